@@ -0,0 +1,187 @@
+package jsonify
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ArrayWriter writes a JSON array to an io.Writer one element at a time, for
+// arrays too large to hold in memory, e.g. multi-gigabyte responses:
+//
+//	aw := jsonify.NewArrayWriter(w)
+//	aw.Begin()
+//	for rows.Next() {
+//		if err := aw.Element(row); err != nil {
+//			return err
+//		}
+//	}
+//	return aw.End()
+//
+// The output is buffered, and written to the io.Writer when the buffer
+// reaches FlushBytes, and by [ArrayWriter.Flush] and [ArrayWriter.End].
+// An ArrayWriter is not safe for concurrent use.
+type ArrayWriter struct {
+	// FlushBytes is the size of the buffered output from which it is
+	// written, 64 KiB if it is 0 or less.
+	FlushBytes int
+
+	c containerWriter
+}
+
+// NewArrayWriter returns an [ArrayWriter] writing to w, encoding the
+// elements with [Bytes] and opts. Indentation options are ignored, and
+// elements are written compactly.
+func NewArrayWriter(w io.Writer, opts ...Option) *ArrayWriter {
+	return &ArrayWriter{c: containerWriter{name: "ArrayWriter", w: w, opts: opts}}
+}
+
+// Begin writes the start of the array.
+func (aw *ArrayWriter) Begin() error {
+	return aw.c.begin('[', aw.FlushBytes)
+}
+
+// Element writes v as the next element of the array. If v cannot be
+// encoded, Element returns the error and writes nothing, so that the array
+// remains valid; errors writing to the io.Writer are returned by every
+// later call.
+func (aw *ArrayWriter) Element(v any) error {
+	if err := aw.c.check("Element"); err != nil {
+		return err
+	}
+	b, err := aw.c.encode(v)
+	if err != nil {
+		return err
+	}
+	return aw.c.add(nil, b, aw.FlushBytes)
+}
+
+// End writes the end of the array, and flushes the output.
+func (aw *ArrayWriter) End() error {
+	return aw.c.end(']')
+}
+
+// Len returns the number of elements written.
+func (aw *ArrayWriter) Len() int {
+	return aw.c.n
+}
+
+// Flush writes the buffered output to the io.Writer, and flushes it if it
+// has a Flush method, e.g. an [http.ResponseWriter] or a [bufio.Writer], so
+// that a client receives the elements written so far.
+func (aw *ArrayWriter) Flush() error {
+	return aw.c.flush()
+}
+
+// containerWriter holds the state of an [ArrayWriter] or an [ObjectWriter].
+type containerWriter struct {
+	name  string
+	w     io.Writer
+	opts  []Option
+	buf   []byte
+	n     int
+	state int
+	err   error
+}
+
+// States of a containerWriter.
+const (
+	containerNotBegun = iota
+	containerOpen
+	containerEnded
+)
+
+func (c *containerWriter) begin(open byte, flushBytes int) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.state != containerNotBegun {
+		return errors.New("jsonify: " + c.name + ".Begin called twice")
+	}
+	c.state = containerOpen
+	c.buf = append(c.buf, open)
+	return c.flushIfFull(flushBytes)
+}
+
+// check reports whether a member can be added by the method.
+func (c *containerWriter) check(method string) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.state != containerOpen {
+		return errors.New("jsonify: " + c.name + "." + method + " called outside of Begin and End")
+	}
+	return nil
+}
+
+// encode encodes v compactly.
+func (c *containerWriter) encode(v any) ([]byte, error) {
+	b, err := Bytes(v, c.opts...)
+	if err == nil && bytes.IndexByte(b, '\n') >= 0 {
+		b, err = Compact(b)
+	}
+	return b, err
+}
+
+// add adds a member: an element, or a key and a value.
+func (c *containerWriter) add(key, value []byte, flushBytes int) error {
+	if c.n > 0 {
+		c.buf = append(c.buf, ',')
+	}
+	if key != nil {
+		c.buf = append(append(c.buf, key...), ':')
+	}
+	c.buf = append(c.buf, value...)
+	c.n++
+	return c.flushIfFull(flushBytes)
+}
+
+func (c *containerWriter) end(closing byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.state != containerOpen {
+		return errors.New("jsonify: " + c.name + ".End called without Begin")
+	}
+	c.state = containerEnded
+	c.buf = append(c.buf, closing)
+	return c.flush()
+}
+
+func (c *containerWriter) flush() error {
+	if c.err != nil {
+		return c.err
+	}
+	if len(c.buf) > 0 {
+		_, c.err = c.w.Write(c.buf)
+		c.buf = c.buf[:0]
+	}
+	if c.err == nil {
+		c.err = flushWriter(c.w)
+	}
+	return c.err
+}
+
+func (c *containerWriter) flushIfFull(flushBytes int) error {
+	if flushBytes <= 0 {
+		flushBytes = 64 << 10
+	}
+	if len(c.buf) < flushBytes {
+		return nil
+	}
+	_, c.err = c.w.Write(c.buf)
+	c.buf = c.buf[:0]
+	return c.err
+}
+
+// flushWriter flushes w if it has a Flush method.
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package jsonify_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleArrayWriter() {
+	aw := jsonify.NewArrayWriter(os.Stdout)
+	aw.Begin()
+	for i := range 3 {
+		aw.Element(map[string]int{"n": i})
+	}
+	aw.End()
+	// Output:
+	// [{"n":0},{"n":1},{"n":2}]
+}
+
+func TestArrayWriter(t *testing.T) {
+	var w countingWriter
+	aw := jsonify.NewArrayWriter(&w, jsonify.WithIndent("", "  "))
+	aw.FlushBytes = 10
+	if err := aw.Element(1); err == nil {
+		t.Error("Element() before Begin() error = nil, want error")
+	}
+	if err := aw.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := aw.Begin(); err == nil {
+		t.Error("Begin() twice error = nil, want error")
+	}
+	if err := aw.Element([]int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes != 0 {
+		t.Errorf("%d writes before FlushBytes, want 0", w.writes)
+	}
+	if err := aw.Element(math.NaN()); err == nil {
+		t.Error("Element(NaN) error = nil, want error")
+	}
+	if err := aw.Element("abcdefgh"); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes != 1 {
+		t.Errorf("%d writes after FlushBytes, want 1", w.writes)
+	}
+	if err := aw.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := aw.Element(1); err == nil {
+		t.Error("Element() after End() error = nil, want error")
+	}
+	if got, want := w.String(), `[[1,2],"abcdefgh"]`; got != want {
+		t.Errorf("ArrayWriter wrote %s, want %s", got, want)
+	}
+	if aw.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", aw.Len())
+	}
+}
+
+func TestArrayWriter_empty(t *testing.T) {
+	var sb strings.Builder
+	aw := jsonify.NewArrayWriter(&sb)
+	if err := aw.End(); err == nil {
+		t.Error("End() without Begin() error = nil, want error")
+	}
+	aw.Begin()
+	if err := aw.End(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != "[]" {
+		t.Errorf("ArrayWriter wrote %s, want []", got)
+	}
+}
+
+func TestArrayWriter_writeError(t *testing.T) {
+	aw := jsonify.NewArrayWriter(errorWriter{})
+	aw.Begin()
+	aw.Element(1)
+	if err := aw.Flush(); !errors.Is(err, errWrite) {
+		t.Fatalf("Flush() error = %v, want %v", err, errWrite)
+	}
+	if err := aw.Element(2); !errors.Is(err, errWrite) {
+		t.Errorf("Element() after a write error = %v, want %v", err, errWrite)
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+type errorWriter struct{}
+
+func (errorWriter) Write([]byte) (int, error) { return 0, errWrite }
@@ -0,0 +1,69 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ForAudience returns a [json.Marshaler] that encodes v with only the struct
+// fields visible to audience, so that the same type can serve, e.g., a
+// public API and internal tooling without hand-built DTOs.
+//
+// A field is restricted to a list of audiences with the aud key of its
+// jsonify tag:
+//
+//	type User struct {
+//		Name  string `json:"name"`
+//		Email string `json:"email" jsonify:"aud=admin,internal"`
+//	}
+//
+// Fields without the aud key are visible to every audience. The filter
+// applies to structs at any depth of v, except inside values that marshal
+// themselves. Encoding v directly, without ForAudience, ignores the aud
+// key.
+//
+// v is always encoded with the [Jsoniter] backend, whatever the backend set
+// with [SetBackend], as the others cannot filter fields.
+func ForAudience(v any, audience string) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) {
+		return marshal(v, func(o *options) {
+			o.mode.audience = audience
+			o.mode.filterAudience = true
+		})
+	})
+}
+
+// jsonifyTag parses the jsonify tag of a struct field, a list of keys with
+// comma-separated values such as "aud=admin,internal". A value without "="
+// belongs to the preceding key; values before the first key, such as
+// "i18n", are flags under the empty key.
+func jsonifyTag(tag reflect.StructTag) map[string][]string {
+	s, ok := tag.Lookup("jsonify")
+	if !ok {
+		return nil
+	}
+	keys := map[string][]string{}
+	key := ""
+	for _, part := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			key = k
+			part = v
+		}
+		keys[key] = append(keys[key], part)
+	}
+	return keys
+}
+
+// filterAudience hides the fields of desc that are not visible to audience.
+func filterAudience(desc *jsoniter.StructDescriptor, audience string) {
+	for _, binding := range desc.Fields {
+		auds, ok := jsonifyTag(binding.Field.Tag())["aud"]
+		if ok && !slices.Contains(auds, audience) {
+			binding.ToNames = []string{}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type member struct {
+	Name  string  `json:"name"`
+	Email string  `json:"email" jsonify:"aud=admin,internal"`
+	Notes string  `json:"notes,omitempty" jsonify:"aud=internal"`
+	Owner *member `json:"owner,omitempty"`
+}
+
+func ExampleForAudience() {
+	type User struct {
+		Name  string `json:"name"`
+		Email string `json:"email" jsonify:"aud=admin,internal"`
+	}
+	u := User{Name: "Ann", Email: "ann@example.com"}
+	fmt.Println(jsonify.MustString(jsonify.ForAudience(u, "public")))
+	fmt.Println(jsonify.MustString(jsonify.ForAudience(u, "admin")))
+	// Output:
+	// {"name":"Ann"}
+	// {"name":"Ann","email":"ann@example.com"}
+}
+
+func TestForAudience(t *testing.T) {
+	a := member{Name: "a", Email: "a@x", Notes: "n", Owner: &member{Name: "b", Email: "b@x"}}
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "public", input: jsonify.ForAudience(a, "public"), expected: `{"name":"a","owner":{"name":"b"}}`},
+		{name: "empty audience", input: jsonify.ForAudience(a, ""), expected: `{"name":"a","owner":{"name":"b"}}`},
+		{name: "admin", input: jsonify.ForAudience(a, "admin"), expected: `{"name":"a","email":"a@x","owner":{"name":"b","email":"b@x"}}`},
+		{name: "internal", input: jsonify.ForAudience(a, "internal"), expected: `{"name":"a","email":"a@x","notes":"n","owner":{"name":"b","email":"b@x"}}`},
+		{name: "nested in map", input: map[string]any{"acct": jsonify.ForAudience([]member{a}, "public")}, expected: `{"acct":[{"name":"a","owner":{"name":"b"}}]}`},
+		{name: "unfiltered", input: member{Name: "a", Email: "a@x"}, expected: `{"name":"a","email":"a@x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestForAudience_backend(t *testing.T) {
+	jsonify.SetBackend(jsonify.Stdlib)
+	defer jsonify.SetBackend(nil)
+	got, err := jsonify.String(jsonify.ForAudience(member{Name: "a", Email: "secret@x"}, "public"))
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+	if want := `{"name":"a"}`; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestForAudience_manyAudiences(t *testing.T) {
+	a := member{Name: "a", Email: "a@x"}
+	for i := range 1000 {
+		audience := fmt.Sprint("aud", i)
+		if i == 999 {
+			audience = "admin"
+		}
+		got, err := jsonify.String(jsonify.ForAudience(a, audience))
+		if err != nil {
+			t.Fatalf("String() error = %v", err)
+		}
+		want := `{"name":"a"}`
+		if audience == "admin" {
+			want = `{"name":"a","email":"a@x"}`
+		}
+		if got != want {
+			t.Errorf("String() of audience %s = %v, want %v", audience, got, want)
+		}
+	}
+}
+
+func TestForAudience_wrappers(t *testing.T) {
+	a := member{Name: "a", Email: "a@x"}
+	some := jsonify.Some(a)
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "Optional", input: jsonify.Some(a), expected: `{"name":"a"}`},
+		{name: "Optional field", input: struct {
+			A jsonify.Optional[member]  `json:"a,omitempty"`
+			B *jsonify.Optional[member] `json:"b"`
+			C jsonify.Optional[member]  `json:"c,omitempty"`
+		}{A: some, B: &some}, expected: `{"a":{"name":"a"},"b":{"name":"a"}}`},
+		{name: "Table", input: jsonify.Table([]member{a}), expected: `{"columns":["name"],"rows":[["a"]]}`},
+		{name: "Sparse", input: jsonify.Sparse[member]{{}, a}, expected: `{"len":2,"entries":{"1":{"name":"a"}}}`},
+		{name: "Set", input: jsonify.NewSet(a), expected: `[{"name":"a"}]`},
+		{name: "Range", input: jsonify.Range[member]{Start: a, End: a}, expected: `{"start":{"name":"a"},"end":{"name":"a"},"bounds":"()"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(jsonify.ForAudience(tt.input, "public"))
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Backend marshals values that jsonify does not handle itself.
+//
+// [json.RawMessage] and [proto.Message] values are always handled by jsonify,
+// regardless of the backend.
+//
+// Implementations must produce compact JSON without a trailing newline, must
+// not escape HTML characters, and must sort map keys so that the output stays
+// consistent across backends.
+type Backend interface {
+	Marshal(v any) ([]byte, error)
+}
+
+var (
+	// Jsoniter is the default backend, using the custom [jsoniter]
+	// configuration described in the package documentation.
+	Jsoniter Backend = jsoniterBackend{}
+
+	// Stdlib is a backend using [encoding/json] with HTML escaping disabled.
+	Stdlib Backend = stdlibBackend{}
+)
+
+var backend = Jsoniter
+
+// SetBackend sets the backend used by [Bytes], [String] and their variants.
+// A nil backend restores the default, [Jsoniter].
+//
+// SetBackend is meant to be called during program initialization; it must not
+// be called concurrently with encoding.
+func SetBackend(b Backend) {
+	if b == nil {
+		b = Jsoniter
+	}
+	backend = b
+}
+
+// optionsBackend is implemented by backends that support [Option].
+type optionsBackend interface {
+	marshalOptions(v any, o *options) ([]byte, error)
+}
+
+type jsoniterBackend struct{}
+
+func (jsoniterBackend) Marshal(v any) ([]byte, error) {
+	return config().Marshal(v)
+}
+
+func (jsoniterBackend) marshalOptions(v any, o *options) ([]byte, error) {
+	api := configFor(o.mode)
+	stream := api.BorrowStream(nil)
+	defer api.ReturnStream(stream)
+	// Encoders find the per-call state, e.g. the translator, in o.
+	stream.Attachment = o
+	stream.WriteVal(v)
+	if o.err != nil {
+		return nil, o.err
+	}
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	return bytes.Clone(stream.Buffer()), nil
+}
+
+type stdlibBackend struct{}
+
+func (stdlibBackend) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,59 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleSetBackend() {
+	jsonify.SetBackend(jsonify.Stdlib)
+	defer jsonify.SetBackend(nil)
+
+	fmt.Println(jsonify.MustString(map[string]any{"B": "<b>", "A": true}))
+	// Output:
+	// {"A":true,"B":"<b>"}
+}
+
+type constBackend struct{}
+
+func (constBackend) Marshal(v any) ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+func TestBackend(t *testing.T) {
+	input := map[string]any{"B": "<b>", "A": []int{1, 2}, "C": nil}
+	expected := `{"A":[1,2],"B":"<b>","C":null}`
+	for name, b := range map[string]jsonify.Backend{
+		"jsoniter": jsonify.Jsoniter,
+		"stdlib":   jsonify.Stdlib,
+	} {
+		t.Run(name, func(t *testing.T) {
+			jsonify.SetBackend(b)
+			defer jsonify.SetBackend(nil)
+			got, err := jsonify.String(input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != expected {
+				t.Errorf("String() = %v, want %v", got, expected)
+			}
+			if _, err := jsonify.Bytes(make(chan int)); err == nil {
+				t.Errorf("Bytes() error = nil, want error")
+			}
+		})
+	}
+
+	t.Run("custom", func(t *testing.T) {
+		jsonify.SetBackend(constBackend{})
+		defer jsonify.SetBackend(nil)
+		if got := jsonify.MustString(1); got != `"custom"` {
+			t.Errorf("MustString() = %v, want %v", got, `"custom"`)
+		}
+		if got := jsonify.MustString(json.RawMessage(`{"raw":true}`)); got != `{"raw":true}` {
+			t.Errorf("MustString() = %v, RawMessage must bypass the backend", got)
+		}
+	})
+}
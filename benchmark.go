@@ -0,0 +1,111 @@
+package jsonify
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// Report is the result of [Benchmark], which encodes with jsonify, e.g. to
+// be stored or compared between releases.
+type Report struct {
+	GoVersion string            `json:"goVersion"`
+	Results   []BenchmarkResult `json:"results"`
+}
+
+// BenchmarkResult is the encoding performance of a value with a backend.
+type BenchmarkResult struct {
+	// Type is the Go type of the value, and Backend the name of the backend:
+	// "jsoniter", "stdlib" or, for the one set by [SetBackend], "custom".
+	Type    string `json:"type"`
+	Backend string `json:"backend"`
+
+	// Size is the length of the encoded value.
+	Size int `json:"size"`
+
+	Iterations      int     `json:"iterations"`
+	NsPerOp         float64 `json:"nsPerOp"`
+	MBPerSec        float64 `json:"mbPerSec"`
+	AllocsPerOp     float64 `json:"allocsPerOp"`
+	AllocBytesPerOp float64 `json:"allocBytesPerOp"`
+
+	// Error is the error of encoding the value with the backend, if any, in
+	// which case the other measurements are zero.
+	Error string `json:"error,omitempty"`
+}
+
+// benchmarkTime is the minimum duration of the measurement of each value
+// with each backend.
+var benchmarkTime = 100 * time.Millisecond
+
+// Benchmark measures the time and allocations of encoding each of values
+// with each available backend, [Jsoniter], [Stdlib] and the one set with
+// [SetBackend] if it is another one, the way [Bytes] does without options.
+// It validates performance claims on your own payloads:
+//
+//	report := jsonify.Benchmark(smallOrder, largeOrder)
+//	b, err := jsonify.Bytes(report, jsonify.WithIndent("", "  "))
+//
+// Each measurement takes about 100ms. The allocations are those of the whole
+// program during the measurement, so other goroutines should be idle.
+func Benchmark(values ...any) Report {
+	backends := []namedBackend{{"jsoniter", Jsoniter}, {"stdlib", Stdlib}}
+	if backend != Jsoniter && backend != Stdlib {
+		backends = append(backends, namedBackend{"custom", backend})
+	}
+	report := Report{GoVersion: runtime.Version(), Results: []BenchmarkResult{}}
+	for _, v := range values {
+		for _, b := range backends {
+			r := benchmark(b.backend, v)
+			r.Type = fmt.Sprintf("%T", v)
+			r.Backend = b.name
+			report.Results = append(report.Results, r)
+		}
+	}
+	return report
+}
+
+type namedBackend struct {
+	name    string
+	backend Backend
+}
+
+// benchmark measures the encoding of v with backend, running it more and
+// more times until a run takes benchmarkTime.
+func benchmark(backend Backend, v any) BenchmarkResult {
+	b, err := newOptions(nil).marshalWith(backend, v)
+	if err != nil {
+		return BenchmarkResult{Error: err.Error()}
+	}
+	run := func(n int) time.Duration {
+		start := time.Now()
+		for range n {
+			newOptions(nil).marshalWith(backend, v)
+		}
+		return time.Since(start)
+	}
+	n := 1
+	for run(n) < benchmarkTime/10 {
+		n *= 2
+	}
+	// Aim at benchmarkTime for the measured run, which follows a warm-up.
+	n = max(n, int(float64(n)*float64(benchmarkTime)/float64(run(n)+1)))
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	elapsed := run(n)
+	runtime.ReadMemStats(&after)
+
+	r := BenchmarkResult{
+		Size:            len(b),
+		Iterations:      n,
+		NsPerOp:         float64(elapsed.Nanoseconds()) / float64(n),
+		AllocsPerOp:     float64(after.Mallocs-before.Mallocs) / float64(n),
+		AllocBytesPerOp: float64(after.TotalAlloc-before.TotalAlloc) / float64(n),
+	}
+	if elapsed > 0 {
+		r.MBPerSec = float64(len(b)) * float64(n) / 1e6 / elapsed.Seconds()
+	}
+	return r
+}
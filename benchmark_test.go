@@ -0,0 +1,37 @@
+package jsonify_test
+
+import (
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func TestBenchmark(t *testing.T) {
+	type order struct {
+		ID    int      `json:"id"`
+		Items []string `json:"items"`
+	}
+	report := jsonify.Benchmark(order{ID: 1, Items: []string{"a", "b"}}, make(chan int))
+	if report.GoVersion == "" {
+		t.Errorf("Benchmark().GoVersion is empty")
+	}
+	if len(report.Results) != 4 {
+		t.Fatalf("Benchmark() has %d results, want 4", len(report.Results))
+	}
+	for _, r := range report.Results[:2] {
+		if r.Type != "jsonify_test.order" || r.Error != "" || r.Size != 26 || r.Iterations == 0 || r.NsPerOp <= 0 || r.AllocsPerOp <= 0 {
+			t.Errorf("Benchmark() result = %+v", r)
+		}
+	}
+	if got := report.Results[0].Backend + "," + report.Results[1].Backend; got != "jsoniter,stdlib" {
+		t.Errorf("Benchmark() backends = %v, want jsoniter,stdlib", got)
+	}
+	for _, r := range report.Results[2:] {
+		if r.Error == "" || r.Iterations != 0 {
+			t.Errorf("Benchmark() result of an unsupported value = %+v, want an error", r)
+		}
+	}
+	if _, err := jsonify.Bytes(report); err != nil {
+		t.Errorf("Bytes() of the report error = %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// CBORBytes returns v encoded as CBOR (RFC 8949), transcoded from its
+// encoding with [Bytes] and opts, so that services can switch between JSON
+// and CBOR per endpoint with one data-shaping layer: the same field names,
+// e.g. json tags and protojson names, the same hooks and redaction, and the
+// same values.
+//
+// Objects become maps with text keys, arrays become arrays, and strings
+// become text strings, all of definite length. Integers become the
+// smallest integer encoding that holds them, and other numbers become
+// single-precision floats if that is exact, or else double-precision ones.
+// As the encoding goes through JSON, byte slices are base64 text, as in
+// JSON, rather than byte strings.
+func CBORBytes(v any, opts ...Option) ([]byte, error) {
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(b) {
+		return nil, errors.New("jsonify: CBORBytes of invalid JSON")
+	}
+	return appendCBOR(nil, bytes.TrimSpace(b))
+}
+
+// DecodeCBOR decodes the CBOR-encoded data into the value pointed to by v,
+// as [Decode] decodes its JSON mapping, so that values encoded with
+// [CBORBytes], and by other CBOR encoders, decode as their JSON would.
+//
+// Byte strings become base64 text, as []byte fields expect, map keys must be
+// text or integers, which become their decimal text, tags are dropped in
+// favour of their content, and undefined becomes null. Indefinite-length
+// items are accepted. NaN and infinite floats, which JSON cannot hold, and
+// data after the first item fail.
+func DecodeCBOR(data []byte, v any, opts ...DecodeOption) error {
+	d := cborDecoder{data: data}
+	b, err := d.value(nil, 0)
+	if err != nil {
+		return err
+	}
+	if d.pos != len(data) {
+		return fmt.Errorf("jsonify: DecodeCBOR: data after the item at offset %d", d.pos)
+	}
+	return Decode(b, v, opts...)
+}
+
+// CBOR major types.
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+	cborSimple = 7 << 5
+)
+
+// appendCBOR appends the JSON value raw, without leading or trailing space,
+// to b as CBOR.
+func appendCBOR(b []byte, raw []byte) ([]byte, error) {
+	switch raw[0] {
+	case '{':
+		keys, values, err := objectMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+		b = appendCBORHead(b, cborMap, uint64(len(keys)))
+		for i, key := range keys {
+			b = append(appendCBORHead(b, cborText, uint64(len(key))), key...)
+			if b, err = appendCBOR(b, values[i]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '[':
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, err
+		}
+		b = appendCBORHead(b, cborArray, uint64(len(elems)))
+		for _, elem := range elems {
+			var err error
+			if b, err = appendCBOR(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '"':
+		s, err := unquote(raw)
+		if err != nil {
+			return nil, err
+		}
+		return append(appendCBORHead(b, cborText, uint64(len(s))), s...), nil
+	case 'n':
+		return append(b, cborSimple|22), nil
+	case 't':
+		return append(b, cborSimple|21), nil
+	case 'f':
+		return append(b, cborSimple|20), nil
+	}
+	s := string(raw)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if i < 0 {
+			return appendCBORHead(b, cborNegInt, uint64(-1-i)), nil
+		}
+		return appendCBORHead(b, cborUint, uint64(i)), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return appendCBORHead(b, cborUint, u), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: CBORBytes of number %s: %w", s, err)
+	}
+	if f32 := float32(f); float64(f32) == f {
+		return binary.BigEndian.AppendUint32(append(b, cborSimple|26), math.Float32bits(f32)), nil
+	}
+	return binary.BigEndian.AppendUint64(append(b, cborSimple|27), math.Float64bits(f)), nil
+}
+
+// appendCBORHead appends the head of an item of the major type with the
+// argument n, in its shortest encoding.
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major|27), n)
+}
+
+// cborDecoder transcodes CBOR to JSON.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// cborBreak is the stop code of indefinite-length items.
+const cborBreak = 0xff
+
+func (d *cborDecoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("jsonify: DecodeCBOR: "+format+" at offset %d", append(args, d.pos)...)
+}
+
+// head reads the head of an item and returns its major type, its additional
+// information and its argument. The argument of indefinite-length items is
+// zero.
+func (d *cborDecoder) head() (major, info byte, n uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, d.errorf("unexpected end of data")
+	}
+	c := d.data[d.pos]
+	major, info = c&0xe0, c&0x1f
+	d.pos++
+	size := 0
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	case info == 31 && major != cborUint && major != cborNegInt && major != cborTag:
+		return major, info, 0, nil
+	default:
+		d.pos--
+		return 0, 0, 0, d.errorf("invalid additional information %d", info)
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, 0, d.errorf("unexpected end of data")
+	}
+	for _, c := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.pos += size
+	return major, info, n, nil
+}
+
+// atBreak reports whether the stop code is next, and consumes it if so.
+func (d *cborDecoder) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// value appends the next item, at the given nesting depth, to b as JSON.
+func (d *cborDecoder) value(b []byte, depth int) ([]byte, error) {
+	if depth >= DefaultMaxDepth {
+		return nil, d.errorf("exceeded max depth %d", DefaultMaxDepth)
+	}
+	start := d.pos
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return strconv.AppendUint(b, n, 10), nil
+	case cborNegInt:
+		if n == math.MaxUint64 {
+			return append(b, "-18446744073709551616"...), nil
+		}
+		return strconv.AppendUint(append(b, '-'), n+1, 10), nil
+	case cborBytes, cborText:
+		s, err := d.str(major, info, n)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborBytes {
+			return appendString(b, base64.StdEncoding.EncodeToString(s)), nil
+		}
+		if !utf8.Valid(s) {
+			d.pos = start
+			return nil, d.errorf("invalid UTF-8 in text string")
+		}
+		return appendString(b, string(s)), nil
+	case cborArray:
+		b = append(b, '[')
+		for i := uint64(0); info == 31 && !d.atBreak() || info != 31 && i < n; i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = d.value(b, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	case cborMap:
+		b = append(b, '{')
+		for i := uint64(0); info == 31 && !d.atBreak() || info != 31 && i < n; i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = d.key(b); err != nil {
+				return nil, err
+			}
+			if b, err = d.value(append(b, ':'), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, '}'), nil
+	case cborTag:
+		return d.value(b, depth+1)
+	}
+	switch info {
+	case 20:
+		return append(b, "false"...), nil
+	case 21:
+		return append(b, "true"...), nil
+	case 22, 23:
+		return append(b, "null"...), nil
+	case 31:
+		d.pos = start
+		return nil, d.errorf("unexpected break")
+	case 25, 26, 27:
+		var f float64
+		bitSize := 64
+		switch info {
+		case 25:
+			f, bitSize = halfToFloat(uint16(n)), 32
+		case 26:
+			f, bitSize = float64(math.Float32frombits(uint32(n))), 32
+		default:
+			f = math.Float64frombits(n)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			d.pos = start
+			return nil, d.errorf("unsupported float %v", f)
+		}
+		return strconv.AppendFloat(b, f, 'g', -1, bitSize), nil
+	}
+	d.pos = start
+	return nil, d.errorf("unsupported simple value %d", n)
+}
+
+// key appends the next item, a map key, to b as a JSON string.
+func (d *cborDecoder) key(b []byte) ([]byte, error) {
+	if d.pos == len(d.data) {
+		return nil, d.errorf("unexpected end of data")
+	}
+	switch d.data[d.pos] & 0xe0 {
+	case cborUint, cborNegInt:
+		b, err := d.value(append(b, '"'), 0)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '"'), nil
+	case cborText:
+		return d.value(b, 0)
+	}
+	return nil, d.errorf("unsupported map key")
+}
+
+// str returns the content of a byte or text string with the given head.
+func (d *cborDecoder) str(major, info byte, n uint64) ([]byte, error) {
+	if info != 31 {
+		if uint64(len(d.data)-d.pos) < n {
+			return nil, d.errorf("unexpected end of data")
+		}
+		s := d.data[d.pos : d.pos+int(n)]
+		d.pos += int(n)
+		return s, nil
+	}
+	// An indefinite-length string is a sequence of definite-length chunks.
+	var s []byte
+	for !d.atBreak() {
+		chunkMajor, chunkInfo, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == 31 {
+			return nil, d.errorf("invalid chunk of indefinite-length string")
+		}
+		chunk, err := d.str(major, chunkInfo, n)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+// halfToFloat returns the value of the IEEE 754 half-precision float h.
+func halfToFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp, mant := int(h>>10&0x1f), float64(h&0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(mant+1024, exp-25)
+}
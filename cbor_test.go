@@ -0,0 +1,154 @@
+package jsonify_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleCBORBytes() {
+	type reading struct {
+		Sensor string  `json:"sensor"`
+		Value  float64 `json:"value"`
+	}
+	b, err := jsonify.CBORBytes(reading{Sensor: "t1", Value: 21.5})
+	fmt.Printf("% x %v\n", b, err)
+
+	var r reading
+	err = jsonify.DecodeCBOR(b, &r)
+	fmt.Printf("%+v %v\n", r, err)
+	// Output:
+	// a2 66 73 65 6e 73 6f 72 62 74 31 65 76 61 6c 75 65 fa 41 ac 00 00 <nil>
+	// {Sensor:t1 Value:21.5} <nil>
+}
+
+func TestCBORBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		want    string // Hex.
+		wantErr bool
+	}{
+		// Examples of RFC 8949, Appendix A.
+		{name: "0", v: 0, want: "00"},
+		{name: "23", v: 23, want: "17"},
+		{name: "24", v: 24, want: "1818"},
+		{name: "1000", v: 1000, want: "1903e8"},
+		{name: "1000000", v: 1000000, want: "1a000f4240"},
+		{name: "1e12", v: int64(1e12), want: "1b000000e8d4a51000"},
+		{name: "max uint64", v: uint64(math.MaxUint64), want: "1bffffffffffffffff"},
+		{name: "-1", v: -1, want: "20"},
+		{name: "-1000", v: -1000, want: "3903e7"},
+		{name: "min int64", v: int64(math.MinInt64), want: "3b7fffffffffffffff"},
+		{name: "1.5", v: 1.5, want: "fa3fc00000"},
+		{name: "1.1", v: 1.1, want: "fb3ff199999999999a"},
+		{name: "false", v: false, want: "f4"},
+		{name: "true", v: true, want: "f5"},
+		{name: "null", v: nil, want: "f6"},
+		{name: "text", v: "ü", want: "62c3bc"},
+		{name: "array", v: []any{1, []int{2, 3}}, want: "8201820203"},
+		{name: "long array", v: make([]int, 25), want: "9819" + strings.Repeat("00", 25)},
+		{name: "map", v: map[string]any{"a": 1, "b": []int{2}}, want: "a2616101616281" + "02"},
+		{name: "raw message", v: json.RawMessage(` {"a" : [ ]} `), want: "a1616180"},
+		{name: "NaN", v: math.NaN(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.CBORBytes(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CBORBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("CBORBytes() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string // Hex.
+		want    string // JSON.
+		wantErr bool
+	}{
+		// Examples of RFC 8949, Appendix A.
+		{name: "uint", data: "1bffffffffffffffff", want: "18446744073709551615"},
+		{name: "negative", data: "3903e7", want: "-1000"},
+		{name: "min negative", data: "3bffffffffffffffff", want: "-18446744073709551616"},
+		{name: "half", data: "f93e00", want: "1.5"},
+		{name: "half subnormal", data: "f90001", want: "5.9604645e-08"},
+		{name: "half negative", data: "f9c400", want: "-4"},
+		{name: "single", data: "fa47c35000", want: "100000"},
+		{name: "double", data: "fb3ff199999999999a", want: "1.1"},
+		{name: "undefined", data: "f7", want: "null"},
+		{name: "bytes", data: "4401020304", want: `"AQIDBA=="`},
+		{name: "text", data: "6449455446", want: `"IETF"`},
+		{name: "tag", data: "c074323031332d30332d32315432303a30343a30305a", want: `"2013-03-21T20:04:00Z"`},
+		{name: "map", data: "a201020304", want: `{"1":2,"3":4}`},
+		{name: "negative key", data: "a120f5", want: `{"-1":true}`},
+		{name: "nested", data: "a26161016162820203", want: `{"a":1,"b":[2,3]}`},
+		{name: "indefinite bytes", data: "5f42010243030405ff", want: `"AQIDBAU="`},
+		{name: "indefinite text", data: "7f657374726561646d696e67ff", want: `"streaming"`},
+		{name: "indefinite array", data: "9f018202039f0405ffff", want: "[1,[2,3],[4,5]]"},
+		{name: "indefinite map", data: "bf61610161629f0203ffff", want: `{"a":1,"b":[2,3]}`},
+		{name: "empty", data: "", wantErr: true},
+		{name: "truncated", data: "1903", wantErr: true},
+		{name: "truncated text", data: "6449", wantErr: true},
+		{name: "trailing data", data: "0000", wantErr: true},
+		{name: "NaN", data: "f97e00", wantErr: true},
+		{name: "infinity", data: "f97c00", wantErr: true},
+		{name: "simple", data: "f0", wantErr: true},
+		{name: "break", data: "ff", wantErr: true},
+		{name: "reserved", data: "1c", wantErr: true},
+		{name: "array key", data: "a18000", wantErr: true},
+		{name: "invalid UTF-8", data: "61ff", wantErr: true},
+		{name: "nested indefinite text", data: "7f7fffff", wantErr: true},
+		{name: "too deep", data: strings.Repeat("81", jsonify.DefaultMaxDepth+1) + "00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got any
+			err = jsonify.DecodeCBOR(data, &got, jsonify.UseNumber())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeCBOR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if s := jsonify.MustString(got); s != tt.want {
+				t.Errorf("DecodeCBOR() = %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCBOR_roundTrip(t *testing.T) {
+	type record struct {
+		ID    int64             `json:"id"`
+		Data  []byte            `json:"data"`
+		Attrs map[string]string `json:"attrs"`
+		Score float64           `json:"score"`
+	}
+	want := record{ID: -1 << 40, Data: []byte{0, 1, 2}, Attrs: map[string]string{"k": "v"}, Score: 0.1}
+	b, err := jsonify.CBORBytes(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got record
+	if err := jsonify.DecodeCBOR(b, &got, jsonify.WithDisallowUnknownFields()); err != nil {
+		t.Fatal(err)
+	}
+	if jsonify.MustString(got) != jsonify.MustString(want) {
+		t.Errorf("DecodeCBOR() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package jsonify
+
+import (
+	"iter"
+	"time"
+)
+
+// FromChannel returns a sequence of the values received from ch until it is
+// closed, which encodes as a JSON array, as any [iter.Seq] does:
+//
+//	results := make(chan Result)
+//	go produce(results) // Closes results when done.
+//	jsonify.Bytes(map[string]any{"results": jsonify.FromChannel(results)})
+//
+// Encoding blocks until ch is closed. Channels themselves cannot be encoded,
+// as receiving from them is not idempotent; nor is encoding the sequence,
+// which drains ch, so it is encoded once.
+func FromChannel[T any](ch <-chan T) iter.Seq[T] {
+	return FromChannelLimit(ch, 0, 0)
+}
+
+// FromChannelLimit is [FromChannel] ending the sequence after maxValues
+// values, or once timeout has passed since the sequence started, if they
+// are positive, whichever comes first. The values left in ch are not
+// received.
+func FromChannelLimit[T any](ch <-chan T, maxValues int, timeout time.Duration) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var deadline <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+		for n := 0; maxValues <= 0 || n < maxValues; n++ {
+			select {
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-deadline:
+				return
+			}
+		}
+	}
+}
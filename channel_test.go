@@ -0,0 +1,66 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleFromChannel() {
+	results := make(chan int)
+	go func() {
+		defer close(results)
+		for i := range 3 {
+			results <- i * i
+		}
+	}()
+	s, err := jsonify.String(map[string]any{"results": jsonify.FromChannel(results)})
+	fmt.Println(s, err)
+	// Output:
+	// {"results":[0,1,4]} <nil>
+}
+
+func TestFromChannelLimit(t *testing.T) {
+	buffered := func(vs ...string) chan string {
+		ch := make(chan string, len(vs))
+		for _, v := range vs {
+			ch <- v
+		}
+		return ch
+	}
+	closed := func(vs ...string) chan string {
+		ch := buffered(vs...)
+		close(ch)
+		return ch
+	}
+	tests := []struct {
+		name    string
+		ch      chan string
+		max     int
+		timeout time.Duration
+		want    string
+		left    int
+	}{
+		{name: "closed", ch: closed("a", "b"), want: `["a","b"]`},
+		{name: "empty", ch: closed(), want: `[]`},
+		{name: "max", ch: closed("a", "b", "c"), max: 2, want: `["a","b"]`, left: 1},
+		{name: "timeout", ch: buffered("a"), timeout: 10 * time.Millisecond, want: `["a"]`},
+		{name: "max before timeout", ch: buffered("a", "b"), max: 1, timeout: time.Hour, want: `["a"]`, left: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(jsonify.FromChannelLimit(tt.ch, tt.max, tt.timeout))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if len(tt.ch) != tt.left {
+				t.Errorf("%d values left in the channel, want %d", len(tt.ch), tt.left)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Jsonify-gen generates [jsonify.FieldTable] variables for flat struct
+// types, so that hot types are decoded without reflection and without
+// writing their tables by hand:
+//
+//	//go:generate go run github.com/goaux/jsonify/cmd/jsonify-gen -type Event,Click
+//
+// For each type, it declares a variable named after it, e.g. eventFieldTable
+// for Event, holding a table of its exported fields named by their json
+// tags, as [jsonify.FieldTableOf] builds at run time. The fields must be of
+// the kinds a [jsonify.TableField] supports, directly or through a type
+// declared in the same package, e.g. type Code uint8.
+//
+// The types are read from the package in the current directory, or in the
+// directory given as argument, and the tables are written to the file given
+// by -output, by default <type>_fieldtable.go for the first type.
+//
+// [jsonify.FieldTable]: https://pkg.go.dev/github.com/goaux/jsonify#FieldTable
+// [jsonify.FieldTableOf]: https://pkg.go.dev/github.com/goaux/jsonify#FieldTableOf
+// [jsonify.TableField]: https://pkg.go.dev/github.com/goaux/jsonify#TableField
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <dir>/<type>_fieldtable.go")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: jsonify-gen -type T[,T...] [-output file] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_fieldtable.go")
+	}
+	src, err := generate(dir, types, filepath.Base(*output))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jsonify-gen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "jsonify-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the source of the tables of types, declared in the
+// package in dir. The file named output, if in dir, is not read, as it is
+// the one being regenerated.
+func generate(dir string, types []string, output string) ([]byte, error) {
+	pkg, decls, err := parseDir(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jsonify-gen -type %s; DO NOT EDIT.\n\n", strings.Join(types, ","))
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import (\n\t\"reflect\"\n\t\"unsafe\"\n\n\t\"github.com/goaux/jsonify\"\n)\n")
+	for _, name := range types {
+		spec, ok := decls[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct type", name)
+		}
+		if spec.TypeParams != nil {
+			return nil, fmt.Errorf("type %s is generic", name)
+		}
+		fields, err := tableFields(name, st, decls)
+		if err != nil {
+			return nil, err
+		}
+		table := tableName(name)
+		fmt.Fprintf(&buf, "\n// %s is the [jsonify.FieldTable] of %s.\n", table, name)
+		fmt.Fprintf(&buf, "var %s = jsonify.NewFieldTable[%s](\n", table, name)
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "\tjsonify.TableField{Name: %s, Offset: unsafe.Offsetof(%s{}.%s), Kind: reflect.%s},\n",
+				strconv.Quote(f.name), name, f.field, kindName(f.kind))
+		}
+		buf.WriteString(")\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parseDir returns the name of the package in dir, and its type
+// declarations by name.
+func parseDir(dir, output string) (string, map[string]*ast.TypeSpec, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	decls := map[string]*ast.TypeSpec{}
+	for _, name := range bp.GoFiles {
+		if name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					decls[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+	return bp.Name, decls, nil
+}
+
+type tableField struct {
+	name  string // the JSON name
+	field string // the Go name
+	kind  reflect.Kind
+}
+
+// tableFields returns the fields of the table of the struct type name, as
+// [jsonify.FieldTableOf] selects them.
+func tableFields(name string, st *ast.StructType, decls map[string]*ast.TypeSpec) ([]tableField, error) {
+	var fields []tableField
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("type %s has an embedded field, which a FieldTable does not support", name)
+		}
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s)
+		}
+		jsonTag := tag.Get("json")
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		for _, ident := range f.Names {
+			if !ident.IsExported() || jsonTag == "-" {
+				continue
+			}
+			kind, err := kindOf(f.Type, decls, 0)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %w", name, ident.Name, err)
+			}
+			n := jsonName
+			if n == "" {
+				n = ident.Name
+			}
+			fields = append(fields, tableField{name: n, field: ident.Name, kind: kind})
+		}
+	}
+	return fields, nil
+}
+
+var basicKinds = map[string]reflect.Kind{
+	"bool":    reflect.Bool,
+	"string":  reflect.String,
+	"int":     reflect.Int,
+	"int8":    reflect.Int8,
+	"int16":   reflect.Int16,
+	"int32":   reflect.Int32,
+	"rune":    reflect.Int32,
+	"int64":   reflect.Int64,
+	"uint":    reflect.Uint,
+	"uint8":   reflect.Uint8,
+	"byte":    reflect.Uint8,
+	"uint16":  reflect.Uint16,
+	"uint32":  reflect.Uint32,
+	"uint64":  reflect.Uint64,
+	"uintptr": reflect.Uintptr,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
+}
+
+var errUnsupported = errors.New("type is not supported by a FieldTable")
+
+// kindOf returns the kind of the type expr, resolving the types declared in
+// the package with decls.
+func kindOf(expr ast.Expr, decls map[string]*ast.TypeSpec, depth int) (reflect.Kind, error) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || depth > len(decls) {
+		return 0, errUnsupported
+	}
+	if spec, ok := decls[ident.Name]; ok {
+		if spec.TypeParams != nil {
+			return 0, errUnsupported
+		}
+		return kindOf(spec.Type, decls, depth+1)
+	}
+	if kind, ok := basicKinds[ident.Name]; ok {
+		return kind, nil
+	}
+	return 0, errUnsupported
+}
+
+// kindName returns the name of the reflect constant of k, e.g. Int64.
+func kindName(k reflect.Kind) string {
+	s := k.String()
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// tableName returns the name of the table variable of the type name, e.g.
+// eventFieldTable for Event.
+func tableName(name string) string {
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[n:] + "FieldTable"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	got, err := generate("testdata/events", []string{"Event", "Click"}, "event_fieldtable.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "events", "event_fieldtable.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerate_errors(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{typ: "Nested", want: "field Nested.Tags: type is not supported by a FieldTable"},
+		{typ: "Embedding", want: "type Embedding has an embedded field, which a FieldTable does not support"},
+		{typ: "NotStruct", want: "type NotStruct is not a struct type"},
+		{typ: "Missing", want: "type Missing not found in testdata/events"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			_, err := generate("testdata/events", []string{tt.typ}, "x.go")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+// Code is declared in the package, so its kind is resolved.
+type Code uint8
+
+type Event struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Score  float64 `json:"score,omitempty"`
+	Active bool    `json:"active"`
+	Code   Code    `json:"code"`
+	Ratio  float32
+	X, Y   int
+	Skip   int `json:"-"`
+	Dash   int `json:"-,"`
+	secret string
+}
+
+type Click struct {
+	At  int64  `json:"at"`
+	URL string `json:"url"`
+}
+
+type Nested struct {
+	Tags []string `json:"tags"`
+}
+
+type Embedding struct {
+	Click
+}
+
+type NotStruct int
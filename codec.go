@@ -0,0 +1,56 @@
+package jsonify
+
+import "io"
+
+// Codec compresses the output of [Encode], e.g. with zstd, brotli or snappy,
+// through an implementation of the format provided by the caller. [Gzip] is
+// the codec of the gzip format.
+type Codec interface {
+	// NewWriter returns a writer compressing what is written to it to w,
+	// until it is closed.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+// CodecFunc adapts a function to a [Codec]:
+//
+//	zstdCodec := jsonify.CodecFunc(func(w io.Writer) (io.WriteCloser, error) {
+//		return zstd.NewWriter(w)
+//	})
+type CodecFunc func(w io.Writer) (io.WriteCloser, error)
+
+// NewWriter returns f(w).
+func (f CodecFunc) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return f(w)
+}
+
+// WithCompression returns an [Option] that compresses the output of
+// [Encode], and of the functions writing with it, e.g. [Reader], with
+// codec, so that a storage layer can write values uniformly whatever the
+// compression:
+//
+//	err := jsonify.Encode(file, snapshot, jsonify.WithCompression(jsonify.Gzip))
+//
+// Functions returning the output, e.g. [Bytes], ignore it. The limit of
+// [WithMaxOutputBytes] applies to the output before it is compressed.
+func WithCompression(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// compress calls write with w, or with the writer of o.codec compressing to
+// w, which it closes.
+func (o *options) compress(w io.Writer, write func(w io.Writer) error) error {
+	if o.codec == nil {
+		return write(w)
+	}
+	cw, err := o.codec.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	if err := write(cw); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
@@ -0,0 +1,94 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithCompression() {
+	var buf bytes.Buffer
+	err := jsonify.Encode(&buf, map[string]int{"a": 1}, jsonify.WithCompression(jsonify.Gzip))
+	fmt.Println(err)
+	fmt.Println(gunzipString(buf.Bytes()))
+	// Output:
+	// <nil>
+	// {"a":1}
+}
+
+// deflate is a Codec provided by the caller.
+var deflate = jsonify.CodecFunc(func(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.BestSpeed)
+})
+
+func inflate(t *testing.T, b []byte) string {
+	t.Helper()
+	out, err := io.ReadAll(flate.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestWithCompression(t *testing.T) {
+	v := map[string]any{"items": strings.Split(strings.Repeat("item,", 10_000), ",")}
+	want := jsonify.MustString(v)
+
+	var buf bytes.Buffer
+	if err := jsonify.Encode(&buf, v, jsonify.WithCompression(deflate)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() >= len(want) {
+		t.Errorf("Encode() wrote %d bytes, want fewer than %d", buf.Len(), len(want))
+	}
+	if got := inflate(t, buf.Bytes()); got != want {
+		t.Errorf("Encode() wrote %d bytes once inflated, want %d", len(got), len(want))
+	}
+
+	b, err := io.ReadAll(jsonify.Reader(v, jsonify.WithCompression(deflate)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inflate(t, b); got != want {
+		t.Errorf("Reader() read %d bytes once inflated, want %d", len(got), len(want))
+	}
+
+	indented := jsonify.MustString(v, jsonify.WithIndent("", " "))
+	b, err = io.ReadAll(jsonify.Reader(v, jsonify.WithIndent("", " "), jsonify.WithCompression(deflate)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inflate(t, b); got != indented {
+		t.Errorf("Reader() with WithIndent read %d bytes once inflated, want %d", len(got), len(indented))
+	}
+
+	if b, err := jsonify.Bytes(v, jsonify.WithCompression(deflate)); err != nil || string(b) != want {
+		t.Errorf("Bytes() with WithCompression = %d bytes, %v, want the output uncompressed", len(b), err)
+	}
+}
+
+func TestWithCompression_errors(t *testing.T) {
+	errCodec := errors.New("codec failed")
+	failing := jsonify.CodecFunc(func(io.Writer) (io.WriteCloser, error) { return nil, errCodec })
+	if err := jsonify.Encode(io.Discard, 1, jsonify.WithCompression(failing)); !errors.Is(err, errCodec) {
+		t.Errorf("Encode() error = %v, want %v", err, errCodec)
+	}
+	if err := jsonify.Encode(io.Discard, math.NaN(), jsonify.WithCompression(jsonify.Gzip)); err == nil {
+		t.Error("Encode(NaN) error = nil, want error")
+	}
+	big := make([]string, 10_000)
+	for i := range big {
+		big[i] = fmt.Sprint(i)
+	}
+	err := jsonify.Encode(io.Discard, big, jsonify.WithCompression(jsonify.Gzip), jsonify.WithMaxOutputBytes(1000))
+	if !errors.Is(err, jsonify.ErrOutputTooLarge) {
+		t.Errorf("Encode() error = %v, want %v", err, jsonify.ErrOutputTooLarge)
+	}
+}
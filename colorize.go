@@ -0,0 +1,102 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+// ANSI colors of [ColorizeJSON].
+const (
+	colorKey    = "\x1b[34;1m" // Bold blue.
+	colorString = "\x1b[32m"   // Green.
+	colorNumber = "\x1b[36m"   // Cyan.
+	colorBool   = "\x1b[33m"   // Yellow.
+	colorNull   = "\x1b[90m"   // Gray.
+	colorReset  = "\x1b[0m"
+)
+
+// Colorize writes v encoded with opts to w, indented by two spaces, and
+// followed by a newline, for the JSON output of command-line tools:
+//
+//	if *output == "json" {
+//		return jsonify.Colorize(os.Stdout, result)
+//	}
+//
+// The output is colored with ANSI escape codes, as by [ColorizeJSON], only
+// if w is a terminal and the NO_COLOR environment variable is not set, so
+// that it can be piped to other tools as is. Options given, e.g.
+// [WithSmartIndent], override the indentation.
+func Colorize(w io.Writer, v any, opts ...Option) error {
+	b, err := Bytes(v, noTrailingNewline(append([]Option{WithIndent("", "  ")}, opts...))...)
+	if err != nil {
+		return err
+	}
+	if isColorTerminal(w) {
+		if b, err = ColorizeJSON(b); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// isColorTerminal reports whether w is a terminal that colors are written
+// to.
+func isColorTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorizeJSON returns data, JSON text, with ANSI escape codes coloring
+// keys bold blue, strings green, numbers cyan, booleans yellow and null
+// gray. Spaces and punctuation are kept as they are.
+func ColorizeJSON(data []byte) ([]byte, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("jsonify: ColorizeJSON of invalid JSON")
+	}
+	b := make([]byte, 0, len(data)*2)
+	for i := 0; i < len(data); {
+		c := data[i]
+		var color string
+		end := i + 1
+		switch {
+		case c == '"':
+			end = scanString(data, i)
+			color = colorString
+			if j := skipSpace(data, end); j < len(data) && data[j] == ':' {
+				color = colorKey
+			}
+		case c == 't' || c == 'f':
+			end = i + len("true")
+			if c == 'f' {
+				end = i + len("false")
+			}
+			color = colorBool
+		case c == 'n':
+			end = i + len("null")
+			color = colorNull
+		case c == '-' || '0' <= c && c <= '9':
+			for end < len(data) && isNumberByte(data[end]) {
+				end++
+			}
+			color = colorNumber
+		}
+		if color == "" {
+			b = append(b, c)
+		} else {
+			b = append(append(append(b, color...), data[i:end]...), colorReset...)
+		}
+		i = end
+	}
+	return b, nil
+}
+
+func isNumberByte(c byte) bool {
+	return '0' <= c && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
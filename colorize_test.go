@@ -0,0 +1,96 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleColorize() {
+	// os.Stdout is not a terminal here, so the output is not colored.
+	jsonify.Colorize(os.Stdout, map[string]any{"name": "Ann", "tags": []string{"a"}})
+	// Output:
+	// {
+	//   "name": "Ann",
+	//   "tags": [
+	//     "a"
+	//   ]
+	// }
+}
+
+func ExampleColorizeJSON() {
+	b, _ := jsonify.ColorizeJSON([]byte(`{"id": 1, "ok": true}`))
+	fmt.Printf("%q\n", b)
+	// Output:
+	// "{\x1b[34;1m\"id\"\x1b[0m: \x1b[36m1\x1b[0m, \x1b[34;1m\"ok\"\x1b[0m: \x1b[33mtrue\x1b[0m}"
+}
+
+func TestColorizeJSON(t *testing.T) {
+	const (
+		key  = "\x1b[34;1m"
+		str  = "\x1b[32m"
+		num  = "\x1b[36m"
+		boo  = "\x1b[33m"
+		null = "\x1b[90m"
+		end  = "\x1b[0m"
+	)
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "scalars", data: `[-1.5e3, "s", false, null]`, want: "[" + num + "-1.5e3" + end + ", " + str + `"s"` + end + ", " + boo + "false" + end + ", " + null + "null" + end + "]"},
+		{name: "escaped quote", data: `{"a\"b" : "c\\"}`, want: "{" + key + `"a\"b"` + end + " : " + str + `"c\\"` + end + "}"},
+		{name: "nested", data: "{\n  \"a\": {}\n}", want: "{\n  " + key + `"a"` + end + ": {}\n}"},
+		{name: "invalid", data: `{"a":}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.ColorizeJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ColorizeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("ColorizeJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jsonify.Colorize(&buf, []int{1}, jsonify.WithSmartIndent(80)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[1]\n" {
+		t.Errorf("Colorize() with WithSmartIndent wrote %q", buf.String())
+	}
+
+	// A pipe is not a terminal.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := jsonify.Colorize(w, map[string]bool{"a": true}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if string(out) != "{\n  \"a\": true\n}\n" {
+		t.Errorf("Colorize() to a pipe wrote %q", out)
+	}
+
+	if err := jsonify.Colorize(&buf, make(chan int)); err == nil {
+		t.Error("Colorize(chan) error = nil, want error")
+	}
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("Colorize() wrote colors to a buffer: %q", buf.String())
+	}
+}
@@ -0,0 +1,85 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// ColumnChunkSize is the number of rows in each column chunk written by
+// [Columns].
+const ColumnChunkSize = 1024
+
+// Columns writes the fields cols of rows to w in a columnar layout, as
+// newline-delimited JSON with one line per column chunk:
+//
+//	{"column":"id","offset":0,"values":[1,2,3]}
+//	{"column":"name","offset":0,"values":["Ann","Bob","Cy"]}
+//
+// Rows are read in chunks of [ColumnChunkSize]; offset is the index of the
+// first row of the chunk. Each chunk has a line for every column, in the
+// order of cols. An empty sequence writes nothing.
+//
+// Each row, a struct or a map, is encoded with jsonify first, so cols are
+// the JSON names of the fields. A row without a column has null in that
+// column.
+func Columns[T any](w io.Writer, rows iter.Seq[T], cols []string) error {
+	index := make(map[string]int, len(cols))
+	for i, col := range cols {
+		index[col] = i
+	}
+	chunk := make([][]json.RawMessage, len(cols))
+	offset, n := 0, 0
+	flush := func() error {
+		var b []byte
+		for i, col := range cols {
+			b = append(b, `{"column":`...)
+			b = appendString(b, col)
+			b = append(b, `,"offset":`...)
+			b = strconv.AppendInt(b, int64(offset), 10)
+			b = append(b, `,"values":[`...)
+			for j, v := range chunk[i] {
+				if j > 0 {
+					b = append(b, ',')
+				}
+				b = append(b, v...)
+			}
+			b = append(b, "]}\n"...)
+			chunk[i] = chunk[i][:0]
+		}
+		offset += n
+		n = 0
+		_, err := w.Write(b)
+		return err
+	}
+	for row := range rows {
+		b, err := marshal(row)
+		if err != nil {
+			return err
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return fmt.Errorf("jsonify: Columns row %d: %w", offset+n, err)
+		}
+		for i := range chunk {
+			chunk[i] = append(chunk[i], json.RawMessage("null"))
+		}
+		for k, key := range keys {
+			if i, ok := index[key]; ok {
+				chunk[i][n] = values[k]
+			}
+		}
+		n++
+		if n == ColumnChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if n > 0 {
+		return flush()
+	}
+	return nil
+}
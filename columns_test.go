@@ -0,0 +1,65 @@
+package jsonify_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleColumns() {
+	type user struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	users := []user{{1, "Ann", "ann@example.com"}, {2, "Bob", "bob@example.com"}}
+
+	jsonify.Columns(os.Stdout, slices.Values(users), []string{"id", "name"})
+	// Output:
+	// {"column":"id","offset":0,"values":[1,2]}
+	// {"column":"name","offset":0,"values":["Ann","Bob"]}
+}
+
+func TestColumns(t *testing.T) {
+	rows := make([]map[string]any, jsonify.ColumnChunkSize+1)
+	for i := range rows {
+		rows[i] = map[string]any{"n": i}
+	}
+	rows[1] = map[string]any{}
+
+	var sb strings.Builder
+	if err := jsonify.Columns(&sb, slices.Values(rows), []string{"n"}); err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Columns() wrote %d lines, want 2", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], `{"column":"n","offset":0,"values":[0,null,2,`) {
+		t.Errorf("Columns() first chunk = %.60s", lines[0])
+	}
+	if lines[1] != `{"column":"n","offset":1024,"values":[1024]}` {
+		t.Errorf("Columns() second chunk = %v", lines[1])
+	}
+
+	sb.Reset()
+	if err := jsonify.Columns(&sb, slices.Values([]map[string]int{}), []string{"n"}); err != nil || sb.Len() != 0 {
+		t.Errorf("Columns() of no rows = %q, %v", sb.String(), err)
+	}
+	if err := jsonify.Columns(&sb, slices.Values([]int{1}), []string{"n"}); err == nil {
+		t.Errorf("Columns() of non-object rows error = nil, want error")
+	}
+	if err := jsonify.Columns(failingWriter{}, slices.Values(rows), []string{"n"}); err == nil {
+		t.Errorf("Columns() write error = nil, want error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
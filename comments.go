@@ -0,0 +1,90 @@
+package jsonify
+
+import "fmt"
+
+// WithComments returns a [DecodeOption] that accepts JSONC-style input, as
+// in hand-edited configuration files: // line comments, /* block */
+// comments, and trailing commas before the end of an object or an array:
+//
+//	{
+//		// The address to listen on.
+//		"addr": ":8080",
+//		"tags": ["a", "b",], /* trailing comma */
+//	}
+//
+// They are replaced with spaces before the input is decoded, so that the
+// offsets in errors are those of the input. Other JSON5 extensions, e.g.
+// unquoted keys, are not accepted, and encoding is not affected.
+func WithComments() DecodeOption {
+	return func(o *decodeOptions) {
+		o.comments = true
+	}
+}
+
+// StripComments returns data, JSON with the comments and trailing commas
+// accepted by [WithComments], as strict JSON, with them replaced with
+// spaces. It fails only for an unterminated block comment; other errors are
+// left to the decoder.
+func StripComments(data []byte) ([]byte, error) {
+	var out []byte
+	blank := func(from, to int) {
+		if out == nil {
+			out = append([]byte(nil), data...)
+		}
+		for i := from; i < to; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+	comma := -1   // The offset of a comma that may be trailing.
+	var prev byte // The last byte of the last token.
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			comma = -1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			start := i
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			blank(start, i)
+			continue
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			start := i
+			end := -1
+			for j := i + 2; j+1 < len(data); j++ {
+				if data[j] == '*' && data[j+1] == '/' {
+					end = j + 2
+					break
+				}
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("jsonify: unterminated comment at offset %d", start)
+			}
+			blank(start, end)
+			i = end - 1
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case c == ',' && prev != ',' && prev != '[' && prev != '{':
+			comma = i
+		case (c == '}' || c == ']') && comma >= 0:
+			blank(comma, comma+1)
+			comma = -1
+		default:
+			comma = -1
+		}
+		prev = c
+	}
+	if out == nil {
+		return data, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,76 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithComments() {
+	type config struct {
+		Addr string   `json:"addr"`
+		Tags []string `json:"tags"`
+	}
+	data := []byte(`{
+		// The address to listen on.
+		"addr": ":8080",
+		"tags": ["a", "b",], /* trailing comma */
+	}`)
+	var c config
+	err := jsonify.Decode(data, &c, jsonify.WithComments())
+	fmt.Printf("%+v %v\n", c, err)
+	// Output:
+	// {Addr::8080 Tags:[a b]} <nil>
+}
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "strict", data: `{"a":[1,2]}`, want: `{"a":[1,2]}`},
+		{name: "line comment", data: "[1, // one\n2]", want: "[1,       \n2]"},
+		{name: "line comment at end", data: "1 // one", want: "1       "},
+		{name: "block comment", data: "[1/* a\nb */,2]", want: "[1    \n    ,2]"},
+		{name: "trailing commas", data: `{"a":[1,2,],}`, want: `{"a":[1,2 ] }`},
+		{name: "comma before comment", data: "[1, /* x */\n]", want: "[1         \n]"},
+		{name: "comments in strings", data: `{"a//b":"/*c*/","d":"\"//"}`, want: `{"a//b":"/*c*/","d":"\"//"}`},
+		{name: "leading comma", data: `[,]`, want: `[,]`},
+		{name: "double comma", data: `[1,,]`, want: `[1,,]`},
+		{name: "unterminated comment", data: "[1 /* x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.StripComments([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StripComments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("StripComments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithComments(t *testing.T) {
+	var v map[string]any
+	if err := jsonify.Decode([]byte(`{"a":1,}`), &v); err == nil {
+		t.Error("Decode() of a trailing comma without WithComments error = nil, want error")
+	}
+	if err := jsonify.Decode([]byte(`{"a":1,} // x`), &v, jsonify.WithComments()); err != nil || v["a"] != 1.0 {
+		t.Errorf("Decode() = %v, %v", v, err)
+	}
+	data := []byte(`{"a":1, /* x */ "a":2}`)
+	if err := jsonify.Decode(data, &v, jsonify.WithComments(), jsonify.WithRejectDuplicateKeys()); err == nil {
+		t.Error("Decode() of a duplicate key error = nil, want error")
+	}
+	if err := jsonify.Decode([]byte(`[1,,]`), &v, jsonify.WithComments()); err == nil {
+		t.Error("Decode() of a double comma error = nil, want error")
+	}
+	if string(data) != `{"a":1, /* x */ "a":2}` {
+		t.Errorf("Decode() modified its input to %s", data)
+	}
+}
@@ -0,0 +1,175 @@
+package jsonify
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+var jsoniterConfig = jsoniter.Config{
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+}
+
+// frozen holds the current *configs built from jsoniterConfig and the
+// registry.
+//
+// jsoniter caches the encoder of a type once it has been created, so the
+// configurations are rebuilt from scratch whenever the registry changes.
+var frozen atomic.Value
+
+func init() {
+	frozen.Store(&configs{ext: registry.ext.clone()})
+}
+
+// configs holds a jsoniter configuration per mode, created on first use.
+type configs struct {
+	ext  *extension
+	apis sync.Map // map[mode]jsoniter.API
+	n    atomic.Int64
+}
+
+// maxConfigs bounds the number of configurations kept by [configFor]. Modes
+// hold caller-chosen strings, e.g. the audience of [ForAudience], so a
+// program must not be able to grow the cache without limit.
+const maxConfigs = 256
+
+// config returns the current jsoniter configuration of the default mode.
+func config() jsoniter.API {
+	return configFor(mode{})
+}
+
+// configFor returns the current jsoniter configuration of m.
+func configFor(m mode) jsoniter.API {
+	c := frozen.Load().(*configs)
+	if api, ok := c.apis.Load(m); ok {
+		return api.(jsoniter.API)
+	}
+	if c.n.Load() >= maxConfigs {
+		// Beyond the limit, configurations are built for the call only.
+		return newConfig(c.ext, m)
+	}
+	api, loaded := c.apis.LoadOrStore(m, newConfig(c.ext, m))
+	if !loaded {
+		c.n.Add(1)
+	}
+	return api.(jsoniter.API)
+}
+
+func newConfig(ext *extension, m mode) jsoniter.API {
+	cfg := jsoniterConfig
+	cfg.UseNumber = m.useNumber
+	cfg.DisallowUnknownFields = m.disallowUnknown
+	cfg.CaseSensitive = m.caseSensitive
+	api := cfg.Froze()
+	api.RegisterExtension(ext)
+	api.RegisterExtension(&modeExtension{mode: m, ext: ext})
+	return api
+}
+
+var registry struct {
+	sync.Mutex
+	ext *extension
+}
+
+// update applies fn to a copy of the registered extension and rebuilds the
+// jsoniter configuration with it.
+func update(fn func(ext *extension)) {
+	registry.Lock()
+	defer registry.Unlock()
+	ext := registry.ext.clone()
+	fn(ext)
+	registry.ext = ext
+	frozen.Store(&configs{ext: ext})
+}
+
+// register installs the encoder and decoder used for typ by the jsoniter
+// configuration. A nil encoder or decoder leaves the current one in place.
+func register(typ reflect.Type, enc jsoniter.ValEncoder, dec jsoniter.ValDecoder) {
+	update(func(ext *extension) {
+		if enc != nil {
+			ext.encoders[typ] = enc
+		}
+		if dec != nil {
+			ext.decoders[typ] = dec
+		}
+	})
+}
+
+// extension is the jsoniter extension carrying the registered encoders,
+// decoders and field hooks. It is immutable once installed.
+type extension struct {
+	jsoniter.DummyExtension
+	encoders   map[reflect.Type]jsoniter.ValEncoder
+	decoders   map[reflect.Type]jsoniter.ValDecoder
+	fieldHooks map[reflect.Type]map[string]func(any) any
+	views      map[reflect.Type]map[string][]string
+}
+
+func (ext *extension) clone() *extension {
+	c := &extension{
+		encoders:   map[reflect.Type]jsoniter.ValEncoder{},
+		decoders:   map[reflect.Type]jsoniter.ValDecoder{},
+		fieldHooks: map[reflect.Type]map[string]func(any) any{},
+		views:      map[reflect.Type]map[string][]string{},
+	}
+	if ext == nil {
+		return c
+	}
+	for t, e := range ext.encoders {
+		c.encoders[t] = e
+	}
+	for t, d := range ext.decoders {
+		c.decoders[t] = d
+	}
+	for t, hooks := range ext.fieldHooks {
+		c.fieldHooks[t] = hooks
+	}
+	for t, views := range ext.views {
+		c.views[t] = views
+	}
+	return c
+}
+
+// encoderFactories create encoders for families of types, such as
+// [Optional]. They are consulted in order after the registered encoders.
+var encoderFactories []func(typ reflect2.Type) jsoniter.ValEncoder
+
+func (ext *extension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if enc := ext.encoders[typ.Type1()]; enc != nil {
+		return enc
+	}
+	for _, factory := range encoderFactories {
+		if enc := factory(typ); enc != nil {
+			return enc
+		}
+	}
+	return nil
+}
+
+// encoderDecorators wrap the encoders of families of types, such as
+// [BeforeJSONifier] implementations. They are applied in order.
+var encoderDecorators []func(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder
+
+func (ext *extension) DecorateEncoder(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder {
+	for _, decorate := range encoderDecorators {
+		enc = decorate(typ, enc)
+	}
+	return enc
+}
+
+func (ext *extension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	return ext.decoders[typ.Type1()]
+}
+
+func (ext *extension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
+	hooks := ext.fieldHooks[desc.Type.Type1()]
+	for _, binding := range desc.Fields {
+		if fn, ok := hooks[binding.Field.Name()]; ok {
+			binding.Encoder = &fieldHookEncoder{binding.Field.Type(), fn, binding.Encoder}
+		}
+	}
+}
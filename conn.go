@@ -0,0 +1,150 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Conn is a minimal request/response protocol over a stream socket, such as
+// a unix control socket, built on [Frame] and [ReadFrame].
+//
+// Each request is a frame holding {"id":N,"method":"...","params":...}, and
+// each response a frame holding {"id":N,"result":...} or {"id":N,"error":"..."}.
+// The same Conn can be used to [Conn.Call] a peer or to [Conn.Serve] one, but
+// not both at the same time.
+type Conn struct {
+	conn net.Conn
+	mu   sync.Mutex
+	id   uint64
+}
+
+// NewConn returns a Conn that exchanges messages over conn.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn}
+}
+
+// Close closes the underlying connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// CallError is the error returned by [Conn.Call] when the handler of the
+// peer fails.
+type CallError struct {
+	Method  string
+	Message string
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("jsonify: call %s: %s", e.Method, e.Message)
+}
+
+// Handler handles the params of a request and returns its result.
+// Use [Handle] to define one with typed params.
+type Handler func(params json.RawMessage) (any, error)
+
+// Handle returns a [Handler] that decodes the params into P with [Decode]
+// before calling fn.
+func Handle[P, R any](fn func(params P) (R, error)) Handler {
+	return func(data json.RawMessage) (any, error) {
+		var params P
+		if len(data) > 0 {
+			if err := Decode(data, &params); err != nil {
+				return nil, err
+			}
+		}
+		return fn(params)
+	}
+}
+
+type connRequest struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type connResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Call sends a request for method with params, waits for the response, and
+// decodes its result into result unless result is nil.
+//
+// Calls from multiple goroutines are sent one at a time. If the handler of
+// the peer fails, Call returns a [*CallError].
+func (c *Conn) Call(method string, params, result any) error {
+	var raw json.RawMessage
+	if params != nil {
+		b, err := Bytes(params)
+		if err != nil {
+			return err
+		}
+		raw = b
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.id++
+	if err := Frame(c.conn, connRequest{ID: c.id, Method: method, Params: raw}); err != nil {
+		return err
+	}
+	payload, err := ReadFrame(c.conn)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	var resp connResponse
+	if err := Decode(payload, &resp); err != nil {
+		return err
+	}
+	if resp.ID != c.id {
+		return fmt.Errorf("jsonify: response id %d, want %d", resp.ID, c.id)
+	}
+	if resp.Error != "" {
+		return &CallError{Method: method, Message: resp.Error}
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return Decode(resp.Result, result)
+}
+
+// Serve reads requests from the connection and answers each with the
+// handler registered for its method, one at a time, until the peer closes
+// the connection, in which case it returns nil.
+//
+// A request for an unknown method, or whose handler fails, is answered with
+// an error; only failures of the connection itself stop Serve.
+func (c *Conn) Serve(handlers map[string]Handler) error {
+	for {
+		payload, err := ReadFrame(c.conn)
+		if err != nil {
+			if err == io.EOF || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		var req connRequest
+		if err := Decode(payload, &req); err != nil {
+			return fmt.Errorf("jsonify: invalid request: %w", err)
+		}
+		resp := connResponse{ID: req.ID}
+		if h, ok := handlers[req.Method]; !ok {
+			resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+		} else if result, err := h(req.Params); err != nil {
+			resp.Error = err.Error()
+		} else if resp.Result, err = Bytes(result); err != nil {
+			resp.Error = err.Error()
+		}
+		if err := Frame(c.conn, resp); err != nil {
+			return err
+		}
+	}
+}
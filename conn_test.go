@@ -0,0 +1,63 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleConn() {
+	client, server := net.Pipe()
+	go jsonify.NewConn(server).Serve(map[string]jsonify.Handler{
+		"upper": jsonify.Handle(func(s string) (string, error) {
+			return strings.ToUpper(s), nil
+		}),
+	})
+
+	conn := jsonify.NewConn(client)
+	defer conn.Close()
+	var out string
+	err := conn.Call("upper", "hello", &out)
+	fmt.Println(out, err)
+	// Output:
+	// HELLO <nil>
+}
+
+func TestConn(t *testing.T) {
+	type sum struct{ A, B int }
+	client, server := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- jsonify.NewConn(server).Serve(map[string]jsonify.Handler{
+			"add": jsonify.Handle(func(p sum) (int, error) { return p.A + p.B, nil }),
+			"fail": jsonify.Handle(func(struct{}) (any, error) {
+				return nil, errors.New("boom")
+			}),
+		})
+	}()
+	conn := jsonify.NewConn(client)
+
+	var n int
+	if err := conn.Call("add", sum{A: 1, B: 2}, &n); err != nil || n != 3 {
+		t.Errorf("Call(add) = %v, %v", n, err)
+	}
+	var callErr *jsonify.CallError
+	if err := conn.Call("fail", nil, nil); !errors.As(err, &callErr) || callErr.Message != "boom" {
+		t.Errorf("Call(fail) error = %v", err)
+	}
+	if err := conn.Call("missing", nil, nil); !errors.As(err, &callErr) || callErr.Method != "missing" {
+		t.Errorf("Call(missing) error = %v", err)
+	}
+	if err := conn.Call("add", "x", &n); err == nil {
+		t.Errorf("Call(add) with invalid params error = nil, want error")
+	}
+
+	conn.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
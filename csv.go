@@ -0,0 +1,144 @@
+package jsonify
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSVOption configures [ToCSV].
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	columns  []string
+	comma    rune
+	noHeader bool
+	opts     []Option
+}
+
+// WithCSVColumns returns a [CSVOption] that writes the columns cols, the
+// JSON names of fields, in that order, rather than those of the first row.
+func WithCSVColumns(cols ...string) CSVOption {
+	return func(o *csvOptions) {
+		o.columns = cols
+	}
+}
+
+// WithCSVComma returns a [CSVOption] that separates the fields with comma,
+// e.g. '\t', rather than ','.
+func WithCSVComma(comma rune) CSVOption {
+	return func(o *csvOptions) {
+		o.comma = comma
+	}
+}
+
+// WithoutCSVHeader returns a [CSVOption] that omits the header record of
+// column names.
+func WithoutCSVHeader() CSVOption {
+	return func(o *csvOptions) {
+		o.noHeader = true
+	}
+}
+
+// WithCSVEncodeOptions returns a [CSVOption] that encodes the rows with
+// opts, e.g. [WithAudience] to select the fields.
+func WithCSVEncodeOptions(opts ...Option) CSVOption {
+	return func(o *csvOptions) {
+		o.opts = opts
+	}
+}
+
+// ToCSV writes v, a slice, an array or an [iter.Seq] of rows, to w as CSV,
+// e.g. as the twin of a JSON export:
+//
+//	jsonify.ToCSV(w, users)
+//	// id,name,email
+//	// 1,Ann,ann@example.com
+//
+// Each row, a struct, a map or a protobuf message, is encoded with [Bytes]
+// first, so that the columns are named by the rules of the JSON encoding,
+// e.g. json tags and protojson field names. The columns are the members of
+// the first row, unless given by [WithCSVColumns]; a row without a column
+// has an empty field, and members of other rows that are not columns are
+// dropped.
+//
+// Strings are written as is, null as an empty field, and other values,
+// including objects and arrays, as their JSON text. The header record of
+// column names is written first, unless [WithoutCSVHeader] is given; an
+// empty v writes it only if the columns are given.
+func ToCSV(w io.Writer, v any, opts ...CSVOption) error {
+	o := csvOptions{comma: ','}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = o.comma
+	cols := o.columns
+	var index map[string]int
+	begin := func() error {
+		index = make(map[string]int, len(cols))
+		for i, col := range cols {
+			index[col] = i
+		}
+		if o.noHeader {
+			return nil
+		}
+		return cw.Write(cols)
+	}
+	if cols != nil {
+		if err := begin(); err != nil {
+			return err
+		}
+	}
+	n := 0
+	err := eachElement("ToCSV", v, func(row any) error {
+		b, err := Bytes(row, o.opts...)
+		if err != nil {
+			return err
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return fmt.Errorf("jsonify: ToCSV row %d: %w", n, err)
+		}
+		if index == nil {
+			cols = keys
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		record := make([]string, len(cols))
+		for k, key := range keys {
+			i, ok := index[key]
+			if !ok {
+				continue
+			}
+			if record[i], err = csvField(values[k]); err != nil {
+				return fmt.Errorf("jsonify: ToCSV row %d: %w", n, err)
+			}
+		}
+		n++
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvField returns the CSV field of the JSON value raw.
+func csvField(raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	switch raw[0] {
+	case '"':
+		return unquote(raw)
+	case 'n':
+		return "", nil
+	case '{', '[':
+		b, err := Compact(raw)
+		return string(b), err
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,104 @@
+package jsonify_test
+
+import (
+	"errors"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleToCSV() {
+	type user struct {
+		ID    int      `json:"id"`
+		Name  string   `json:"name"`
+		Email string   `json:"email,omitempty"`
+		Tags  []string `json:"tags"`
+	}
+	users := []user{
+		{ID: 1, Name: "Ann", Email: "ann@example.com", Tags: []string{"admin"}},
+		{ID: 2, Name: "Bob, Jr."},
+	}
+	jsonify.ToCSV(os.Stdout, users)
+	// Output:
+	// id,name,email,tags
+	// 1,Ann,ann@example.com,"[""admin""]"
+	// 2,"Bob, Jr.",,
+}
+
+func TestToCSV(t *testing.T) {
+	type row struct {
+		A string  `json:"a"`
+		B float64 `json:"b"`
+		C any     `json:"c,omitempty"`
+	}
+	rows := []row{{A: "x", B: 1.5, C: map[string]int{"k": 1}}, {A: "line\nbreak", B: 2}}
+	tests := []struct {
+		name    string
+		v       any
+		opts    []jsonify.CSVOption
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "structs",
+			v:    rows,
+			want: "a,b,c\nx,1.5,\"{\"\"k\"\":1}\"\n\"line\nbreak\",2,\n",
+		},
+		{
+			name: "maps",
+			v:    []map[string]any{{"b": true, "a": nil}, {"a": "y", "z": 1}},
+			want: "a,b\n,true\ny,\n",
+		},
+		{
+			name: "columns",
+			v:    rows,
+			opts: []jsonify.CSVOption{jsonify.WithCSVColumns("b", "missing", "a")},
+			want: "b,missing,a\n1.5,,x\n2,,\"line\nbreak\"\n",
+		},
+		{
+			name: "tabs without header",
+			v:    rows[:1],
+			opts: []jsonify.CSVOption{jsonify.WithCSVComma('\t'), jsonify.WithoutCSVHeader()},
+			want: "x\t1.5\t\"{\"\"k\"\":1}\"\n",
+		},
+		{
+			name: "encode options",
+			v:    []map[string]int64{{"n": 1 << 60}},
+			opts: []jsonify.CSVOption{jsonify.WithCSVEncodeOptions(jsonify.WithInt64(jsonify.Int64AsString))},
+			want: "n\n1152921504606846976\n",
+		},
+		{
+			name: "sequence",
+			v:    iter.Seq[any](slices.Values([]any{map[string]int{"n": 1}})),
+			want: "n\n1\n",
+		},
+		{name: "empty", v: []row{}, want: ""},
+		{
+			name: "empty with columns",
+			v:    []row{},
+			opts: []jsonify.CSVOption{jsonify.WithCSVColumns("a")},
+			want: "a\n",
+		},
+		{name: "not rows", v: 1, wantErr: true},
+		{name: "not objects", v: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			err := jsonify.ToCSV(&sb, tt.v, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && sb.String() != tt.want {
+				t.Errorf("ToCSV() wrote %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+	if err := jsonify.ToCSV(errorWriter{}, rows); !errors.Is(err, errWrite) {
+		t.Errorf("ToCSV() error = %v, want %v", err, errWrite)
+	}
+}
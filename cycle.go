@@ -0,0 +1,75 @@
+package jsonify
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// startDetectingCyclesAfter is the nesting depth of pointers, maps and
+// slices after which encoding starts tracking the values being encoded, as
+// in encoding/json. Shallower values cannot be part of a cycle that would
+// overflow the stack, so the common case pays no tracking cost.
+const startDetectingCyclesAfter = 1000
+
+// cycleKey identifies a pointer, map or slice being encoded.
+type cycleKey struct {
+	ptr unsafe.Pointer
+	len int
+}
+
+func init() {
+	encoderDecorators = append(encoderDecorators, decorateCycleDetection)
+}
+
+func decorateCycleDetection(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		return &cycleEncoder{typ: typ, next: enc}
+	}
+	return enc
+}
+
+// cycleEncoder fails the encoding, instead of recursing until the stack
+// overflows, if a value contains itself.
+type cycleEncoder struct {
+	typ  reflect2.Type
+	next jsoniter.ValEncoder
+}
+
+func (e *cycleEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+func (e *cycleEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	o, _ := stream.Attachment.(*options)
+	if o == nil {
+		e.next.Encode(ptr, stream)
+		return
+	}
+	o.depth++
+	if o.depth <= startDetectingCyclesAfter {
+		e.next.Encode(ptr, stream)
+		o.depth--
+		return
+	}
+	key := cycleKey{ptr: *(*unsafe.Pointer)(ptr)}
+	if e.typ.Kind() == reflect.Slice {
+		key.len = e.typ.(reflect2.SliceType).UnsafeLengthOf(ptr)
+	}
+	if _, ok := o.visited[key]; ok && key.ptr != nil {
+		o.fail(stream, fmt.Errorf("jsonify: encountered a cycle via %v", e.typ))
+		o.depth--
+		return
+	}
+	if o.visited == nil {
+		o.visited = map[cycleKey]struct{}{}
+	}
+	o.visited[key] = struct{}{}
+	e.next.Encode(ptr, stream)
+	delete(o.visited, key)
+	o.depth--
+}
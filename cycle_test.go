@@ -0,0 +1,69 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type treeNode struct {
+	Name     string      `json:"name"`
+	Parent   *treeNode   `json:"parent,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+func ExampleBytes_cycle() {
+	root := &treeNode{Name: "root"}
+	root.Children = []*treeNode{{Name: "child", Parent: root}}
+
+	_, err := jsonify.Bytes(root)
+	fmt.Println(err)
+	// Output:
+	// jsonify: encountered a cycle via []*jsonify_test.treeNode
+}
+
+func TestCycleDetection(t *testing.T) {
+	self := &treeNode{Name: "self"}
+	self.Parent = self
+
+	m := map[string]any{}
+	m["m"] = m
+
+	s := []any{nil}
+	s[0] = s
+
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{name: "pointer", input: self},
+		{name: "map", input: m},
+		{name: "slice", input: s},
+		{name: "nested", input: map[string]any{"x": []any{self}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jsonify.Bytes(tt.input)
+			if err == nil || !strings.Contains(err.Error(), "cycle") {
+				t.Errorf("Bytes() error = %v, want a cycle error", err)
+			}
+		})
+	}
+
+	// A deep chain without a cycle still encodes.
+	var chain *treeNode
+	for i := 0; i < 3000; i++ {
+		chain = &treeNode{Parent: chain}
+	}
+	if _, err := jsonify.Bytes(chain); err != nil {
+		t.Errorf("Bytes() of a deep chain error = %v", err)
+	}
+
+	// The same value may appear twice without forming a cycle.
+	shared := &treeNode{Name: "shared"}
+	if got := jsonify.MustString([]*treeNode{shared, shared}); got != `[{"name":"shared"},{"name":"shared"}]` {
+		t.Errorf("MustString() = %v", got)
+	}
+}
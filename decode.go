@@ -0,0 +1,85 @@
+package jsonify
+
+import "encoding/json"
+
+// Decode decodes the JSON-encoded data and stores the result in the value
+// pointed to by v.
+//
+// It uses the same [jsoniter] configuration as encoding, so that types
+// registered with, e.g., [Enum] decode consistently with how they encode.
+// The decoding can be configured with opts, e.g. [UseNumber].
+//
+// If v is a [proto.Message], it is decoded with [protojson], so messages
+// encoded with [Bytes] decode back without importing protojson. Unknown
+// fields are ignored, as with the DiscardUnknown option of protojson and as
+// they are for other types, unless [WithDisallowUnknownFields] is given.
+func Decode(data []byte, v any, opts ...DecodeOption) error {
+	o := &decodeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.comments {
+		var err error
+		if data, err = StripComments(data); err != nil {
+			return err
+		}
+	}
+	if o.rejectDuplicateKeys && json.Valid(data) {
+		// Invalid input is reported by the decoder below.
+		if _, err := checkDuplicateKeys(data, skipSpace(data, 0)); err != nil {
+			return err
+		}
+	}
+	if ok, err := unmarshalProto(data, v, o); ok {
+		return err
+	}
+	return configFor(o.mode).Unmarshal(data, v)
+}
+
+// DecodeOption configures a call of [Decode].
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	mode mode
+
+	rejectDuplicateKeys bool
+
+	// maxBodyBytes is set by WithMaxBodyBytes, for ReadJSON.
+	maxBodyBytes int64
+
+	// comments is set by WithComments.
+	comments bool
+
+	proto protoDecodeOptions
+}
+
+// UseNumber returns a [DecodeOption] that decodes numbers into an
+// interface{} as a [json.Number] instead of a float64.
+//
+// A json.Number keeps the text of the number, so that, e.g.,
+// 0.30000000000000004 and integers beyond 2^53 encode back unchanged.
+func UseNumber() DecodeOption {
+	return func(o *decodeOptions) {
+		o.mode.useNumber = true
+	}
+}
+
+// WithDisallowUnknownFields returns a [DecodeOption] that makes decoding
+// fail if an object has a key that does not match any field of the struct
+// or [proto.Message] it is decoded into, e.g. to catch typos in
+// configuration files.
+func WithDisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.mode.disallowUnknown = true
+	}
+}
+
+// WithCaseSensitive returns a [DecodeOption] that matches object keys to
+// struct fields by exact name. By default, as in [encoding/json], a key also
+// matches a field whose name differs only in case, e.g. "ADMIN" matches a
+// field tagged "admin".
+func WithCaseSensitive() DecodeOption {
+	return func(o *decodeOptions) {
+		o.mode.caseSensitive = true
+	}
+}
@@ -0,0 +1,102 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleUseNumber() {
+	var v map[string]any
+	jsonify.Decode([]byte(`{"id":9007199254740993,"ratio":0.30000000000000004}`), &v, jsonify.UseNumber())
+	fmt.Printf("%T\n", v["id"])
+	fmt.Println(jsonify.MustString(v))
+	// Output:
+	// json.Number
+	// {"id":9007199254740993,"ratio":0.30000000000000004}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []jsonify.DecodeOption
+		expected any
+	}{
+		{name: "float", input: `[1.0,2]`, expected: []any{1.0, 2.0}},
+		{name: "number", input: `[1.0,2]`, opts: []jsonify.DecodeOption{jsonify.UseNumber()}, expected: []any{json.Number("1.0"), json.Number("2")}},
+		{name: "nested", input: `{"a":{"b":[1e3]}}`, opts: []jsonify.DecodeOption{jsonify.UseNumber()}, expected: map[string]any{"a": map[string]any{"b": []any{json.Number("1e3")}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			if err := jsonify.Decode([]byte(tt.input), &got, tt.opts...); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func ExampleWithDisallowUnknownFields() {
+	var config struct {
+		Port int `json:"port"`
+	}
+	err := jsonify.Decode([]byte(`{"prot":8080}`), &config, jsonify.WithDisallowUnknownFields())
+	fmt.Println(err != nil)
+	// Output:
+	// true
+}
+
+func TestWithDisallowUnknownFields(t *testing.T) {
+	type inner struct {
+		A int `json:"a"`
+	}
+	var v struct {
+		Inner inner `json:"inner"`
+	}
+	if err := jsonify.Decode([]byte(`{"inner":{"a":1,"b":2}}`), &v); err != nil {
+		t.Errorf("Decode() error = %v", err)
+	}
+	if err := jsonify.Decode([]byte(`{"inner":{"a":1,"b":2}}`), &v, jsonify.WithDisallowUnknownFields()); err == nil {
+		t.Errorf("Decode() of a nested unknown field error = nil, want error")
+	}
+	if err := jsonify.Decode([]byte(`{"inner":{"a":1}}`), &v, jsonify.WithDisallowUnknownFields()); err != nil || v.Inner.A != 1 {
+		t.Errorf("Decode() = %v, %v", v, err)
+	}
+}
+
+func TestWithCaseSensitive(t *testing.T) {
+	type role struct {
+		Admin bool `json:"admin"`
+	}
+	tests := []struct {
+		name     string
+		input    string
+		opts     []jsonify.DecodeOption
+		expected bool
+		wantErr  bool
+	}{
+		{name: "insensitive", input: `{"ADMIN":true}`, expected: true},
+		{name: "sensitive", input: `{"ADMIN":true}`, opts: []jsonify.DecodeOption{jsonify.WithCaseSensitive()}, expected: false},
+		{name: "sensitive exact", input: `{"admin":true}`, opts: []jsonify.DecodeOption{jsonify.WithCaseSensitive()}, expected: true},
+		{name: "sensitive strict", input: `{"Admin":true}`, opts: []jsonify.DecodeOption{jsonify.WithCaseSensitive(), jsonify.WithDisallowUnknownFields()}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got role
+			err := jsonify.Decode([]byte(tt.input), &got, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Admin != tt.expected {
+				t.Errorf("Decode() = %v, want %v", got.Admin, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// The well-known types, which descriptor sets may depend on without
+	// including them.
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Descriptors holds the message types of a FileDescriptorSet loaded at
+// runtime, for tools that have no generated code for the messages they
+// handle. Its messages are [dynamicpb.Message] values, which encode with
+// [Bytes] like generated messages do:
+//
+//	d, err := jsonify.LoadDescriptorSet(data)
+//	m, err := d.Decode("example.User", []byte(`{"name":"Ann"}`))
+//	b, err := jsonify.Bytes(m, jsonify.WithProtoResolver(d.Types()))
+//
+// Pass [Descriptors.Types] to [WithProtoResolver] so that google.protobuf.Any
+// messages of the loaded types are expanded too.
+//
+// Descriptors is not available with the jsonify_noproto build tag.
+type Descriptors struct {
+	files *protoregistry.Files
+	types *dynamicpb.Types
+}
+
+// LoadDescriptorSet loads a FileDescriptorSet in the protobuf binary format,
+// as written by protoc --descriptor_set_out or buf build -o. The set must
+// include the dependencies of its files, e.g. with protoc
+// --include_imports, except for the well-known types and other files linked
+// into the program.
+func LoadDescriptorSet(data []byte) (*Descriptors, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jsonify: invalid descriptor set: %w", err)
+	}
+	files, err := newFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: invalid descriptor set: %w", err)
+	}
+	return &Descriptors{files: files, types: dynamicpb.NewTypes(files)}, nil
+}
+
+// newFiles is [protodesc.NewFiles] falling back to
+// [protoregistry.GlobalFiles] for the dependencies missing from set.
+func newFiles(set *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
+	included := map[string]bool{}
+	for _, f := range set.GetFile() {
+		included[f.GetName()] = true
+	}
+	set = proto.Clone(set).(*descriptorpb.FileDescriptorSet)
+	for i := 0; i < len(set.File); i++ {
+		for _, dep := range set.File[i].GetDependency() {
+			if included[dep] {
+				continue
+			}
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if err != nil {
+				return nil, fmt.Errorf("missing dependency %q of %q", dep, set.File[i].GetName())
+			}
+			included[dep] = true
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+		}
+	}
+	return protodesc.NewFiles(set)
+}
+
+// Types returns the resolver of the loaded types, to use with
+// [WithProtoResolver].
+func (d *Descriptors) Types() *dynamicpb.Types {
+	return d.types
+}
+
+// New returns an empty message of the type with the given full name, e.g.
+// "example.User".
+func (d *Descriptors) New(name string) (*dynamicpb.Message, error) {
+	desc, err := d.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: unknown message type %q", name)
+	}
+	md, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("jsonify: %q is not a message type", name)
+	}
+	return dynamicpb.NewMessage(md), nil
+}
+
+// Decode decodes data into a new message of the type with the given full
+// name, like [Decode] does, resolving google.protobuf.Any messages with the
+// loaded types.
+func (d *Descriptors) Decode(name string, data []byte, opts ...DecodeOption) (*dynamicpb.Message, error) {
+	m, err := d.New(name)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts[:len(opts):len(opts)], func(o *decodeOptions) {
+		o.proto.resolver = d.types
+	})
+	if err := Decode(data, m, opts...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
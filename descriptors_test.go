@@ -0,0 +1,106 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// userDescriptorSet returns a descriptor set of example.User, which is not
+// linked into the test binary.
+func userDescriptorSet(deps ...string) []byte {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       proto.String("example/user.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: deps,
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("created", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				field("manager", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any"),
+			},
+		}},
+	}}}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func ExampleLoadDescriptorSet() {
+	d, err := jsonify.LoadDescriptorSet(userDescriptorSet("google/protobuf/timestamp.proto", "google/protobuf/any.proto"))
+	if err != nil {
+		panic(err)
+	}
+	m, err := d.Decode("example.User", []byte(`{"name":"Ann","created":"2024-01-02T03:04:05Z"}`))
+	if err != nil {
+		panic(err)
+	}
+	s, err := jsonify.String(map[string]any{"user": m})
+	fmt.Println(s, err)
+	// Output:
+	// {"user":{"name":"Ann","created":"2024-01-02T03:04:05Z"}} <nil>
+}
+
+func TestDescriptors(t *testing.T) {
+	d, err := jsonify.LoadDescriptorSet(userDescriptorSet("google/protobuf/timestamp.proto", "google/protobuf/any.proto"))
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet() error = %v", err)
+	}
+
+	data := `{"name":"Ann","manager":{"@type":"type.googleapis.com/example.User","name":"Bob"}}`
+	m, err := d.Decode("example.User", []byte(data))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if _, err := jsonify.String(m); err == nil {
+		t.Errorf("String() of an Any of a loaded type without WithProtoResolver error = nil, want error")
+	}
+	if got, err := jsonify.String(m, jsonify.WithProtoResolver(d.Types())); err != nil || got != data {
+		t.Errorf("String() = %v, %v, want %v", got, err, data)
+	}
+
+	if _, err := d.Decode("example.User", []byte(`{"bogus":1}`)); err != nil {
+		t.Errorf("Decode() with an unknown field error = %v", err)
+	}
+	if _, err := d.Decode("example.User", []byte(`{"bogus":1}`), jsonify.WithDisallowUnknownFields()); err == nil {
+		t.Errorf("Decode() with WithDisallowUnknownFields() error = nil, want error")
+	}
+	if _, err := d.New("example.Missing"); err == nil {
+		t.Errorf("New() of an unknown type error = nil, want error")
+	}
+	if _, err := d.New("example"); err == nil {
+		t.Errorf("New() of a package error = nil, want error")
+	}
+
+	for name, data := range map[string][]byte{
+		"malformed":          []byte("\xff"),
+		"missing dependency": userDescriptorSet("example/missing.proto"),
+		"unresolved type":    userDescriptorSet(),
+	} {
+		if _, err := jsonify.LoadDescriptorSet(data); err == nil {
+			t.Errorf("LoadDescriptorSet() of a %s set error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,522 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Document is a JSON or JSONC document that can be edited in place while
+// keeping its comments, whitespace and key order, e.g. a user's
+// configuration file.
+//
+// JSONC is JSON with // and /* */ comments and trailing commas in objects
+// and arrays.
+//
+// Values are addressed by JSON Pointers (RFC 6901), such as "/server/port".
+// Edits change only the text of the affected value or member; new members
+// are inserted after the last member with its indentation, and new values
+// are indented to match.
+type Document struct {
+	src  []byte
+	root *docValue
+}
+
+// docValue is the position of a value in the source of a Document.
+type docValue struct {
+	start, end int
+	kind       byte // '{' or '[' for containers, 0 for scalars
+	members    []docMember
+}
+
+// docMember is a member of an object or an element of an array.
+type docMember struct {
+	key    string // decoded key; empty for array elements
+	start  int    // start of the key, or of the value for array elements
+	keyEnd int
+	value  *docValue
+	comma  int // position of the following comma, or -1
+}
+
+// LoadDocument parses data, a JSON or JSONC document.
+func LoadDocument(data []byte) (*Document, error) {
+	src := bytes.Clone(data)
+	root, err := parseDocument(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{src: src, root: root}, nil
+}
+
+// Bytes returns the text of d, including its comments.
+func (d *Document) Bytes() []byte {
+	return bytes.Clone(d.src)
+}
+
+// Get returns the value at path as compact JSON, without comments.
+func (d *Document) Get(path string) (json.RawMessage, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	v, err := d.lookup(tokens)
+	if err != nil {
+		return nil, err
+	}
+	return d.appendCompact(nil, v), nil
+}
+
+// Set sets the value at path to v, encoded with jsonify.
+//
+// An existing value is replaced. A missing member of an existing object is
+// added, and so is an element of an array at index "-" or at the index equal
+// to the length of the array. The parent of path must exist.
+func (d *Document) Set(path string, v any) error {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	b, err := marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return d.apply(docEdit{d.root.start, d.root.end, d.indentValue(b, "")})
+	}
+	parent, err := d.lookup(tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.kind {
+	case '{':
+		if k := parent.memberIndex(last); k >= 0 {
+			return d.replace(parent.members[k], b)
+		}
+		return d.insert(parent, func(indent string) string {
+			sep := ":"
+			if d.multiline() {
+				sep = ": "
+			}
+			if n := len(parent.members); n > 0 {
+				m := parent.members[n-1]
+				if s := string(d.src[m.keyEnd:m.value.start]); !strings.Contains(s, "/") {
+					sep = s
+				}
+			}
+			return string(appendString(nil, last)) + sep + d.indentValue(b, indent)
+		})
+	case '[':
+		k, err := parent.elementIndex(last, true)
+		if err != nil {
+			return fmt.Errorf("jsonify: cannot set %q: %w", path, err)
+		}
+		if k < len(parent.members) {
+			return d.replace(parent.members[k], b)
+		}
+		return d.insert(parent, func(indent string) string {
+			return d.indentValue(b, indent)
+		})
+	}
+	return fmt.Errorf("jsonify: cannot set %q: parent is not an object or array", path)
+}
+
+// Delete removes the member or element at path, along with the comments
+// preceding it and on the rest of its line.
+func (d *Document) Delete(path string) error {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("jsonify: cannot delete the document root")
+	}
+	parent, err := d.lookup(tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+	last := tokens[len(tokens)-1]
+	k := -1
+	switch parent.kind {
+	case '{':
+		k = parent.memberIndex(last)
+	case '[':
+		k, _ = parent.elementIndex(last, false)
+	}
+	if k < 0 {
+		return fmt.Errorf("jsonify: cannot delete %q: not found", path)
+	}
+	m := parent.members[k]
+	start := sameLineEnd(d.src, parent.start+1)
+	if k > 0 {
+		start = sameLineEnd(d.src, parent.members[k-1].comma+1)
+	}
+	if m.comma >= 0 {
+		return d.apply(docEdit{start, sameLineEnd(d.src, m.comma+1), ""})
+	}
+	end := sameLineEnd(d.src, m.value.end)
+	if k > 0 {
+		// Without a trailing comma, the comma of the previous member goes.
+		prev := parent.members[k-1].comma
+		return d.apply(docEdit{prev, prev + 1, ""}, docEdit{start, end, ""})
+	}
+	return d.apply(docEdit{start, end, ""})
+}
+
+// replace replaces the value of m with b.
+func (d *Document) replace(m docMember, b []byte) error {
+	return d.apply(docEdit{m.value.start, m.value.end, d.indentValue(b, lineIndent(d.src, m.start))})
+}
+
+// insert adds a member to c, which is built by member given the
+// indentation of its line.
+func (d *Document) insert(c *docValue, member func(indent string) string) error {
+	if n := len(c.members); n > 0 {
+		m := c.members[n-1]
+		ws := string(d.src[trimSpaceLeft(d.src, m.start):m.start])
+		if n > 1 {
+			// Follow the spacing between the last two members, e.g. ", ".
+			if s := d.src[c.members[n-2].comma+1 : m.start]; !bytes.Contains(s, []byte("/")) {
+				ws = string(s)
+			}
+		}
+		text := member(lineIndent(d.src, m.start))
+		end := m.value.end
+		if m.comma >= 0 {
+			end = m.comma + 1
+		}
+		p := sameLineEnd(d.src, end)
+		if bytes.Contains(d.src[end:p], []byte("//")) && !strings.Contains(ws, "\n") {
+			// The line ends with a comment, so the member goes on the next.
+			ws = "\n" + lineIndent(d.src, m.start)
+		}
+		if m.comma >= 0 {
+			return d.apply(docEdit{p, p, ws + text + ","})
+		}
+		return d.apply(docEdit{end, end, ","}, docEdit{p, p, ws + text})
+	}
+	if !d.multiline() {
+		return d.apply(docEdit{c.start + 1, c.start + 1, member("")})
+	}
+	outer := lineIndent(d.src, c.start)
+	inner := outer + d.indentUnit()
+	if len(bytes.TrimSpace(d.src[c.start+1:c.end-1])) == 0 {
+		return d.apply(docEdit{c.start + 1, c.end - 1, "\n" + inner + member(inner) + "\n" + outer})
+	}
+	return d.apply(docEdit{c.start + 1, c.start + 1, "\n" + inner + member(inner)})
+}
+
+// docEdit replaces src[start:end] with text.
+type docEdit struct {
+	start, end int
+	text       string
+}
+
+// apply applies edits, which must be in order and must not overlap, and
+// parses the result.
+func (d *Document) apply(edits ...docEdit) error {
+	src := bytes.Clone(d.src)
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		src = append(src[:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+	root, err := parseDocument(src)
+	if err != nil {
+		return err
+	}
+	d.src, d.root = src, root
+	return nil
+}
+
+func (d *Document) multiline() bool {
+	return bytes.Contains(bytes.TrimSpace(d.src), []byte("\n"))
+}
+
+// indentUnit returns the indentation of the first member of the root, or
+// two spaces.
+func (d *Document) indentUnit() string {
+	if len(d.root.members) > 0 {
+		if unit := lineIndent(d.src, d.root.members[0].start); unit != "" {
+			return unit
+		}
+	}
+	return "  "
+}
+
+// indentValue formats the compact value b for a line indented by indent.
+func (d *Document) indentValue(b []byte, indent string) string {
+	if !d.multiline() {
+		return string(b)
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, indent, d.indentUnit()); err != nil {
+		return string(b)
+	}
+	return buf.String()
+}
+
+func (d *Document) lookup(tokens []string) (*docValue, error) {
+	v := d.root
+	for i, token := range tokens {
+		k := -1
+		switch v.kind {
+		case '{':
+			k = v.memberIndex(token)
+		case '[':
+			k, _ = v.elementIndex(token, false)
+		}
+		if k < 0 {
+			return nil, fmt.Errorf("jsonify: path %q not found", formatPointer(tokens[:i+1]))
+		}
+		v = v.members[k].value
+	}
+	return v, nil
+}
+
+// memberIndex returns the index of the last member named key, or -1.
+func (v *docValue) memberIndex(key string) int {
+	for i := len(v.members) - 1; i >= 0; i-- {
+		if v.members[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// elementIndex returns the array index denoted by token, or -1. The index
+// past the last element, or "-", is accepted if end is true.
+func (v *docValue) elementIndex(token string, end bool) (int, error) {
+	n := len(v.members)
+	if token == "-" && end {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > n || i == n && !end || token != strconv.Itoa(i) {
+		return -1, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+// appendCompact appends v as compact JSON without comments to b.
+func (d *Document) appendCompact(b []byte, v *docValue) []byte {
+	if v.kind == 0 {
+		return append(b, d.src[v.start:v.end]...)
+	}
+	b = append(b, v.kind)
+	for i, m := range v.members {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if v.kind == '{' {
+			b = append(b, d.src[m.start:m.keyEnd]...)
+			b = append(b, ':')
+		}
+		b = d.appendCompact(b, m.value)
+	}
+	return append(b, d.src[v.end-1])
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if path[0] != '/' {
+		return nil, fmt.Errorf("jsonify: invalid JSON pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// formatPointer is the inverse of parsePointer.
+func formatPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+func parseDocument(src []byte) (*docValue, error) {
+	i, err := skipTrivia(src, 0)
+	if err != nil {
+		return nil, err
+	}
+	root, i, err := parseDocValue(src, i)
+	if err != nil {
+		return nil, err
+	}
+	if i, err = skipTrivia(src, i); err != nil {
+		return nil, err
+	}
+	if i != len(src) {
+		return nil, docError(src, i, "unexpected data after the document")
+	}
+	return root, nil
+}
+
+func parseDocValue(src []byte, i int) (*docValue, int, error) {
+	if i >= len(src) {
+		return nil, 0, docError(src, i, "unexpected end of document")
+	}
+	v := &docValue{start: i}
+	switch src[i] {
+	case '{', '[':
+		v.kind = src[i]
+		closing := byte('}')
+		if v.kind == '[' {
+			closing = ']'
+		}
+		var err error
+		for i++; ; {
+			if i, err = skipTrivia(src, i); err != nil {
+				return nil, 0, err
+			}
+			if i >= len(src) {
+				return nil, 0, docError(src, i, "unexpected end of document")
+			}
+			if src[i] == closing {
+				v.end = i + 1
+				return v, v.end, nil
+			}
+			if n := len(v.members); n > 0 && v.members[n-1].comma < 0 {
+				return nil, 0, docError(src, i, "expected a comma")
+			}
+			m := docMember{start: i, comma: -1}
+			if v.kind == '{' {
+				if src[i] != '"' {
+					return nil, 0, docError(src, i, "expected a string key")
+				}
+				if m.keyEnd, err = scanDocString(src, i); err != nil {
+					return nil, 0, err
+				}
+				if err := json.Unmarshal(src[i:m.keyEnd], &m.key); err != nil {
+					return nil, 0, docError(src, i, "invalid key")
+				}
+				if i, err = skipTrivia(src, m.keyEnd); err != nil {
+					return nil, 0, err
+				}
+				if i >= len(src) || src[i] != ':' {
+					return nil, 0, docError(src, i, "expected a colon")
+				}
+				if i, err = skipTrivia(src, i+1); err != nil {
+					return nil, 0, err
+				}
+			}
+			if m.value, i, err = parseDocValue(src, i); err != nil {
+				return nil, 0, err
+			}
+			if i, err = skipTrivia(src, i); err != nil {
+				return nil, 0, err
+			}
+			if i < len(src) && src[i] == ',' {
+				m.comma = i
+				i++
+			}
+			v.members = append(v.members, m)
+		}
+	case '"':
+		end, err := scanDocString(src, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		v.end = end
+	default:
+		end := i
+		for end < len(src) && !bytes.ContainsRune([]byte(" \t\r\n,]}/"), rune(src[end])) {
+			end++
+		}
+		v.end = end
+	}
+	if !json.Valid(src[v.start:v.end]) {
+		return nil, 0, docError(src, i, "invalid value")
+	}
+	return v, v.end, nil
+}
+
+// scanDocString returns the index following the string starting at src[i].
+func scanDocString(src []byte, i int) (int, error) {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		case '\n':
+			return 0, docError(src, j, "newline in string")
+		}
+	}
+	return 0, docError(src, i, "unterminated string")
+}
+
+// skipTrivia returns the index of the first byte at or after i that is not
+// whitespace or part of a comment.
+func skipTrivia(src []byte, i int) (int, error) {
+	for i < len(src) {
+		switch {
+		case src[i] == ' ' || src[i] == '\t' || src[i] == '\r' || src[i] == '\n':
+			i++
+		case bytes.HasPrefix(src[i:], []byte("//")):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(src[i:], []byte("/*")):
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0, docError(src, i, "unterminated comment")
+			}
+			i += end + 4
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// sameLineEnd returns the index following the spaces and the comment, if
+// any, that follow i on the same line.
+func sameLineEnd(src []byte, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	switch {
+	case bytes.HasPrefix(src[i:], []byte("//")):
+		for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+			i++
+		}
+	case bytes.HasPrefix(src[i:], []byte("/*")):
+		if end := bytes.Index(src[i+2:], []byte("*/")); end >= 0 {
+			i += end + 4
+		}
+	}
+	return i
+}
+
+// trimSpaceLeft returns the start of the whitespace that precedes i.
+func trimSpaceLeft(src []byte, i int) int {
+	for i > 0 && (src[i-1] == ' ' || src[i-1] == '\t' || src[i-1] == '\r' || src[i-1] == '\n') {
+		i--
+	}
+	return i
+}
+
+// lineIndent returns the leading whitespace of the line containing i.
+func lineIndent(src []byte, i int) string {
+	start := bytes.LastIndexByte(src[:i], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+func docError(src []byte, i int, msg string) error {
+	line := bytes.Count(src[:min(i, len(src))], []byte("\n")) + 1
+	return fmt.Errorf("jsonify: document line %d: %s", line, msg)
+}
@@ -0,0 +1,142 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleDocument() {
+	doc, err := jsonify.LoadDocument([]byte(`{
+  // Server settings.
+  "server": {
+    "host": "localhost", // overridden in production
+    "port": 8080
+  },
+  /* Enabled features. */
+  "features": ["a", "b"],
+}
+`))
+	if err != nil {
+		panic(err)
+	}
+	doc.Set("/server/port", 9090)
+	doc.Set("/server/tls", map[string]any{"cert": "server.pem"})
+	doc.Set("/features/-", "c")
+	doc.Delete("/server/host")
+	fmt.Print(string(doc.Bytes()))
+	// Output:
+	// {
+	//   // Server settings.
+	//   "server": {
+	//     "port": 9090,
+	//     "tls": {
+	//       "cert": "server.pem"
+	//     }
+	//   },
+	//   /* Enabled features. */
+	//   "features": ["a", "b", "c"],
+	// }
+}
+
+func TestDocumentSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		path     string
+		value    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "compact replace", input: `{"a":1,"b":2}`, path: "/b", value: []int{3}, expected: `{"a":1,"b":[3]}`},
+		{name: "compact insert", input: `{"a":1}`, path: "/c", value: true, expected: `{"a":1,"c":true}`},
+		{name: "compact empty", input: `{}`, path: "/a", value: 1, expected: `{"a":1}`},
+		{name: "root", input: `1`, path: "", value: "x", expected: `"x"`},
+		{name: "pointer escape", input: `{"a/b":1}`, path: "/a~1b", value: 2, expected: `{"a/b":2}`},
+		{name: "trailing comment", input: "{\n  \"a\": 1 // one\n}", path: "/b", value: 2, expected: "{\n  \"a\": 1, // one\n  \"b\": 2\n}"},
+		{name: "comment after only element", input: "[1 // one\n]", path: "/-", value: 2, expected: "[1, // one\n2\n]"},
+		{name: "comment after comma", input: "[1, // one\n]", path: "/-", value: 2, expected: "[1, // one\n2,\n]"},
+		{name: "comment after same-line members", input: "{\"a\": 1, \"b\": 2 // two\n}", path: "/c", value: 3, expected: "{\"a\": 1, \"b\": 2, // two\n\"c\": 3\n}"},
+		{name: "trailing comma", input: "{\n  \"a\": 1,\n}", path: "/b", value: 2, expected: "{\n  \"a\": 1,\n  \"b\": 2,\n}"},
+		{name: "empty multiline", input: "{\n  \"a\": {}\n}", path: "/a/b", value: 1, expected: "{\n  \"a\": {\n    \"b\": 1\n  }\n}"},
+		{name: "array index", input: `[1,2]`, path: "/1", value: 5, expected: `[1,5]`},
+		{name: "array end", input: `[1,2]`, path: "/2", value: 5, expected: `[1,2,5]`},
+		{name: "array out of range", input: `[1,2]`, path: "/3", value: 5, wantErr: true},
+		{name: "missing parent", input: `{}`, path: "/a/b", value: 1, wantErr: true},
+		{name: "scalar parent", input: `{"a":1}`, path: "/a/b", value: 1, wantErr: true},
+		{name: "invalid pointer", input: `{}`, path: "a", value: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonify.LoadDocument([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("LoadDocument() error = %v", err)
+			}
+			err = doc.Set(tt.path, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(doc.Bytes()) != tt.expected {
+				t.Errorf("Set() = %q, want %q", doc.Bytes(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "compact first", input: `{"a":1,"b":2}`, path: "/a", expected: `{"b":2}`},
+		{name: "compact last", input: `{"a":1,"b":2}`, path: "/b", expected: `{"a":1}`},
+		{name: "only", input: `[1]`, path: "/0", expected: `[]`},
+		{name: "comments", input: "{\n  // a\n  \"a\": 1, // one\n  // b\n  \"b\": 2 // two\n}", path: "/b", expected: "{\n  // a\n  \"a\": 1 // one\n}"},
+		{name: "leading comment", input: "{\n  // a\n  \"a\": 1,\n  \"b\": 2\n}", path: "/a", expected: "{\n  \"b\": 2\n}"},
+		{name: "missing", input: `{"a":1}`, path: "/b", wantErr: true},
+		{name: "root", input: `{"a":1}`, path: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonify.LoadDocument([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("LoadDocument() error = %v", err)
+			}
+			err = doc.Delete(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(doc.Bytes()) != tt.expected {
+				t.Errorf("Delete() = %q, want %q", doc.Bytes(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestDocumentGet(t *testing.T) {
+	doc, err := jsonify.LoadDocument([]byte("{\"a\": [1, /* c */ {\"b\": \"x\"},], // c\n}"))
+	if err != nil {
+		t.Fatalf("LoadDocument() error = %v", err)
+	}
+	for path, expected := range map[string]string{"": `{"a":[1,{"b":"x"}]}`, "/a/1/b": `"x"`} {
+		got, err := doc.Get(path)
+		if err != nil || string(got) != expected {
+			t.Errorf("Get(%q) = %s, %v, want %s", path, got, err, expected)
+		}
+	}
+	if _, err := doc.Get("/a/2"); err == nil {
+		t.Errorf("Get() of a missing path error = nil, want error")
+	}
+}
+
+func TestLoadDocumentError(t *testing.T) {
+	for _, input := range []string{``, `{`, `{"a" 1}`, `{"a":1 "b":2}`, `[1]]`, `/* x`, `{a:1}`, `[tru]`} {
+		if _, err := jsonify.LoadDocument([]byte(input)); err == nil {
+			t.Errorf("LoadDocument(%q) error = nil, want error", input)
+		}
+	}
+}
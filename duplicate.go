@@ -0,0 +1,75 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DuplicateKeyError is returned for a JSON object with a duplicate key.
+type DuplicateKeyError struct {
+	Key    string
+	Offset int // byte offset of the second occurrence of the key
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("jsonify: duplicate key %q at offset %d", e.Key, e.Offset)
+}
+
+// WithRejectDuplicateKeys returns a [DecodeOption] that makes decoding fail
+// with a [*DuplicateKeyError] if an object in the input has a duplicate
+// key, which parsers disagree on how to handle.
+func WithRejectDuplicateKeys() DecodeOption {
+	return func(o *decodeOptions) {
+		o.rejectDuplicateKeys = true
+	}
+}
+
+// CheckDuplicateKeys reports a [*DuplicateKeyError] for the first object in
+// data with a duplicate key. Keys are compared after unescaping, so "a" and
+// "\u0061" are duplicates.
+//
+// It returns an error if data is not valid JSON.
+func CheckDuplicateKeys(data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("jsonify: invalid JSON")
+	}
+	_, err := checkDuplicateKeys(data, skipSpace(data, 0))
+	return err
+}
+
+// checkDuplicateKeys checks the valid JSON value at data[i:] and returns the
+// index following it.
+func checkDuplicateKeys(data []byte, i int) (int, error) {
+	switch data[i] {
+	case '{', '[':
+		var seen map[string]bool
+		if data[i] == '{' {
+			seen = map[string]bool{}
+		}
+		i = skipSpace(data, i+1)
+		for data[i] != '}' && data[i] != ']' {
+			if seen != nil {
+				end := scanString(data, i)
+				var key string
+				if err := json.Unmarshal(data[i:end], &key); err != nil {
+					return 0, err
+				}
+				if seen[key] {
+					return 0, &DuplicateKeyError{Key: key, Offset: i}
+				}
+				seen[key] = true
+				i = skipSpace(data, skipSpace(data, end)+1)
+			}
+			var err error
+			if i, err = checkDuplicateKeys(data, i); err != nil {
+				return 0, err
+			}
+			if i = skipSpace(data, i); data[i] == ',' {
+				i = skipSpace(data, i+1)
+			}
+		}
+		return i + 1, nil
+	}
+	_, end := parseNode(data, i)
+	return end, nil
+}
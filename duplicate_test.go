@@ -0,0 +1,57 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithRejectDuplicateKeys() {
+	var v map[string]any
+	err := jsonify.Decode([]byte(`{"role":"user","role":"admin"}`), &v, jsonify.WithRejectDuplicateKeys())
+	fmt.Println(err)
+	// Output:
+	// jsonify: duplicate key "role" at offset 15
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		key     string
+		offset  int
+		invalid bool
+	}{
+		{name: "none", input: `{"a":1,"b":{"a":2},"c":[{"a":3},{"a":4}]}`},
+		{name: "scalar", input: ` "x" `},
+		{name: "top level", input: `{"a":1, "a":2}`, key: "a", offset: 8},
+		{name: "nested", input: `[{"x":{"b":"}","b":null}}]`, key: "b", offset: 15},
+		{name: "escaped", input: `{"a":1,"\u0061":2}`, key: "a", offset: 7},
+		{name: "invalid", input: `{"a":1,"a"}`, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jsonify.CheckDuplicateKeys([]byte(tt.input))
+			var dup *jsonify.DuplicateKeyError
+			switch {
+			case tt.invalid:
+				if err == nil || errors.As(err, &dup) {
+					t.Errorf("CheckDuplicateKeys() error = %v, want invalid JSON", err)
+				}
+			case tt.key == "":
+				if err != nil {
+					t.Errorf("CheckDuplicateKeys() error = %v", err)
+				}
+			case !errors.As(err, &dup) || dup.Key != tt.key || dup.Offset != tt.offset:
+				t.Errorf("CheckDuplicateKeys() error = %v, want key %q at %d", err, tt.key, tt.offset)
+			}
+		})
+	}
+
+	var v any
+	if err := jsonify.Decode([]byte(`{"a":1,"a":2}`), &v); err != nil {
+		t.Errorf("Decode() without the option error = %v", err)
+	}
+}
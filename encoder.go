@@ -0,0 +1,48 @@
+package jsonify
+
+import "slices"
+
+// Encoder encodes values with a fixed set of options, so that a package can
+// configure jsonify once instead of passing the same options to every call:
+//
+//	var enc = jsonify.New(jsonify.WithInt64(jsonify.Int64AsString))
+//
+//	b, err := enc.Bytes(v)
+//
+// An Encoder is safe for concurrent use.
+type Encoder struct {
+	opts []Option
+}
+
+// New returns an [Encoder] applying opts to every call.
+func New(opts ...Option) *Encoder {
+	return &Encoder{opts: slices.Clone(opts)}
+}
+
+// with returns the options of e followed by opts, which take precedence.
+func (e *Encoder) with(opts []Option) []Option {
+	if len(opts) == 0 {
+		return e.opts
+	}
+	return slices.Concat(e.opts, opts)
+}
+
+// Bytes is [Bytes] with the options of e followed by opts.
+func (e *Encoder) Bytes(v any, opts ...Option) ([]byte, error) {
+	return Bytes(v, e.with(opts)...)
+}
+
+// String is [String] with the options of e followed by opts.
+func (e *Encoder) String(v any, opts ...Option) (string, error) {
+	return String(v, e.with(opts)...)
+}
+
+// MustBytes is [MustBytes] with the options of e followed by opts.
+func (e *Encoder) MustBytes(v any, opts ...Option) []byte {
+	return MustBytes(v, e.with(opts)...)
+}
+
+// MustString is [MustString] with the options of e followed by opts.
+func (e *Encoder) MustString(v any, opts ...Option) string {
+	return MustString(v, e.with(opts)...)
+}
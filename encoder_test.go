@@ -0,0 +1,34 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleNew() {
+	enc := jsonify.New(jsonify.WithInt64(jsonify.Int64AsString))
+	fmt.Println(enc.MustString(map[string]int64{"id": 1}))
+	// Output:
+	// {"id":"1"}
+}
+
+func TestEncoder(t *testing.T) {
+	opts := []jsonify.Option{jsonify.WithFloatFormat(jsonify.FloatFixed(1))}
+	enc := jsonify.New(opts...)
+	opts[0] = jsonify.WithFloatFormat(jsonify.FloatFixed(3))
+
+	if got, err := enc.String(1.0); err != nil || got != "1.0" {
+		t.Errorf("String() = %v, %v, want 1.0", got, err)
+	}
+	if got := enc.MustString(1.0, jsonify.WithFloatFormat(jsonify.FloatFixed(2))); got != "1.00" {
+		t.Errorf("MustString() with an option = %v, want 1.00", got)
+	}
+	if got, err := enc.Bytes([]float64{0.5}); err != nil || string(got) != "[0.5]" {
+		t.Errorf("Bytes() = %s, %v", got, err)
+	}
+	if got := enc.MustBytes(2.0); string(got) != "2.0" {
+		t.Errorf("MustBytes() = %s", got)
+	}
+}
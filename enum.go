@@ -0,0 +1,136 @@
+package jsonify
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// EnumOption configures how [Enum] and [Flags] handle values.
+type EnumOption func(*enumOptions)
+
+type enumOptions struct {
+	rejectUnknown bool
+}
+
+// RejectUnknown makes encoding and decoding fail for values that have no
+// registered name. By default, such values are encoded as and decoded from
+// JSON numbers.
+//
+// For [Flags], a value is unknown if it has bits without a registered name.
+func RejectUnknown() EnumOption {
+	return func(o *enumOptions) {
+		o.rejectUnknown = true
+	}
+}
+
+// WithEnumNumbers returns an [Option] that encodes enums as numbers instead
+// of names: the types registered with [Enum], and protobuf enums, whether in
+// messages, as protojson does with UseEnumNumbers, or in other values with
+// [WithProtoEnumNames].
+//
+// Decoding accepts both names and numbers regardless.
+func WithEnumNumbers() Option {
+	return func(o *options) {
+		o.enumNumbers = true
+	}
+}
+
+// Enum registers names for the values of an integer enum type T, such as
+// a type declared with iota constants.
+//
+// Values of T then encode as their names, and decode from either their names
+// or numbers, without T implementing [json.Marshaler] or [json.Unmarshaler].
+//
+//	type Color int
+//
+//	const (
+//		Red Color = iota
+//		Green
+//	)
+//
+//	func init() {
+//		jsonify.Enum(map[Color]string{Red: "red", Green: "green"})
+//	}
+//
+// Enum panics if two values share the same name.
+//
+// Registrations apply to the [Jsoniter] backend and [Decode]. They are meant
+// to be made during program initialization.
+func Enum[T ~int | ~int8 | ~int16 | ~int32 | ~int64](names map[T]string, opts ...EnumOption) {
+	c := &enumCodec[T]{
+		names:  make(map[T]string, len(names)),
+		values: make(map[string]T, len(names)),
+	}
+	for _, opt := range opts {
+		opt(&c.enumOptions)
+	}
+	for v, name := range names {
+		if other, ok := c.values[name]; ok {
+			panic(fmt.Sprintf("jsonify: enum name %q is used by both %d and %d", name, other, v))
+		}
+		c.names[v] = name
+		c.values[name] = v
+	}
+	register(reflect.TypeOf(T(0)), c, c)
+}
+
+type enumCodec[T ~int | ~int8 | ~int16 | ~int32 | ~int64] struct {
+	enumOptions
+	names  map[T]string
+	values map[string]T
+}
+
+func (c *enumCodec[T]) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*T)(ptr) == 0
+}
+
+func (c *enumCodec[T]) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	v := *(*T)(ptr)
+	o, _ := stream.Attachment.(*options)
+	if o != nil && o.enumNumbers {
+		stream.WriteInt64(int64(v))
+		return
+	}
+	if name, ok := c.names[v]; ok {
+		stream.WriteString(name)
+		return
+	}
+	if c.rejectUnknown {
+		o.fail(stream, fmt.Errorf("jsonify: %T value %d has no registered name", v, v))
+		return
+	}
+	stream.WriteInt64(int64(v))
+}
+
+func (c *enumCodec[T]) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		name := iter.ReadString()
+		v, ok := c.values[name]
+		if !ok {
+			iter.ReportError("jsonify.Enum", "unknown name "+strconv.Quote(name))
+			return
+		}
+		*(*T)(ptr) = v
+	case jsoniter.NumberValue:
+		n := iter.ReadInt64()
+		v := T(n)
+		if int64(v) != n {
+			iter.ReportError("jsonify.Enum", "value "+strconv.FormatInt(n, 10)+" overflows")
+			return
+		}
+		if _, ok := c.names[v]; !ok && c.rejectUnknown {
+			iter.ReportError("jsonify.Enum", "unknown value "+strconv.FormatInt(n, 10))
+			return
+		}
+		*(*T)(ptr) = v
+	case jsoniter.NilValue:
+		iter.Skip()
+	default:
+		iter.ReportError("jsonify.Enum", "expect string or number")
+	}
+}
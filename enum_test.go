@@ -0,0 +1,111 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type color int
+
+const (
+	red color = iota
+	green
+	blue
+)
+
+type level int8
+
+func init() {
+	jsonify.Enum(map[color]string{red: "red", green: "green", blue: "blue"})
+	jsonify.Enum(map[level]string{0: "low", 1: "high"}, jsonify.RejectUnknown())
+}
+
+func ExampleEnum() {
+	type Weekday int
+	jsonify.Enum(map[Weekday]string{0: "sunday", 1: "monday"})
+
+	fmt.Println(jsonify.MustString([]Weekday{1, 0}))
+
+	var days []Weekday
+	jsonify.Decode([]byte(`["sunday",1]`), &days)
+	fmt.Println(days)
+	// Output:
+	// ["monday","sunday"]
+	// [0 1]
+}
+
+func TestEnum(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "value", input: green, expected: `"green"`},
+		{name: "pointer", input: &struct{ C *color }{C: new(color)}, expected: `{"C":"red"}`},
+		{name: "unknown", input: color(7), expected: `7`},
+		{name: "rejected unknown", input: level(2), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnum_firstError(t *testing.T) {
+	_, err := jsonify.String([]level{1, 2, 3})
+	if want := "jsonify: jsonify_test.level value 2 has no registered name"; err == nil || err.Error() != want {
+		t.Errorf("String() error = %v, want %v", err, want)
+	}
+}
+
+func TestWithEnumNumbers(t *testing.T) {
+	got, err := jsonify.String(map[string]any{"colors": []color{green, 7}, "level": level(2)}, jsonify.WithEnumNumbers())
+	if want := `{"colors":[1,7],"level":2}`; err != nil || got != want {
+		t.Errorf("String() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestEnumDecode(t *testing.T) {
+	var v struct {
+		A, B, C color
+		L       level
+	}
+	if err := jsonify.Decode([]byte(`{"A":"blue","B":1,"C":9,"L":"high"}`), &v); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if v.A != blue || v.B != green || v.C != 9 || v.L != 1 {
+		t.Errorf("Decode() = %+v", v)
+	}
+
+	inputs := []string{`"purple"`, `true`, `300`}
+	for _, input := range inputs {
+		var c level
+		if err := jsonify.Decode([]byte(input), &c); err == nil {
+			t.Errorf("Decode(%s) error = nil, want error", input)
+		}
+	}
+	var l level
+	if err := jsonify.Decode([]byte(`5`), &l); err == nil {
+		t.Errorf("Decode() accepted an unknown value with RejectUnknown")
+	}
+}
+
+func TestEnumDuplicateName(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Enum() did not panic")
+		}
+	}()
+	type dup int
+	jsonify.Enum(map[dup]string{0: "same", 1: "same"})
+}
@@ -0,0 +1,403 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// TableField describes a field of a flat struct for a [FieldTable]: the JSON
+// name of the field, its offset in the struct, as given by [unsafe.Offsetof],
+// and its kind.
+//
+// The supported kinds are Bool, String, Float32, Float64, and the signed and
+// unsigned integer kinds.
+type TableField struct {
+	Name   string
+	Offset uintptr
+	Kind   reflect.Kind
+}
+
+// FieldTable decodes JSON objects into a flat struct T without reflection,
+// using a table of the offsets and kinds of its fields, for hot types whose
+// decoding dominates the cost of a service.
+//
+// The table can be generated by the jsonify-gen command, in cmd/jsonify-gen,
+// written by hand, or built at startup with [FieldTableOf]:
+//
+//	var eventTable = jsonify.NewFieldTable[Event](
+//		jsonify.TableField{Name: "id", Offset: unsafe.Offsetof(Event{}.ID), Kind: reflect.Int64},
+//		jsonify.TableField{Name: "name", Offset: unsafe.Offsetof(Event{}.Name), Kind: reflect.String},
+//	)
+//
+// Keys match the names exactly or, failing that, case-insensitively, as with
+// [Decode]. Keys without a field and null values are skipped.
+type FieldTable[T any] struct {
+	fields []TableField
+	index  map[string]int
+}
+
+// NewFieldTable returns a [FieldTable] for T with fields.
+//
+// It checks once, with reflection, that T has a field of each kind at each
+// offset, and panics if not, so that a stale table cannot corrupt memory.
+func NewFieldTable[T any](fields ...TableField) *FieldTable[T] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonify: NewFieldTable of non-struct type %v", typ))
+	}
+	byOffset := map[uintptr]reflect.StructField{}
+	for _, f := range reflect.VisibleFields(typ) {
+		if len(f.Index) == 1 {
+			byOffset[f.Offset] = f
+		}
+	}
+	t := &FieldTable[T]{fields: fields, index: make(map[string]int, len(fields))}
+	for i, field := range fields {
+		f, ok := byOffset[field.Offset]
+		if !ok || f.Type.Kind() != field.Kind || !tableKind(field.Kind) {
+			panic(fmt.Sprintf("jsonify: %v has no %v field at offset %d for %q", typ, field.Kind, field.Offset, field.Name))
+		}
+		t.index[field.Name] = i
+	}
+	return t
+}
+
+// FieldTableOf returns a [FieldTable] for the exported fields of T, named by
+// their json tags, using reflection once instead of a generated table.
+//
+// It panics if T is not a struct, or has an exported field whose kind is
+// not supported by [TableField].
+func FieldTableOf[T any]() *FieldTable[T] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonify: FieldTableOf non-struct type %v", typ))
+	}
+	var fields []TableField
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		if !tableKind(f.Type.Kind()) {
+			panic(fmt.Sprintf("jsonify: field %v.%s has type %v, which a FieldTable does not support", typ, f.Name, f.Type))
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, TableField{Name: name, Offset: f.Offset, Kind: f.Type.Kind()})
+	}
+	return NewFieldTable[T](fields...)
+}
+
+func tableKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// Decode decodes data, which must be a JSON object, into v.
+func (t *FieldTable[T]) Decode(data []byte, v *T) error {
+	i := skipSpace(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return fmt.Errorf("jsonify: FieldTable of %T decodes an object", *v)
+	}
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return trailing(data, i+1)
+	}
+	for {
+		end, escaped, err := scanTableString(data, i)
+		if err != nil {
+			return err
+		}
+		key := data[i+1 : end-1]
+		if escaped {
+			s, err := unquote(data[i:end])
+			if err != nil {
+				return err
+			}
+			key = []byte(s)
+		}
+		if i = skipSpace(data, end); i == len(data) || data[i] != ':' {
+			return tableSyntaxError(data, i)
+		}
+		i = skipSpace(data, i+1)
+		field, ok := t.lookup(key)
+		if end, err = tableValueEnd(data, i); err != nil {
+			return err
+		}
+		if ok && string(data[i:end]) != "null" {
+			if err := setField(unsafe.Pointer(v), field, data[i:end]); err != nil {
+				return err
+			}
+		} else if !json.Valid(data[i:end]) {
+			return tableSyntaxError(data, i)
+		}
+		i = skipSpace(data, end)
+		if i == len(data) {
+			return tableSyntaxError(data, i)
+		}
+		if data[i] == '}' {
+			return trailing(data, i+1)
+		}
+		if data[i] != ',' {
+			return tableSyntaxError(data, i)
+		}
+		i = skipSpace(data, i+1)
+	}
+}
+
+func (t *FieldTable[T]) lookup(key []byte) (TableField, bool) {
+	if i, ok := t.index[string(key)]; ok {
+		return t.fields[i], true
+	}
+	for _, f := range t.fields {
+		if strings.EqualFold(f.Name, string(key)) {
+			return f, true
+		}
+	}
+	return TableField{}, false
+}
+
+func tableSyntaxError(data []byte, i int) error {
+	if i >= len(data) {
+		return fmt.Errorf("jsonify: unexpected end of JSON input")
+	}
+	return fmt.Errorf("jsonify: invalid character %q at offset %d", data[i], i)
+}
+
+// trailing reports an error if data has anything but whitespace after i.
+func trailing(data []byte, i int) error {
+	if i = skipSpace(data, i); i != len(data) {
+		return tableSyntaxError(data, i)
+	}
+	return nil
+}
+
+// scanTableString returns the end of the JSON string starting at data[i],
+// and whether it contains escapes, which are validated by [unquote].
+func scanTableString(data []byte, i int) (int, bool, error) {
+	if i == len(data) || data[i] != '"' {
+		return 0, false, tableSyntaxError(data, i)
+	}
+	escaped := false
+	for i++; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '"':
+			return i + 1, escaped, nil
+		case c == '\\':
+			escaped = true
+			i++
+		case c < 0x20:
+			return 0, false, tableSyntaxError(data, i)
+		}
+	}
+	return 0, false, tableSyntaxError(data, i)
+}
+
+// tableValueEnd returns the end of the JSON value starting at data[i].
+// Only strings are validated; other values are validated by their parser.
+func tableValueEnd(data []byte, i int) (int, error) {
+	if i == len(data) {
+		return 0, tableSyntaxError(data, i)
+	}
+	switch data[i] {
+	case '"':
+		end, _, err := scanTableString(data, i)
+		return end, err
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				end, _, err := scanTableString(data, i)
+				if err != nil {
+					return 0, err
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+		return 0, tableSyntaxError(data, i)
+	}
+	end := i
+	for end < len(data) && !isDelimiter(data[end]) {
+		end++
+	}
+	if end == i {
+		return 0, tableSyntaxError(data, i)
+	}
+	return end, nil
+}
+
+// isDelimiter reports whether c ends a JSON literal or number.
+func isDelimiter(c byte) bool {
+	switch c {
+	case ',', ']', '}', ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
+
+// unquote returns the value of the JSON string s.
+func unquote(s []byte) (string, error) {
+	var v string
+	err := json.Unmarshal(s, &v)
+	return v, err
+}
+
+// setField stores the JSON value raw in the field f of the struct at base.
+func setField(base unsafe.Pointer, f TableField, raw []byte) error {
+	p := unsafe.Add(base, f.Offset)
+	mismatch := func() error {
+		return fmt.Errorf("jsonify: cannot decode %s into %s field %q", raw, f.Kind, f.Name)
+	}
+	switch f.Kind {
+	case reflect.Bool:
+		switch string(raw) {
+		case "true":
+			*(*bool)(p) = true
+		case "false":
+			*(*bool)(p) = false
+		default:
+			return mismatch()
+		}
+	case reflect.String:
+		if raw[0] != '"' {
+			return mismatch()
+		}
+		s := string(raw[1 : len(raw)-1])
+		if bytes.IndexByte(raw, '\\') >= 0 {
+			var err error
+			if s, err = unquote(raw); err != nil {
+				return err
+			}
+		}
+		*(*string)(p) = s
+	case reflect.Float32, reflect.Float64:
+		if !isNumber(raw) {
+			return mismatch()
+		}
+		bits := 64
+		if f.Kind == reflect.Float32 {
+			bits = 32
+		}
+		// The number is only read, so it is not copied into a string.
+		n, err := strconv.ParseFloat(unsafe.String(&raw[0], len(raw)), bits)
+		if err != nil {
+			return mismatch()
+		}
+		if bits == 32 {
+			*(*float32)(p) = float32(n)
+		} else {
+			*(*float64)(p) = n
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(unsafe.String(&raw[0], len(raw)), 10, intBits(f.Kind))
+		if err != nil || raw[0] == '+' || !isNumber(raw) {
+			return mismatch()
+		}
+		switch f.Kind {
+		case reflect.Int:
+			*(*int)(p) = int(n)
+		case reflect.Int8:
+			*(*int8)(p) = int8(n)
+		case reflect.Int16:
+			*(*int16)(p) = int16(n)
+		case reflect.Int32:
+			*(*int32)(p) = int32(n)
+		default:
+			*(*int64)(p) = n
+		}
+	default:
+		n, err := strconv.ParseUint(unsafe.String(&raw[0], len(raw)), 10, intBits(f.Kind))
+		if err != nil || !isNumber(raw) {
+			return mismatch()
+		}
+		switch f.Kind {
+		case reflect.Uint:
+			*(*uint)(p) = uint(n)
+		case reflect.Uint8:
+			*(*uint8)(p) = uint8(n)
+		case reflect.Uint16:
+			*(*uint16)(p) = uint16(n)
+		case reflect.Uint32:
+			*(*uint32)(p) = uint32(n)
+		case reflect.Uintptr:
+			*(*uintptr)(p) = uintptr(n)
+		default:
+			*(*uint64)(p) = n
+		}
+	}
+	return nil
+}
+
+// isNumber reports whether b is a JSON number.
+func isNumber(b []byte) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && '1' <= b[i] && b[i] <= '9':
+		for i < len(b) && '0' <= b[i] && b[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(b) && b[i] == '.' {
+		j := i + 1
+		for i = j; i < len(b) && '0' <= b[i] && b[i] <= '9'; i++ {
+		}
+		if i == j {
+			return false
+		}
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		j := i
+		for ; i < len(b) && '0' <= b[i] && b[i] <= '9'; i++ {
+		}
+		if i == j {
+			return false
+		}
+	}
+	return i == len(b)
+}
+
+// intBits returns the size in bits of the integer kind k.
+func intBits(k reflect.Kind) int {
+	switch k {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32:
+		return 32
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return strconv.IntSize
+	}
+	return 64
+}
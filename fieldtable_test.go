@@ -0,0 +1,126 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/goaux/jsonify"
+)
+
+type event struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Active bool    `json:"active"`
+	Code   uint8   `json:"code"`
+	Ratio  float32
+	Count  int `json:",omitempty"`
+	Skip   int `json:"-"`
+}
+
+var eventTable = jsonify.NewFieldTable[event](
+	jsonify.TableField{Name: "id", Offset: unsafe.Offsetof(event{}.ID), Kind: reflect.Int64},
+	jsonify.TableField{Name: "name", Offset: unsafe.Offsetof(event{}.Name), Kind: reflect.String},
+)
+
+func ExampleFieldTable() {
+	var e event
+	err := eventTable.Decode([]byte(`{"id":7,"name":"signup","score":1.5}`), &e)
+	fmt.Println(e.ID, e.Name, e.Score, err)
+	// Output:
+	// 7 signup 0 <nil>
+}
+
+func TestFieldTableOf(t *testing.T) {
+	table := jsonify.FieldTableOf[event]()
+	tests := []struct {
+		name     string
+		input    string
+		expected event
+		wantErr  bool
+	}{
+		{
+			name:     "all kinds",
+			input:    `{"id":-1,"name":"aé\"b","score":2.5e1,"active":true,"code":255,"Ratio":0.5,"Count":3}`,
+			expected: event{ID: -1, Name: "aé\"b", Score: 25, Active: true, Code: 255, Ratio: 0.5, Count: 3},
+		},
+		{
+			name:     "unknown and null",
+			input:    ` { "extra" : {"a":[1,"}"]}, "NAME":"x", "id":null, "Skip":1 } `,
+			expected: event{Name: "x"},
+		},
+		{name: "overflow", input: `{"code":256}`, wantErr: true},
+		{name: "mismatch", input: `{"name":1}`, wantErr: true},
+		{name: "fraction", input: `{"id":1.5}`, wantErr: true},
+		{name: "invalid", input: `{"id":1`, wantErr: true},
+		{name: "not an object", input: `[1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got event
+			err := table.Decode([]byte(tt.input), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Decode() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("Decode() = %+v, %v, want %+v", got, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFieldTableOf_dashName(t *testing.T) {
+	type dash struct {
+		Dash int `json:"-,"`
+		Skip int `json:"-"`
+	}
+	var got dash
+	if err := jsonify.FieldTableOf[dash]().Decode([]byte(`{"-":1,"Skip":2}`), &got); err != nil || got != (dash{Dash: 1}) {
+		t.Errorf("Decode() = %+v, %v, want %+v", got, err, dash{Dash: 1})
+	}
+}
+
+func TestNewFieldTablePanics(t *testing.T) {
+	tests := map[string]func(){
+		"wrong kind": func() {
+			jsonify.NewFieldTable[event](jsonify.TableField{Name: "id", Offset: unsafe.Offsetof(event{}.ID), Kind: reflect.String})
+		},
+		"wrong offset": func() {
+			jsonify.NewFieldTable[event](jsonify.TableField{Name: "id", Offset: 1, Kind: reflect.Int64})
+		},
+		"unsupported": func() { jsonify.FieldTableOf[struct{ A []int }]() },
+		"non-struct":  func() { jsonify.FieldTableOf[int]() },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func BenchmarkFieldTable(b *testing.B) {
+	data := []byte(`{"id":12345,"name":"signup","score":0.75,"active":true,"code":3,"Ratio":1,"Count":9}`)
+	table := jsonify.FieldTableOf[event]()
+	b.Run("FieldTable", func(b *testing.B) {
+		var e event
+		for range b.N {
+			table.Decode(data, &e)
+		}
+	})
+	b.Run("Decode", func(b *testing.B) {
+		var e event
+		for range b.N {
+			jsonify.Decode(data, &e)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package jsonify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modern-go/reflect2"
+)
+
+// TypeFingerprint returns a stable hash of the JSON shape of T: the names of
+// the fields it encodes, whether they are omitted when empty, and the JSON
+// types of their values, recursively. It changes when a producer adds,
+// removes, renames or retypes a field, but not when it reorders fields or
+// changes Go details that don't show in the JSON, e.g. int32 to int64.
+//
+// Types that marshal themselves are described by their name only, and the
+// fields selected by the jsonify tag, e.g. for an audience or a view, are
+// not taken into account. Use [WithFingerprint] to include the fingerprint
+// in the output, or send it as metadata, e.g. in an HTTP header.
+func TypeFingerprint[T any]() string {
+	return fingerprintOf(reflect.TypeFor[T]())
+}
+
+// Fingerprint is [TypeFingerprint] of the dynamic type of v.
+func Fingerprint(v any) string {
+	return fingerprintOf(reflect.TypeOf(v))
+}
+
+// WithFingerprint returns an [Option] that adds the [Fingerprint] of the
+// value as the first member of its object, under key, so that consumers can
+// detect changes of the producer's types:
+//
+//	jsonify.Bytes(user, jsonify.WithFingerprint("$shape"))
+//	// {"$shape":"ae39cd4d46f3c966","id":1,"name":"Ann"}
+//
+// Encoding fails if the value does not encode as a JSON object.
+func WithFingerprint(key string) Option {
+	return func(o *options) {
+		o.fingerprint = key
+	}
+}
+
+// addFingerprint inserts the fingerprint of v into b, its encoded object.
+func addFingerprint(b []byte, key string, v any) ([]byte, error) {
+	i := skipSpace(b, 0)
+	if i == len(b) || b[i] != '{' {
+		return nil, fmt.Errorf("jsonify: WithFingerprint of %T, which does not encode as an object", v)
+	}
+	k, err := marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	member := fmt.Sprintf(`%s:"%s"`, k, Fingerprint(v))
+	if j := skipSpace(b, i+1); j == len(b) || b[j] != '}' {
+		member += ","
+	}
+	return slices.Insert(b, i+1, []byte(member)...), nil
+}
+
+var fingerprints sync.Map // reflect.Type -> string
+
+func fingerprintOf(t reflect.Type) string {
+	if t == nil {
+		return fingerprintOfShape("null")
+	}
+	if fp, ok := fingerprints.Load(t); ok {
+		return fp.(string)
+	}
+	var sb strings.Builder
+	describeType(&sb, t, map[reflect.Type]bool{})
+	fp, _ := fingerprints.LoadOrStore(t, fingerprintOfShape(sb.String()))
+	return fp.(string)
+}
+
+func fingerprintOfShape(shape string) string {
+	sum := sha256.Sum256([]byte(shape))
+	return hex.EncodeToString(sum[:8])
+}
+
+// describeType writes a canonical description of the JSON shape of t to sb.
+// A struct type already being described is referred to by its name.
+func describeType(sb *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	if t.Kind() != reflect.Interface && hasMarshaler(reflect2.Type2(t)) {
+		sb.WriteString(t.String())
+		return
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		sb.WriteByte('*')
+		describeType(sb, t.Elem(), seen)
+	case reflect.Bool:
+		sb.WriteString("boolean")
+	case reflect.String:
+		sb.WriteString("string")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sb.WriteString("integer")
+	case reflect.Float32, reflect.Float64:
+		sb.WriteString("number")
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			sb.WriteString("bytes")
+			return
+		}
+		sb.WriteByte('[')
+		describeType(sb, t.Elem(), seen)
+		sb.WriteByte(']')
+	case reflect.Map:
+		sb.WriteString("map[")
+		describeType(sb, t.Key(), seen)
+		sb.WriteByte(']')
+		describeType(sb, t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			sb.WriteString(t.String())
+			return
+		}
+		seen[t] = true
+		sb.WriteByte('{')
+		for i, f := range structFields(t) {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(f.name)
+			if f.omitEmpty {
+				sb.WriteByte('?')
+			}
+			sb.WriteByte(':')
+			if f.quoted {
+				sb.WriteString("string")
+			} else {
+				describeType(sb, f.typ, seen)
+			}
+		}
+		sb.WriteByte('}')
+		delete(seen, t)
+	default:
+		sb.WriteString("any")
+	}
+}
+
+type structField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+	quoted    bool
+	depth     int
+}
+
+// structFields returns the encoded fields of the struct type t, including
+// those promoted from embedded structs, sorted by name.
+func structFields(t reflect.Type) []structField {
+	byName := map[string]structField{}
+	var collect func(t reflect.Type, depth int)
+	collect = func(t reflect.Type, depth int) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" && opts == "" {
+				continue
+			}
+			ft := f.Type
+			if f.Anonymous && name == "" {
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					collect(ft, depth+1)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if prev, ok := byName[name]; ok && prev.depth <= depth {
+				continue
+			}
+			byName[name] = structField{
+				name:      name,
+				typ:       f.Type,
+				omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+				quoted:    slices.Contains(strings.Split(opts, ","), "string"),
+				depth:     depth,
+			}
+		}
+	}
+	collect(t, 0)
+	fields := make([]structField, 0, len(byName))
+	for _, f := range byName {
+		fields = append(fields, f)
+	}
+	slices.SortFunc(fields, func(a, b structField) int { return strings.Compare(a.name, b.name) })
+	return fields
+}
@@ -0,0 +1,124 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithFingerprint() {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	s, err := jsonify.String(user{ID: 1, Name: "Ann"}, jsonify.WithFingerprint("$shape"))
+	fmt.Println(s, err)
+	fmt.Println(jsonify.TypeFingerprint[user]())
+	// Output:
+	// {"$shape":"ae39cd4d46f3c966","id":1,"name":"Ann"} <nil>
+	// ae39cd4d46f3c966
+}
+
+type fingerprintNode struct {
+	Value    int                `json:"value"`
+	Children []*fingerprintNode `json:"children,omitempty"`
+}
+
+func TestTypeFingerprint(t *testing.T) {
+	type base struct {
+		ID      int32     `json:"id"`
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+	type reordered struct {
+		Created time.Time `json:"created"`
+		Name    string    `json:"name"`
+		ID      int64     `json:"id"`
+		private bool
+		Ignored bool `json:"-"`
+	}
+	type embedded struct {
+		Meta struct {
+			Created time.Time `json:"created"`
+		}
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	type promoted struct {
+		embeddedTime
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	type renamed struct {
+		ID      int32     `json:"id"`
+		Label   string    `json:"label"`
+		Created time.Time `json:"created"`
+	}
+	type retyped struct {
+		ID      string    `json:"id"`
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+	type quoted struct {
+		ID      int32     `json:"id,string"`
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+	type omitted struct {
+		ID      int32     `json:"id"`
+		Name    string    `json:"name,omitempty"`
+		Created time.Time `json:"created"`
+	}
+	type nullable struct {
+		ID      int32     `json:"id"`
+		Name    *string   `json:"name"`
+		Created time.Time `json:"created"`
+	}
+
+	want := jsonify.TypeFingerprint[base]()
+	if len(want) != 16 {
+		t.Errorf("TypeFingerprint() = %q, want 16 hex digits", want)
+	}
+	for name, got := range map[string]string{
+		"reordered": jsonify.TypeFingerprint[reordered](),
+		"promoted":  jsonify.TypeFingerprint[promoted](),
+		"value":     jsonify.Fingerprint(base{}),
+	} {
+		if got != want {
+			t.Errorf("TypeFingerprint() of %s = %v, want %v", name, got, want)
+		}
+	}
+	for name, got := range map[string]string{
+		"embedded": jsonify.TypeFingerprint[embedded](),
+		"renamed":  jsonify.TypeFingerprint[renamed](),
+		"retyped":  jsonify.TypeFingerprint[retyped](),
+		"quoted":   jsonify.TypeFingerprint[quoted](),
+		"omitted":  jsonify.TypeFingerprint[omitted](),
+		"nullable": jsonify.TypeFingerprint[nullable](),
+		"slice":    jsonify.TypeFingerprint[[]base](),
+	} {
+		if got == want {
+			t.Errorf("TypeFingerprint() of %s = %v, want a different fingerprint", name, got)
+		}
+	}
+	if a, b := jsonify.TypeFingerprint[fingerprintNode](), jsonify.TypeFingerprint[[]fingerprintNode](); a == b {
+		t.Errorf("TypeFingerprint() of a recursive type = %v for its slice too", a)
+	}
+}
+
+type embeddedTime struct {
+	Created time.Time `json:"created"`
+}
+
+func TestWithFingerprint(t *testing.T) {
+	fp := jsonify.TypeFingerprint[struct{}]()
+	got, err := jsonify.String(struct{}{}, jsonify.WithFingerprint("fp"), jsonify.WithIndent("", " "))
+	if want := "{\n \"fp\": \"" + fp + "\"\n}"; err != nil || got != want {
+		t.Errorf("String() = %q, %v, want %q", got, err, want)
+	}
+	if _, err := jsonify.String([]int{1}, jsonify.WithFingerprint("fp")); err == nil {
+		t.Errorf("String() of an array with WithFingerprint error = nil, want error")
+	}
+}
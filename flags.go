@@ -0,0 +1,154 @@
+package jsonify
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Flags registers names for the bits of an unsigned integer bitmask type T.
+//
+// Values of T then encode as a JSON array of the names of the set bits,
+// ordered by bit, and decode from such an array or from a JSON number:
+//
+//	type Perm uint
+//
+//	const (
+//		Read Perm = 1 << iota
+//		Write
+//	)
+//
+//	func init() {
+//		jsonify.Flags(map[Perm]string{Read: "read", Write: "write"})
+//	}
+//
+// Read|Write then encodes as ["read","write"]. Set bits without a name are
+// encoded as a single number at the end of the array, unless
+// [RejectUnknown] is given.
+//
+// Flags panics if a value is not a single bit or two values share the same
+// name.
+//
+// Registrations apply to the [Jsoniter] backend and [Decode]. They are meant
+// to be made during program initialization.
+func Flags[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](names map[T]string, opts ...EnumOption) {
+	c := &flagsCodec[T]{
+		values: make(map[string]T, len(names)),
+	}
+	for _, opt := range opts {
+		opt(&c.enumOptions)
+	}
+	for v, name := range names {
+		if bits.OnesCount64(uint64(v)) != 1 {
+			panic(fmt.Sprintf("jsonify: flag %q has value %d, which is not a single bit", name, v))
+		}
+		if other, ok := c.values[name]; ok {
+			panic(fmt.Sprintf("jsonify: flag name %q is used by both %d and %d", name, other, v))
+		}
+		c.values[name] = v
+		c.known |= v
+		c.bits = append(c.bits, flagBit[T]{v, name})
+	}
+	sort.Slice(c.bits, func(i, j int) bool { return c.bits[i].value < c.bits[j].value })
+	register(reflect.TypeOf(T(0)), c, c)
+}
+
+type flagBit[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64] struct {
+	value T
+	name  string
+}
+
+type flagsCodec[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64] struct {
+	enumOptions
+	bits   []flagBit[T]
+	values map[string]T
+	known  T
+}
+
+func (c *flagsCodec[T]) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*T)(ptr) == 0
+}
+
+func (c *flagsCodec[T]) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	v := *(*T)(ptr)
+	unknown := v &^ c.known
+	if unknown != 0 && c.rejectUnknown {
+		o, _ := stream.Attachment.(*options)
+		o.fail(stream, fmt.Errorf("jsonify: %T value %d has unregistered bits %d", v, v, unknown))
+		return
+	}
+	stream.WriteArrayStart()
+	first := true
+	for _, bit := range c.bits {
+		if v&bit.value == 0 {
+			continue
+		}
+		if !first {
+			stream.WriteMore()
+		}
+		first = false
+		stream.WriteString(bit.name)
+	}
+	if unknown != 0 {
+		if !first {
+			stream.WriteMore()
+		}
+		stream.WriteUint64(uint64(unknown))
+	}
+	stream.WriteArrayEnd()
+}
+
+func (c *flagsCodec[T]) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.ArrayValue:
+		var v T
+		ok := iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			bit, ok := c.decodeBit(iter)
+			v |= bit
+			return ok
+		})
+		if ok {
+			*(*T)(ptr) = v
+		}
+	case jsoniter.NumberValue:
+		if v, ok := c.decodeBit(iter); ok {
+			*(*T)(ptr) = v
+		}
+	case jsoniter.NilValue:
+		iter.Skip()
+	default:
+		iter.ReportError("jsonify.Flags", "expect array or number")
+	}
+}
+
+// decodeBit decodes a flag name or a number of bits.
+func (c *flagsCodec[T]) decodeBit(iter *jsoniter.Iterator) (T, bool) {
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		name := iter.ReadString()
+		v, ok := c.values[name]
+		if !ok {
+			iter.ReportError("jsonify.Flags", "unknown name "+strconv.Quote(name))
+		}
+		return v, ok
+	case jsoniter.NumberValue:
+		n := iter.ReadUint64()
+		v := T(n)
+		if uint64(v) != n {
+			iter.ReportError("jsonify.Flags", "value "+strconv.FormatUint(n, 10)+" overflows")
+			return 0, false
+		}
+		if v&^c.known != 0 && c.rejectUnknown {
+			iter.ReportError("jsonify.Flags", "unknown bits "+strconv.FormatUint(n, 10))
+			return 0, false
+		}
+		return v, true
+	}
+	iter.ReportError("jsonify.Flags", "expect string or number")
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type perm uint
+
+const (
+	permRead perm = 1 << iota
+	permWrite
+	permExec
+)
+
+type strictPerm uint8
+
+func init() {
+	jsonify.Flags(map[perm]string{permRead: "read", permWrite: "write", permExec: "exec"})
+	jsonify.Flags(map[strictPerm]string{1: "a", 2: "b"}, jsonify.RejectUnknown())
+}
+
+func ExampleFlags() {
+	type Feature uint
+	jsonify.Flags(map[Feature]string{1: "beta", 2: "dark-mode", 4: "offline"})
+
+	fmt.Println(jsonify.MustString(map[string]Feature{"user": 1 | 4}))
+
+	var f Feature
+	jsonify.Decode([]byte(`["dark-mode","offline"]`), &f)
+	fmt.Println(f)
+	// Output:
+	// {"user":["beta","offline"]}
+	// 6
+}
+
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "none", input: perm(0), expected: `[]`},
+		{name: "ordered by bit", input: permExec | permRead, expected: `["read","exec"]`},
+		{name: "unknown bits", input: permWrite | 8 | 16, expected: `["write",24]`},
+		{name: "only unknown bits", input: perm(8), expected: `[8]`},
+		{name: "rejected unknown", input: strictPerm(4), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlags_firstError(t *testing.T) {
+	_, err := jsonify.String([]any{strictPerm(1), strictPerm(4), strictPerm(8)})
+	if want := "jsonify: jsonify_test.strictPerm value 4 has unregistered bits 4"; err == nil || err.Error() != want {
+		t.Errorf("String() error = %v, want %v", err, want)
+	}
+}
+
+func TestFlagsDecode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected perm
+	}{
+		{`[]`, 0},
+		{`["exec","read"]`, permRead | permExec},
+		{`["write",24]`, permWrite | 24},
+		{`3`, permRead | permWrite},
+	}
+	for _, tt := range tests {
+		var got perm
+		if err := jsonify.Decode([]byte(tt.input), &got); err != nil {
+			t.Fatalf("Decode(%s) error = %v", tt.input, err)
+		}
+		if got != tt.expected {
+			t.Errorf("Decode(%s) = %d, want %d", tt.input, got, tt.expected)
+		}
+	}
+
+	for _, input := range []string{`["nope"]`, `"read"`, `[true]`, `[1024]`} {
+		var p strictPerm
+		if err := jsonify.Decode([]byte(input), &p); err == nil {
+			t.Errorf("Decode(%s) error = nil, want error", input)
+		}
+	}
+}
+
+func TestFlagsInvalidRegistration(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Flags() did not panic")
+		}
+	}()
+	type multi uint
+	jsonify.Flags(map[multi]string{3: "both"})
+}
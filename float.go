@@ -0,0 +1,105 @@
+package jsonify
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// FloatFormat is how finite floats are written.
+type FloatFormat struct {
+	fmt  byte // 0 for the shortest form, or a format of strconv.FormatFloat
+	prec int
+}
+
+var (
+	// FloatShortest writes the shortest decimal that reads back as the same
+	// float, with an exponent if it is less than 1e-6 or at least 1e21,
+	// e.g. 0.1, 1e-07 and 1e+21. It is the default.
+	FloatShortest = FloatFormat{}
+
+	// FloatPlain writes the shortest decimal that reads back as the same
+	// float, never with an exponent, e.g. 1000000000000000000000.
+	FloatPlain = FloatFormat{fmt: 'f', prec: -1}
+)
+
+// FloatFixed returns a FloatFormat that writes floats with places digits
+// after the decimal point, never with an exponent, e.g. 1.50 for 2 places.
+func FloatFixed(places int) FloatFormat {
+	return FloatFormat{fmt: 'f', prec: max(places, 0)}
+}
+
+// WithFloatFormat returns an [Option] that writes floats in format f.
+//
+// It applies to values of float kinds, except types implementing
+// [json.Marshaler] or [encoding.TextMarshaler] and types with a registered
+// encoder.
+func WithFloatFormat(f FloatFormat) Option {
+	return func(o *options) {
+		o.mode.float = f
+	}
+}
+
+func floatEncoderOf(typ reflect2.Type, m mode) jsoniter.ValEncoder {
+	if m.nan == NaNError && m.float == FloatShortest || hasMarshaler(typ) {
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Float32:
+		return &floatEncoder{nan: m.nan, format: m.float, bits: 32}
+	case reflect.Float64:
+		return &floatEncoder{nan: m.nan, format: m.float, bits: 64}
+	}
+	return nil
+}
+
+type floatEncoder struct {
+	nan    NaNPolicy
+	format FloatFormat
+	bits   int
+}
+
+func (e *floatEncoder) float(ptr unsafe.Pointer) float64 {
+	if e.bits == 32 {
+		return float64(*(*float32)(ptr))
+	}
+	return *(*float64)(ptr)
+}
+
+func (e *floatEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.float(ptr) == 0
+}
+
+func (e *floatEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	f := e.float(ptr)
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		e.encodeNaN(f, stream)
+	case e.format != FloatShortest:
+		stream.WriteRaw(strconv.FormatFloat(f, e.format.fmt, e.format.prec, e.bits))
+	case e.bits == 32:
+		stream.WriteFloat32(*(*float32)(ptr))
+	default:
+		stream.WriteFloat64(f)
+	}
+}
+
+func (e *floatEncoder) encodeNaN(f float64, stream *jsoniter.Stream) {
+	switch {
+	case e.nan == NaNError:
+		// The stream reports the error.
+		stream.WriteFloat64(f)
+	case e.nan == NaNAsNull:
+		stream.WriteNil()
+	case math.IsNaN(f):
+		stream.WriteString("NaN")
+	case f > 0:
+		stream.WriteString("Infinity")
+	default:
+		stream.WriteString("-Infinity")
+	}
+}
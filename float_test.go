@@ -0,0 +1,51 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithFloatFormat() {
+	prices := []float64{1.5, 2, 1e21}
+	fmt.Println(jsonify.MustString(prices))
+	fmt.Println(jsonify.MustString(prices, jsonify.WithFloatFormat(jsonify.FloatPlain)))
+	fmt.Println(jsonify.MustString(prices, jsonify.WithFloatFormat(jsonify.FloatFixed(2))))
+	// Output:
+	// [1.5,2,1e+21]
+	// [1.5,2,1000000000000000000000]
+	// [1.50,2.00,1000000000000000000000.00]
+}
+
+func TestWithFloatFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		format   jsonify.FloatFormat
+		opts     []jsonify.Option
+		expected string
+		wantErr  bool
+	}{
+		{name: "shortest", input: []float64{1e-7, 0.1}, format: jsonify.FloatShortest, expected: `[1e-07,0.1]`},
+		{name: "plain small", input: 1e-7, format: jsonify.FloatPlain, expected: `0.0000001`},
+		{name: "plain float32", input: float32(0.1), format: jsonify.FloatPlain, expected: `0.1`},
+		{name: "fixed zero places", input: 2.5, format: jsonify.FloatFixed(0), expected: `2`},
+		{name: "fixed negative places", input: 2.25, format: jsonify.FloatFixed(-1), expected: `2`},
+		{name: "fixed NaN", input: math.NaN(), format: jsonify.FloatFixed(2), wantErr: true},
+		{name: "fixed NaN as null", input: math.NaN(), format: jsonify.FloatFixed(2), opts: []jsonify.Option{jsonify.WithNaN(jsonify.NaNAsNull)}, expected: `null`},
+		{name: "marshaler", input: celsius(1e21), format: jsonify.FloatPlain, expected: `"1e+21 C"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, append(tt.opts, jsonify.WithFloatFormat(tt.format))...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
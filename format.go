@@ -0,0 +1,259 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// node is a parsed JSON value used to reformat encoded output. Scalars and
+// keys keep their original text.
+type node struct {
+	kind  byte // '{' or '[' for containers, 0 for scalars
+	raw   []byte
+	keys  [][]byte
+	elems []*node
+}
+
+// parseTree parses the JSON value data, which may contain insignificant
+// whitespace.
+func parseTree(data []byte) (*node, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("jsonify: cannot format invalid JSON")
+	}
+	n, _ := parseNode(data, skipSpace(data, 0))
+	return n, nil
+}
+
+// parseNode parses the valid JSON value at data[i:] and returns it with the
+// index following it.
+func parseNode(data []byte, i int) (*node, int) {
+	switch data[i] {
+	case '{', '[':
+		n := &node{kind: data[i]}
+		i = skipSpace(data, i+1)
+		for data[i] != '}' && data[i] != ']' {
+			if n.kind == '{' {
+				end := scanString(data, i)
+				n.keys = append(n.keys, data[i:end])
+				i = skipSpace(data, skipSpace(data, end)+1)
+			}
+			var elem *node
+			elem, i = parseNode(data, i)
+			n.elems = append(n.elems, elem)
+			i = skipSpace(data, i)
+			if data[i] == ',' {
+				i = skipSpace(data, i+1)
+			}
+		}
+		return n, i + 1
+	case '"':
+		end := scanString(data, i)
+		return &node{raw: data[i:end]}, end
+	}
+	end := i
+	for end < len(data) && !bytes.ContainsRune([]byte(",]} \t\r\n"), rune(data[end])) {
+		end++
+	}
+	return &node{raw: data[i:end]}, end
+}
+
+// scanString returns the index following the string starting at data[i].
+func scanString(data []byte, i int) int {
+	for i++; data[i] != '"'; i++ {
+		if data[i] == '\\' {
+			i++
+		}
+	}
+	return i + 1
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// closing returns the closing delimiter of a container.
+func (n *node) closing() byte {
+	if n.kind == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// isLeaf reports whether n contains no non-empty objects or arrays.
+func (n *node) isLeaf() bool {
+	for _, elem := range n.elems {
+		if len(elem.elems) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// appendInline appends n on a single line, with a space after each colon
+// and comma.
+func (n *node) appendInline(b []byte) []byte {
+	if n.kind == 0 {
+		return append(b, n.raw...)
+	}
+	b = append(b, n.kind)
+	for i, elem := range n.elems {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		if n.kind == '{' {
+			b = append(b, n.keys[i]...)
+			b = append(b, ": "...)
+		}
+		b = elem.appendInline(b)
+	}
+	return append(b, n.closing())
+}
+
+// indentOptions holds the arguments of [WithIndent].
+type indentOptions struct {
+	prefix, indent string
+}
+
+// WithIndent returns an [Option] that indents the output as [json.Indent]
+// does: each element of an object or array begins on a new line starting
+// with prefix followed by copies of indent per nesting level, e.g. "\t" or
+// four spaces, to match a team's formatting conventions:
+//
+//	var enc = jsonify.New(jsonify.WithIndent("", "\t"))
+//
+// A [proto.Message] is indented the same way, with prefix and indent as
+// they are, although protojson itself only indents with spaces and tabs.
+func WithIndent(prefix, indent string) Option {
+	return func(o *options) {
+		o.indent = &indentOptions{prefix: prefix, indent: indent}
+		o.format = func(b []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, b, prefix, indent); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+// WithSmartIndent returns an [Option] that indents objects and arrays by two
+// spaces, like [json.Indent], but keeps an object or array on one line if it
+// contains no other non-empty object or array and the line fits in width
+// columns:
+//
+//	{
+//	  "name": "api",
+//	  "ports": [80, 443],
+//	  "limits": {"cpu": 2, "memory": "1Gi"}
+//	}
+func WithSmartIndent(width int) Option {
+	return func(o *options) {
+		o.indent = nil
+		o.format = func(b []byte) ([]byte, error) {
+			return smartIndent(b, width)
+		}
+	}
+}
+
+func smartIndent(data []byte, width int) ([]byte, error) {
+	n, err := parseTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendSmart(nil, n, 0, 0, width), nil
+}
+
+// appendSmart appends n, which starts at column col of a line indented by
+// indent spaces.
+func appendSmart(b []byte, n *node, indent, col, width int) []byte {
+	if len(n.elems) == 0 || n.isLeaf() && col+len(n.appendInline(nil)) <= width {
+		return n.appendInline(b)
+	}
+	b = append(b, n.kind, '\n')
+	inner := indent + 2
+	for i, elem := range n.elems {
+		b = append(b, bytes.Repeat([]byte{' '}, inner)...)
+		col := inner
+		if n.kind == '{' {
+			b = append(b, n.keys[i]...)
+			b = append(b, ": "...)
+			col += len(n.keys[i]) + 2
+		}
+		b = appendSmart(b, elem, inner, col, width)
+		if i < len(n.elems)-1 {
+			b = append(b, ',')
+		}
+		b = append(b, '\n')
+	}
+	b = append(b, bytes.Repeat([]byte{' '}, indent)...)
+	return append(b, n.closing())
+}
+
+// WithAlignedIndent returns an [Option] that indents objects and arrays by
+// two spaces and aligns the values of each object in a column, for output
+// reviewed by humans such as golden files:
+//
+//	{
+//	  "id":   1,
+//	  "name": "Ann"
+//	}
+//
+// Only whitespace is added, so [Compact] of the output reproduces the
+// output without the option byte for byte.
+func WithAlignedIndent() Option {
+	return func(o *options) {
+		o.indent = nil
+		o.format = alignedIndent
+	}
+}
+
+func alignedIndent(data []byte) ([]byte, error) {
+	n, err := parseTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendAligned(nil, n, 0), nil
+}
+
+// appendAligned appends n, which starts on a line indented by indent spaces.
+func appendAligned(b []byte, n *node, indent int) []byte {
+	if len(n.elems) == 0 {
+		return n.appendInline(b)
+	}
+	keyWidth := 0
+	for _, key := range n.keys {
+		keyWidth = max(keyWidth, len(key))
+	}
+	b = append(b, n.kind, '\n')
+	inner := indent + 2
+	for i, elem := range n.elems {
+		b = append(b, bytes.Repeat([]byte{' '}, inner)...)
+		if n.kind == '{' {
+			b = append(b, n.keys[i]...)
+			b = append(b, ':')
+			b = append(b, bytes.Repeat([]byte{' '}, keyWidth-len(n.keys[i])+1)...)
+		}
+		b = appendAligned(b, elem, inner)
+		if i < len(n.elems)-1 {
+			b = append(b, ',')
+		}
+		b = append(b, '\n')
+	}
+	b = append(b, bytes.Repeat([]byte{' '}, indent)...)
+	return append(b, n.closing())
+}
+
+// Compact returns data with insignificant whitespace removed, e.g. to turn
+// the output of [WithSmartIndent] or [WithAlignedIndent] back into the
+// compact form.
+func Compact(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithIndent() {
+	fmt.Println(jsonify.MustString(map[string]any{"name": "api", "ports": []int{80}}, jsonify.WithIndent("", "  ")))
+	// Output:
+	// {
+	//   "name": "api",
+	//   "ports": [
+	//     80
+	//   ]
+	// }
+}
+
+func TestWithIndent(t *testing.T) {
+	got, err := jsonify.String(map[string]any{"a": []int{}, "b": 1}, jsonify.WithIndent("> ", "\t"))
+	if expected := "{\n> \t\"a\": [],\n> \t\"b\": 1\n> }"; err != nil || got != expected {
+		t.Errorf("String() = %q, %v, want %q", got, err, expected)
+	}
+	got, err = jsonify.String([]int{1}, jsonify.WithIndent("", " "), jsonify.WithSmartIndent(80))
+	if err != nil || got != "[1]" {
+		t.Errorf("String() with a later option = %q, %v, want [1]", got, err)
+	}
+
+	// Per instance, and per call.
+	enc := jsonify.New(jsonify.WithIndent("", "\t"))
+	v := map[string][]int{"a": {1}}
+	tests := []struct {
+		name string
+		opts []jsonify.Option
+		want string
+	}{
+		{name: "instance", want: "{\n\t\"a\": [\n\t\t1\n\t]\n}"},
+		{name: "four spaces", opts: []jsonify.Option{jsonify.WithIndent("", "    ")}, want: "{\n    \"a\": [\n        1\n    ]\n}"},
+		{name: "any characters", opts: []jsonify.Option{jsonify.WithIndent("//", "..")}, want: "{\n//..\"a\": [\n//....1\n//..]\n//}"},
+	}
+	for _, tt := range tests {
+		if got := enc.MustString(v, tt.opts...); got != tt.want {
+			t.Errorf("%s: MustString() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func ExampleWithSmartIndent() {
+	config := map[string]any{
+		"name":   "api",
+		"ports":  []int{80, 443},
+		"limits": map[string]any{"cpu": 2, "memory": "1Gi"},
+		"routes": []map[string]string{{"path": "/", "to": "web"}},
+	}
+	fmt.Println(jsonify.MustString(config, jsonify.WithSmartIndent(40)))
+	// Output:
+	// {
+	//   "limits": {"cpu": 2, "memory": "1Gi"},
+	//   "name": "api",
+	//   "ports": [80, 443],
+	//   "routes": [
+	//     {"path": "/", "to": "web"}
+	//   ]
+	// }
+}
+
+func TestWithSmartIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		width    int
+		expected string
+	}{
+		{name: "scalar", input: 1, width: 80, expected: `1`},
+		{name: "leaf fits", input: []int{1, 2}, width: 80, expected: `[1, 2]`},
+		{name: "empty", input: map[string]any{"a": []int{}, "b": map[string]int{}}, width: 80, expected: `{"a": [], "b": {}}`},
+		{name: "leaf too wide", input: []string{"abc", "def"}, width: 10, expected: "[\n  \"abc\",\n  \"def\"\n]"},
+		{name: "key counts", input: map[string][]int{"key": {1, 2}}, width: 14, expected: "{\n  \"key\": [\n    1,\n    2\n  ]\n}"},
+		{name: "nested", input: [][]int{{1}}, width: 80, expected: "[\n  [1]\n]"},
+		{name: "raw", input: json.RawMessage(` { "a" : "x\"y" , "b":[ ] } `), width: 80, expected: `{"a": "x\"y", "b": []}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, jsonify.WithSmartIndent(tt.width))
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := jsonify.String(json.RawMessage(`{`), jsonify.WithSmartIndent(80)); err == nil {
+		t.Errorf("String() of invalid JSON error = nil, want error")
+	}
+}
+
+func ExampleWithAlignedIndent() {
+	v := map[string]any{"id": 1, "name": "Ann", "tags": []string{"a"}, "meta": map[string]any{}}
+	fmt.Println(jsonify.MustString(v, jsonify.WithAlignedIndent()))
+	// Output:
+	// {
+	//   "id":   1,
+	//   "meta": {},
+	//   "name": "Ann",
+	//   "tags": [
+	//     "a"
+	//   ]
+	// }
+}
+
+func TestWithAlignedIndent(t *testing.T) {
+	inputs := []any{
+		1,
+		[]any{},
+		map[string]any{"a": []any{1, map[string]any{"bb": "x y", "c": nil}}, "long key": "</>"},
+		json.RawMessage(` [ {"k" :"v\" ,"} ] `),
+	}
+	for _, input := range inputs {
+		aligned, err := jsonify.Bytes(input, jsonify.WithAlignedIndent())
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		compact, err := jsonify.Compact(aligned)
+		if err != nil {
+			t.Fatalf("Compact() error = %v", err)
+		}
+		want, _ := jsonify.Compact(jsonify.MustBytes(input))
+		if string(compact) != string(want) {
+			t.Errorf("Compact(%s) = %s, want %s", aligned, compact, want)
+		}
+	}
+
+	if _, err := jsonify.Compact([]byte(`{`)); err == nil {
+		t.Errorf("Compact() of invalid JSON error = nil, want error")
+	}
+}
@@ -0,0 +1,115 @@
+package jsonify
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// MaxFrameSize is the largest payload [ReadFrame] accepts, to bound the
+// memory a corrupt or hostile peer can make it allocate.
+const MaxFrameSize = 64 << 20
+
+var (
+	// ErrFrameTooLarge is returned by [ReadFrame] for a frame whose payload
+	// is larger than [MaxFrameSize].
+	ErrFrameTooLarge = errors.New("jsonify: frame too large")
+
+	// ErrFrameChecksum is returned by [ReadFrame] for a frame whose CRC does
+	// not match its payload.
+	ErrFrameChecksum = errors.New("jsonify: frame checksum mismatch")
+)
+
+// FrameOption configures [Frame].
+type FrameOption func(*frameOptions)
+
+type frameOptions struct {
+	crc bool
+}
+
+// WithCRC returns a [FrameOption] that appends a CRC-32C of the payload to
+// the frame, which [ReadFrame] verifies.
+func WithCRC() FrameOption {
+	return func(o *frameOptions) {
+		o.crc = true
+	}
+}
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Frame writes v encoded with [Bytes] to w as a length-prefixed frame, for
+// stream protocols such as raw TCP or unix sockets.
+//
+// A frame is a header, the payload, and an optional checksum. The header is
+// an unsigned varint, as written by [binary.AppendUvarint], of the payload
+// length shifted left by one, with the lowest bit set if the frame has a
+// checksum. The checksum is the CRC-32C of the payload in big-endian order.
+// The frame does not depend on the byte order of either host.
+func Frame(w io.Writer, v any, opts ...FrameOption) error {
+	var o frameOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	payload, err := Bytes(v)
+	if err != nil {
+		return err
+	}
+	header := uint64(len(payload)) << 1
+	if o.crc {
+		header |= 1
+	}
+	b := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(payload)+4), header)
+	b = append(b, payload...)
+	if o.crc {
+		b = binary.BigEndian.AppendUint32(b, crc32.Checksum(payload, crcTable))
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadFrame reads a frame written by [Frame] from r and returns its payload.
+// It returns [io.EOF] if r is at the end before the frame starts, and
+// [io.ErrUnexpectedEOF] if r ends within the frame.
+func ReadFrame(r io.Reader) (json.RawMessage, error) {
+	header, err := binary.ReadUvarint(byteReader{r})
+	if err != nil {
+		return nil, err
+	}
+	size := header >> 1
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+	}
+	n := int(size)
+	if header&1 != 0 {
+		n += 4
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	payload := b[:size]
+	if header&1 != 0 && binary.BigEndian.Uint32(b[size:]) != crc32.Checksum(payload, crcTable) {
+		return nil, ErrFrameChecksum
+	}
+	return payload, nil
+}
+
+// byteReader reads single bytes from r without reading ahead.
+type byteReader struct {
+	r io.Reader
+}
+
+func (br byteReader) ReadByte() (byte, error) {
+	if r, ok := br.r.(io.ByteReader); ok {
+		return r.ReadByte()
+	}
+	var b [1]byte
+	_, err := io.ReadFull(br.r, b[:])
+	return b[0], err
+}
@@ -0,0 +1,67 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleFrame() {
+	var conn bytes.Buffer
+	jsonify.Frame(&conn, map[string]any{"cmd": "ping"})
+	jsonify.Frame(&conn, map[string]any{"cmd": "stop"}, jsonify.WithCRC())
+
+	for {
+		payload, err := jsonify.ReadFrame(&conn)
+		if err == io.EOF {
+			break
+		}
+		fmt.Printf("%s %v\n", payload, err)
+	}
+	// Output:
+	// {"cmd":"ping"} <nil>
+	// {"cmd":"stop"} <nil>
+}
+
+func TestFrame(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jsonify.Frame(&buf, "x"); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	if got := buf.Bytes(); !bytes.Equal(got, []byte("\x06\"x\"")) {
+		t.Errorf("Frame() = %q", got)
+	}
+	if err := jsonify.Frame(&buf, make(chan int)); err == nil {
+		t.Errorf("Frame() of an unsupported value error = nil, want error")
+	}
+
+	buf.Reset()
+	jsonify.Frame(&buf, []int{1, 2}, jsonify.WithCRC())
+	frame := buf.Bytes()
+	got, err := jsonify.ReadFrame(iotest.OneByteReader(bytes.NewReader(frame)))
+	if err != nil || string(got) != `[1,2]` {
+		t.Errorf("ReadFrame() = %s, %v", got, err)
+	}
+
+	corrupt := bytes.Clone(frame)
+	corrupt[2] = '3'
+	if _, err := jsonify.ReadFrame(bytes.NewReader(corrupt)); !errors.Is(err, jsonify.ErrFrameChecksum) {
+		t.Errorf("ReadFrame() of a corrupt frame error = %v", err)
+	}
+	if _, err := jsonify.ReadFrame(bytes.NewReader(frame[:len(frame)-1])); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadFrame() of a truncated frame error = %v", err)
+	}
+	if _, err := jsonify.ReadFrame(bytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("ReadFrame() at the end error = %v", err)
+	}
+	huge := binary.AppendUvarint(nil, (jsonify.MaxFrameSize+1)<<1)
+	if _, err := jsonify.ReadFrame(bytes.NewReader(huge)); !errors.Is(err, jsonify.ErrFrameTooLarge) {
+		t.Errorf("ReadFrame() of a huge frame error = %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package jsonify
+
+import "encoding/json"
+
+// Func returns a [json.Marshaler] that encodes v with fn, to attach a one-off
+// representation inline without defining a wrapper type:
+//
+//	jsonify.Bytes(map[string]any{
+//		"id": jsonify.Func(id, func(v any) ([]byte, error) {
+//			return jsonify.Bytes(fmt.Sprint(v))
+//		}),
+//	})
+//
+// fn must return a single valid JSON value.
+func Func(v any, fn func(v any) ([]byte, error)) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) { return fn(v) })
+}
+
+// As is the type-safe variant of [Func].
+func As[T any](v T, fn func(v T) ([]byte, error)) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) { return fn(v) })
+}
+
+type marshalerFunc func() ([]byte, error)
+
+func (fn marshalerFunc) MarshalJSON() ([]byte, error) {
+	return fn()
+}
+
+// optionsMarshalerFunc is a [json.Marshaler] encoding with the options of
+// the call encoding it, see [optionsMarshaler].
+type optionsMarshalerFunc func(o *options) ([]byte, error)
+
+func (fn optionsMarshalerFunc) MarshalJSON() ([]byte, error) {
+	return fn(newOptions(nil))
+}
+
+func (fn optionsMarshalerFunc) marshalJSON(o *options) ([]byte, error) {
+	return fn(o)
+}
@@ -0,0 +1,56 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleAs() {
+	d := 90 * time.Second
+	fmt.Println(jsonify.MustString(map[string]any{
+		"timeout": jsonify.As(d, func(d time.Duration) ([]byte, error) {
+			return jsonify.Bytes(d.String())
+		}),
+	}))
+	// Output:
+	// {"timeout":"1m30s"}
+}
+
+func TestFunc(t *testing.T) {
+	quote := func(v any) ([]byte, error) {
+		return []byte(strconv.Quote(fmt.Sprint(v))), nil
+	}
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "top level", input: jsonify.Func(42, quote), expected: `"42"`},
+		{name: "nested", input: []any{1, jsonify.Func(2, quote)}, expected: `[1,"2"]`},
+		{name: "as", input: jsonify.As(3, func(n int) ([]byte, error) { return jsonify.Bytes(n * 2) }), expected: `6`},
+		{
+			name: "error",
+			input: jsonify.Func(nil, func(any) ([]byte, error) {
+				return nil, errors.New("failed")
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package gateway provides a grpc-gateway [runtime.Marshaler] backed by
+// jsonify, so that a gateway emits the same compact JSON as the rest of a
+// service, with protobuf messages encoded by protojson without HTML
+// escaping and in a reproducible form:
+//
+//	mux := runtime.NewServeMux(
+//		runtime.WithMarshalerOption(runtime.MIMEWildcard, &gateway.Marshaler{}),
+//	)
+//
+// The zero value encodes and decodes with the defaults of [jsonify.Bytes]
+// and [jsonify.Decode].
+//
+// The package is a module of its own, github.com/goaux/jsonify/gateway, so
+// that grpc-gateway and gRPC are only required by programs using it.
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/goaux/jsonify"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// Marshaler is a [runtime.Marshaler] using jsonify.
+type Marshaler struct {
+	// Options are the options of every encoding, e.g.
+	// [jsonify.WithProtoOptions] to emit unpopulated fields.
+	Options []jsonify.Option
+
+	// DecodeOptions are the options of every decoding, e.g.
+	// [jsonify.WithDisallowUnknownFields] to reject unknown fields.
+	DecodeOptions []jsonify.DecodeOption
+}
+
+var (
+	_ runtime.Marshaler = (*Marshaler)(nil)
+	_ runtime.Delimited = (*Marshaler)(nil)
+)
+
+// ContentType returns "application/json".
+func (m *Marshaler) ContentType(v any) string {
+	return "application/json"
+}
+
+// Marshal encodes v with [jsonify.Bytes].
+func (m *Marshaler) Marshal(v any) ([]byte, error) {
+	return jsonify.Bytes(v, m.Options...)
+}
+
+// Unmarshal decodes data into v with [jsonify.Decode].
+func (m *Marshaler) Unmarshal(data []byte, v any) error {
+	return jsonify.Decode(data, v, m.DecodeOptions...)
+}
+
+// NewDecoder returns a [runtime.Decoder] that decodes a stream of JSON
+// values from r with [jsonify.Decode].
+func (m *Marshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	dec := json.NewDecoder(r)
+	return runtime.DecoderFunc(func(v any) error {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		return m.Unmarshal(raw, v)
+	})
+}
+
+// NewEncoder returns a [runtime.Encoder] that writes each value to w with
+// [jsonify.Bytes], followed by the [Marshaler.Delimiter].
+func (m *Marshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v any) error {
+		b, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, m.Delimiter()...))
+		return err
+	})
+}
+
+// Delimiter returns "\n", which separates the messages of a streaming
+// response.
+func (m *Marshaler) Delimiter() []byte {
+	return []byte("\n")
+}
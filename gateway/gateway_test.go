@@ -0,0 +1,104 @@
+package gateway_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"github.com/goaux/jsonify/gateway"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func Example() {
+	m := &gateway.Marshaler{}
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, m))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/v1/users/1", nil)
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{})
+	runtime.HTTPError(ctx, mux, m, w, r, status.Error(codes.NotFound, "user <1> not found"))
+	fmt.Println(w.Code, w.Body)
+	// Output:
+	// 404 {"code":5,"message":"user <1> not found"}
+}
+
+func TestMarshaler(t *testing.T) {
+	msg, err := structpb.NewStruct(map[string]any{"b": "<b>", "a": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &gateway.Marshaler{}
+	if got := m.ContentType(msg); got != "application/json" {
+		t.Errorf("ContentType() = %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		m        *gateway.Marshaler
+		value    any
+		expected string
+	}{
+		{name: "message", m: m, value: msg, expected: `{"a":null,"b":"<b>"}`},
+		{name: "field", m: m, value: map[string]any{"z": 1, "y": "<y>"}, expected: `{"y":"<y>","z":1}`},
+		{
+			name:     "options",
+			m:        &gateway.Marshaler{Options: []jsonify.Option{jsonify.WithIndent("", " ")}},
+			value:    msg,
+			expected: "{\n \"a\": null,\n \"b\": \"<b>\"\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Marshal() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+
+	var got structpb.Struct
+	if err := m.Unmarshal([]byte(`{"a":1}`), &got); err != nil || got.Fields["a"].GetNumberValue() != 1 {
+		t.Errorf("Unmarshal() = %v, %v", &got, err)
+	}
+	strict := &gateway.Marshaler{DecodeOptions: []jsonify.DecodeOption{jsonify.WithDisallowUnknownFields()}}
+	var field structpb.ListValue
+	if err := strict.Unmarshal([]byte(`{"values":[],"bogus":1}`), &field); err == nil {
+		t.Errorf("Unmarshal() with WithDisallowUnknownFields error = nil, want error")
+	}
+}
+
+func TestMarshalerStream(t *testing.T) {
+	m := &gateway.Marshaler{}
+	var buf bytes.Buffer
+	enc := m.NewEncoder(&buf)
+	for _, v := range []any{map[string]int{"n": 1}, map[string]int{"n": 2}} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if want := "{\"n\":1}\n{\"n\":2}\n"; buf.String() != want {
+		t.Errorf("NewEncoder() wrote %q, want %q", buf.String(), want)
+	}
+
+	dec := m.NewDecoder(strings.NewReader(buf.String()))
+	for want := 1; want <= 2; want++ {
+		var v struct{ N int }
+		if err := dec.Decode(&v); err != nil || v.N != want {
+			t.Errorf("Decode() = %v, %v, want %d", v, err, want)
+		}
+	}
+	var v any
+	if err := dec.Decode(&v); err == nil {
+		t.Errorf("Decode() at the end of the stream error = nil, want io.EOF")
+	}
+}
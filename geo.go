@@ -0,0 +1,196 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// LatLng is a geographic position in degrees.
+//
+// It encodes as a GeoJSON Point geometry (RFC 7946). Note that GeoJSON
+// orders coordinates as longitude first:
+//
+//	{"type":"Point","coordinates":[139.767,35.681]}
+//
+// Decoding null leaves a LatLng, as well as a [LineString] or a [Polygon],
+// unchanged.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// LineString is a sequence of positions that encodes as a GeoJSON
+// LineString geometry.
+type LineString []LatLng
+
+// Polygon is a list of linear rings that encodes as a GeoJSON Polygon
+// geometry. The first ring is the exterior ring and any others are holes.
+//
+// Rings are encoded as given; it is up to the caller to close them by
+// repeating the first position at the end.
+type Polygon [][]LatLng
+
+type geometryJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Validate reports whether p is within the valid latitude and longitude
+// ranges.
+func (p LatLng) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("jsonify: latitude %v is out of range", p.Lat)
+	}
+	if math.IsNaN(p.Lng) || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("jsonify: longitude %v is out of range", p.Lng)
+	}
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (p LatLng) MarshalJSON() ([]byte, error) {
+	b, err := appendPosition([]byte(`{"type":"Point","coordinates":`), p)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (p *LatLng) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var pos []float64
+	if err := unmarshalGeometry(data, "Point", &pos); err != nil {
+		return err
+	}
+	v, err := toLatLng(pos)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (l LineString) MarshalJSON() ([]byte, error) {
+	b, err := appendPositions([]byte(`{"type":"LineString","coordinates":`), l)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (l *LineString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var coords [][]float64
+	if err := unmarshalGeometry(data, "LineString", &coords); err != nil {
+		return err
+	}
+	v, err := toLatLngs(coords)
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	b := []byte(`{"type":"Polygon","coordinates":[`)
+	for i, ring := range p {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = appendPositions(b, ring); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, ']', '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var coords [][][]float64
+	if err := unmarshalGeometry(data, "Polygon", &coords); err != nil {
+		return err
+	}
+	v := make(Polygon, len(coords))
+	for i, ring := range coords {
+		var err error
+		if v[i], err = toLatLngs(ring); err != nil {
+			return err
+		}
+	}
+	*p = v
+	return nil
+}
+
+func appendPosition(b []byte, p LatLng) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	b = append(b, '[')
+	b = strconv.AppendFloat(b, p.Lng, 'f', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, p.Lat, 'f', -1, 64)
+	return append(b, ']'), nil
+}
+
+func appendPositions(b []byte, ps []LatLng) ([]byte, error) {
+	b = append(b, '[')
+	for i, p := range ps {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = appendPosition(b, p); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, ']'), nil
+}
+
+func unmarshalGeometry(data []byte, typ string, coords any) error {
+	var g geometryJSON
+	if err := json.Unmarshal(data, &g); err != nil {
+		return err
+	}
+	if g.Type != typ {
+		return fmt.Errorf("jsonify: geometry type is %q, want %q", g.Type, typ)
+	}
+	if len(g.Coordinates) == 0 {
+		return fmt.Errorf("jsonify: %s geometry has no coordinates", typ)
+	}
+	return json.Unmarshal(g.Coordinates, coords)
+}
+
+func toLatLng(pos []float64) (LatLng, error) {
+	// A third element is an altitude, which LatLng does not keep.
+	if len(pos) != 2 && len(pos) != 3 {
+		return LatLng{}, fmt.Errorf("jsonify: position has %d elements, want 2 or 3", len(pos))
+	}
+	p := LatLng{Lat: pos[1], Lng: pos[0]}
+	return p, p.Validate()
+}
+
+func toLatLngs(coords [][]float64) ([]LatLng, error) {
+	ps := make([]LatLng, len(coords))
+	for i, pos := range coords {
+		var err error
+		if ps[i], err = toLatLng(pos); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
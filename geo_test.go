@@ -0,0 +1,97 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleLatLng() {
+	fmt.Println(jsonify.MustString(jsonify.LatLng{Lat: 35.681, Lng: 139.767}))
+	// Output:
+	// {"type":"Point","coordinates":[139.767,35.681]}
+}
+
+func TestGeo(t *testing.T) {
+	tokyo := jsonify.LatLng{Lat: 35.681, Lng: 139.767}
+	osaka := jsonify.LatLng{Lat: 34.702, Lng: 135.495}
+	tests := []struct {
+		name     string
+		input    any
+		target   any
+		expected string
+	}{
+		{
+			name:     "point",
+			input:    tokyo,
+			target:   new(jsonify.LatLng),
+			expected: `{"type":"Point","coordinates":[139.767,35.681]}`,
+		},
+		{
+			name:     "line string",
+			input:    jsonify.LineString{tokyo, osaka},
+			target:   new(jsonify.LineString),
+			expected: `{"type":"LineString","coordinates":[[139.767,35.681],[135.495,34.702]]}`,
+		},
+		{
+			name:     "polygon",
+			input:    jsonify.Polygon{{tokyo, osaka, {Lat: 35, Lng: 136}, tokyo}},
+			target:   new(jsonify.Polygon),
+			expected: `{"type":"Polygon","coordinates":[[[139.767,35.681],[135.495,34.702],[136,35],[139.767,35.681]]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+			if err := json.Unmarshal([]byte(got), tt.target); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if decoded := reflect.ValueOf(tt.target).Elem().Interface(); !reflect.DeepEqual(decoded, tt.input) {
+				t.Errorf("round trip = %v, want %v", decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestGeoInvalid(t *testing.T) {
+	if _, err := jsonify.Bytes(jsonify.LatLng{Lat: 91}); err == nil {
+		t.Errorf("Bytes() accepted an out of range latitude")
+	}
+	inputs := []string{
+		`{"type":"LineString","coordinates":[1,2]}`,
+		`{"type":"Point","coordinates":[1]}`,
+		`{"type":"Point","coordinates":[200,0]}`,
+		`{"type":"Point"}`,
+	}
+	for _, input := range inputs {
+		var p jsonify.LatLng
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want error", input)
+		}
+	}
+}
+
+func TestGeoUnmarshalNull(t *testing.T) {
+	var v struct {
+		Point   jsonify.LatLng     `json:"point"`
+		Line    jsonify.LineString `json:"line"`
+		Polygon jsonify.Polygon    `json:"polygon"`
+	}
+	p := jsonify.LatLng{Lat: 35.681, Lng: 139.767}
+	v.Point, v.Line, v.Polygon = p, jsonify.LineString{p}, jsonify.Polygon{{p}}
+	if err := jsonify.Decode([]byte(`{"point":null,"line":null,"polygon":null}`), &v); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if v.Point != p || len(v.Line) != 1 || len(v.Polygon) != 1 {
+		t.Errorf("Decode() of null changed the geometries to %v", v)
+	}
+}
@@ -1,13 +1,14 @@
 module github.com/goaux/jsonify
 
-go 1.20
+go 1.23
 
 require (
 	github.com/json-iterator/go v1.1.12
+	github.com/modern-go/reflect2 v1.0.2
 	google.golang.org/protobuf v1.34.2
 )
 
 require (
+	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
-	github.com/modern-go/reflect2 v1.0.2 // indirect
 )
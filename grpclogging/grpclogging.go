@@ -0,0 +1,243 @@
+// Package grpclogging provides gRPC interceptors that log the messages of
+// calls with [log/slog], encoded with jsonify, so that protobuf payloads
+// appear in logs in their protojson form rather than as %v dumps:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpclogging.UnaryServerInterceptor(logger)),
+//		grpc.ChainStreamInterceptor(grpclogging.StreamServerInterceptor(logger)),
+//	)
+//
+// A unary call is logged once it completes, with its request and response.
+// A stream is logged once per message, and once it completes. Messages can
+// be redacted with [WithRedactor] before they are encoded.
+//
+// The package is a module of its own, github.com/goaux/jsonify/grpclogging,
+// so that gRPC is only required by programs using it.
+package grpclogging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Option configures the interceptors.
+type Option func(*config)
+
+type config struct {
+	logger *slog.Logger
+	level  slog.Level
+	redact func(method string, m proto.Message)
+	opts   []jsonify.Option
+}
+
+// WithRedactor returns an [Option] that calls fn with a copy of each
+// message before it is logged, e.g. to clear secrets:
+//
+//	grpclogging.WithRedactor(func(method string, m proto.Message) {
+//		if req, ok := m.(*pb.LoginRequest); ok {
+//			req.Password = ""
+//		}
+//	})
+//
+// The messages of the call itself are not modified.
+func WithRedactor(fn func(method string, m proto.Message)) Option {
+	return func(c *config) {
+		c.redact = fn
+	}
+}
+
+// WithLevel returns an [Option] that logs calls and messages at level,
+// slog.LevelInfo by default. Failed calls are logged at slog.LevelError.
+func WithLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+// WithEncodeOptions returns an [Option] that encodes messages with opts,
+// e.g. [jsonify.WithMaxOutputBytes] to bound the size of log records.
+func WithEncodeOptions(opts ...jsonify.Option) Option {
+	return func(c *config) {
+		c.opts = opts
+	}
+}
+
+func newConfig(logger *slog.Logger, opts []Option) *config {
+	c := &config{logger: logger, level: slog.LevelInfo}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// payload is an encoded message, which is logged as raw JSON by
+// [slog.JSONHandler] and as text by other handlers.
+type payload []byte
+
+func (p payload) MarshalJSON() ([]byte, error) { return p, nil }
+func (p payload) MarshalText() ([]byte, error) { return p, nil }
+
+// payload encodes m, redacted if needed, for a log record. A message that
+// cannot be encoded is logged as the error.
+func (c *config) payload(method string, m any) slog.Value {
+	if pm, ok := m.(proto.Message); ok && c.redact != nil && pm.ProtoReflect().IsValid() {
+		pm = proto.Clone(pm)
+		c.redact(method, pm)
+		m = pm
+	}
+	b, err := jsonify.Bytes(m, c.opts...)
+	if err != nil {
+		return slog.StringValue(err.Error())
+	}
+	return slog.AnyValue(payload(b))
+}
+
+// logCall logs a completed call, with attrs, e.g. its messages.
+func (c *config) logCall(ctx context.Context, side, method string, start time.Time, err error, attrs ...slog.Attr) {
+	level := c.level
+	if err != nil {
+		level = slog.LevelError
+	}
+	attrs = append([]slog.Attr{
+		slog.String("grpc.side", side),
+		slog.String("grpc.method", method),
+		slog.String("grpc.code", status.Code(err).String()),
+		slog.Duration("grpc.duration", time.Since(start)),
+	}, attrs...)
+	if err != nil {
+		attrs = append(attrs, slog.String("grpc.error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, level, "grpc call", attrs...)
+}
+
+// logMessage logs a message of a stream sent or received by side.
+func (c *config) logMessage(ctx context.Context, side, method, direction string, m any) {
+	c.logger.LogAttrs(ctx, c.level, "grpc message",
+		slog.String("grpc.side", side),
+		slog.String("grpc.method", method),
+		slog.String("grpc.direction", direction),
+		slog.Attr{Key: "grpc.message", Value: c.payload(method, m)},
+	)
+}
+
+// UnaryServerInterceptor returns an interceptor logging the unary calls
+// served, with their requests and responses.
+func UnaryServerInterceptor(logger *slog.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(logger, opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		attrs := []slog.Attr{{Key: "grpc.request", Value: c.payload(info.FullMethod, req)}}
+		if err == nil {
+			attrs = append(attrs, slog.Attr{Key: "grpc.response", Value: c.payload(info.FullMethod, resp)})
+		}
+		c.logCall(ctx, "server", info.FullMethod, start, err, attrs...)
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor logging the unary calls
+// made, with their requests and responses.
+func UnaryClientInterceptor(logger *slog.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(logger, opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		attrs := []slog.Attr{{Key: "grpc.request", Value: c.payload(method, req)}}
+		if err == nil {
+			attrs = append(attrs, slog.Attr{Key: "grpc.response", Value: c.payload(method, reply)})
+		}
+		c.logCall(ctx, "client", method, start, err, attrs...)
+		return err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging the messages of
+// the streams served, and the streams once they complete.
+func StreamServerInterceptor(logger *slog.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(logger, opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, config: c, method: info.FullMethod})
+		c.logCall(ss.Context(), "server", info.FullMethod, start, err)
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	config *config
+	method string
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.config.logMessage(s.Context(), "server", s.method, "sent", m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.config.logMessage(s.Context(), "server", s.method, "received", m)
+	}
+	return err
+}
+
+// StreamClientInterceptor returns an interceptor logging the messages of
+// the streams opened, and the streams once they fail or end.
+func StreamClientInterceptor(logger *slog.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(logger, opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			c.logCall(ctx, "client", method, start, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, config: c, method: method, start: start}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	config *config
+	method string
+	start  time.Time
+	done   bool
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.config.logMessage(s.Context(), "client", s.method, "sent", m)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.config.logMessage(s.Context(), "client", s.method, "received", m)
+	case !s.done:
+		s.done = true
+		logErr := err
+		if err == io.EOF {
+			// The stream ended successfully.
+			logErr = nil
+		}
+		s.config.logCall(s.Context(), "client", s.method, s.start, logErr)
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package grpclogging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goaux/jsonify/grpclogging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// syncBuffer is a buffer that the server and client log to concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var r map[string]any
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid log record %s: %v", line, err)
+		}
+		delete(r, "time")
+		delete(r, "grpc.duration")
+		records = append(records, r)
+	}
+	b.buf.Reset()
+	return records
+}
+
+func TestInterceptors(t *testing.T) {
+	var logs syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	redact := grpclogging.WithRedactor(func(method string, m proto.Message) {
+		if req, ok := m.(*healthpb.HealthCheckRequest); ok && req.Service == "secret" {
+			req.Service = "<redacted>"
+		}
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpclogging.UnaryServerInterceptor(logger, redact)),
+		grpc.ChainStreamInterceptor(grpclogging.StreamServerInterceptor(logger)),
+	)
+	hs := health.NewServer()
+	hs.SetServingStatus("secret", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, hs)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(grpclogging.UnaryClientInterceptor(logger, grpclogging.WithLevel(slog.LevelWarn))),
+		grpc.WithChainStreamInterceptor(grpclogging.StreamClientInterceptor(logger)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	t.Run("unary", func(t *testing.T) {
+		req := &healthpb.HealthCheckRequest{Service: "secret"}
+		if _, err := client.Check(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Service != "secret" {
+			t.Errorf("the redactor modified the request: %v", req)
+		}
+		want := []map[string]any{
+			{
+				"level": "INFO", "msg": "grpc call", "grpc.side": "server", "grpc.method": "/grpc.health.v1.Health/Check", "grpc.code": "OK",
+				"grpc.request": map[string]any{"service": "<redacted>"}, "grpc.response": map[string]any{"status": "SERVING"},
+			},
+			{
+				"level": "WARN", "msg": "grpc call", "grpc.side": "client", "grpc.method": "/grpc.health.v1.Health/Check", "grpc.code": "OK",
+				"grpc.request": map[string]any{"service": "secret"}, "grpc.response": map[string]any{"status": "SERVING"},
+			},
+		}
+		if got := logs.records(t); !equalJSON(got, want) {
+			t.Errorf("logged %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unary error", func(t *testing.T) {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"}); err == nil {
+			t.Fatal("Check() error = nil, want error")
+		}
+		for _, r := range logs.records(t) {
+			if r["level"] != "ERROR" || r["grpc.code"] != "NotFound" || r["grpc.response"] != nil || r["grpc.error"] == nil {
+				t.Errorf("logged %v", r)
+			}
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "secret"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		if _, err := stream.Recv(); err == nil {
+			t.Fatal("Recv() after cancel error = nil, want error")
+		}
+		server.GracefulStop()
+
+		var messages, calls []string
+		for _, r := range logs.records(t) {
+			switch r["msg"] {
+			case "grpc message":
+				messages = append(messages, r["grpc.side"].(string)+" "+r["grpc.direction"].(string))
+			case "grpc call":
+				calls = append(calls, r["grpc.side"].(string)+" "+r["grpc.code"].(string))
+			}
+		}
+		if got, want := strings.Join(messages, ","), "client sent,server received,server sent,client received"; !sameSet(got, want) {
+			t.Errorf("logged messages %v, want %v", got, want)
+		}
+		if got, want := strings.Join(calls, ","), "client Canceled,server Canceled"; !sameSet(got, want) {
+			t.Errorf("logged calls %v, want %v", got, want)
+		}
+	})
+}
+
+func equalJSON(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return bytes.Equal(x, y)
+}
+
+// sameSet reports whether the comma-separated lists a and b have the same
+// elements, as the server and client log concurrently.
+func sameSet(a, b string) bool {
+	x, y := strings.Split(a, ","), strings.Split(b, ",")
+	if len(x) != len(y) {
+		return false
+	}
+	count := map[string]int{}
+	for _, s := range x {
+		count[s]++
+	}
+	for _, s := range y {
+		count[s]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
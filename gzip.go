@@ -0,0 +1,81 @@
+package jsonify
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// Gzip is the [Codec] of the gzip format, at the default compression level,
+// with pooled writers.
+var Gzip Codec = gzipCodec{}
+
+var gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+type gzipCodec struct{}
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	zw := gzipWriterPool.Get().(*gzip.Writer)
+	zw.Reset(w)
+	return &pooledGzipWriter{zw}, nil
+}
+
+// pooledGzipWriter returns its writer to the pool once closed.
+type pooledGzipWriter struct {
+	*gzip.Writer
+}
+
+func (pw *pooledGzipWriter) Close() error {
+	if pw.Writer == nil {
+		return nil
+	}
+	err := pw.Writer.Close()
+	pw.Writer.Reset(io.Discard) // Not to keep w alive in the pool.
+	gzipWriterPool.Put(pw.Writer)
+	pw.Writer = nil
+	return err
+}
+
+// GzipEncode encodes v with opts, compressed with gzip, to w, in one pass
+// through [Encode], without holding the whole output, e.g. to persist a
+// value to a file or an object store:
+//
+//	err := jsonify.GzipEncode(file, snapshot)
+//
+// It is [Encode] with [WithCompression] of [Gzip], except that options that
+// reformat the output, e.g. [WithIndent], and the hook of
+// [SetPostMarshalHook] make it encode the value whole with [Bytes] before
+// compressing it, so that the output is that of Bytes. If encoding fails, w
+// has received part of the output.
+func GzipEncode(w io.Writer, v any, opts ...Option) error {
+	return encodeTo(w, v, append(opts[:len(opts):len(opts)], WithCompression(Gzip)))
+}
+
+// GzipBytes returns the encoding of v with opts compressed with gzip, as
+// written by [GzipEncode].
+func GzipBytes(v any, opts ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := GzipEncode(&buf, v, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeTo encodes v to w with [Encode], or with [Bytes] if opts reformat
+// the output or a post-marshal hook is set, which Encode does not apply,
+// compressed by [WithCompression] either way.
+func encodeTo(w io.Writer, v any, opts []Option) error {
+	o := newOptions(opts)
+	if !o.reformats() && postMarshalHook == nil {
+		return Encode(w, v, opts...)
+	}
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		return err
+	}
+	return o.compress(w, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
@@ -0,0 +1,105 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func gunzip(t *testing.T, b []byte) string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// gunzipString returns the decompressed b, for examples.
+func gunzipString(b []byte) string {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return err.Error()
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
+
+func ExampleGzipBytes() {
+	b, err := jsonify.GzipBytes(map[string]int{"a": 1})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(gunzipString(b))
+	// Output:
+	// {"a":1}
+}
+
+func TestGzipBytes(t *testing.T) {
+	big := make(map[string]int, 10_000)
+	for i := range 10_000 {
+		big[fmt.Sprint("key", i)] = i
+	}
+	tests := []struct {
+		name string
+		v    any
+		opts []jsonify.Option
+	}{
+		{name: "scalar", v: "x"},
+		{name: "large", v: big},
+		{name: "indent", v: []int{1, 2}, opts: []jsonify.Option{jsonify.WithIndent("", " ")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Twice, to reuse a pooled writer.
+			for range 2 {
+				b, err := jsonify.GzipBytes(tt.v, tt.opts...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := gunzip(t, b), jsonify.MustString(tt.v, tt.opts...); got != want {
+					t.Errorf("GzipBytes() = %d bytes, want %d", len(got), len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestGzipBytes_hook(t *testing.T) {
+	jsonify.SetPostMarshalHook(func(v any, out []byte) []byte {
+		return append(out[:len(out)-1:len(out)-1], `,"v":1}`...)
+	})
+	defer jsonify.SetPostMarshalHook(nil)
+	for _, opts := range [][]jsonify.Option{nil, {jsonify.WithIndent("", "  ")}} {
+		v := map[string]int{"a": 1}
+		b, err := jsonify.GzipBytes(v, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := gunzip(t, b), jsonify.MustString(v, opts...); got != want {
+			t.Errorf("GzipBytes() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestGzipEncode_errors(t *testing.T) {
+	if _, err := jsonify.GzipBytes(math.Inf(1)); err == nil {
+		t.Error("GzipBytes(Inf) error = nil, want error")
+	}
+	if err := jsonify.GzipEncode(errorWriter{}, "x"); !errors.Is(err, errWrite) {
+		t.Errorf("GzipEncode() error = %v, want %v", err, errWrite)
+	}
+}
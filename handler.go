@@ -0,0 +1,118 @@
+package jsonify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+// HTTPHandler returns an [http.Handler] serving v, encoded with [Bytes] and
+// opts, to GET and HEAD requests, e.g. for a health, debug or config
+// endpoint:
+//
+//	http.Handle("GET /debug/config", jsonify.HTTPHandler(cfg, jsonify.WithIndent("", "  ")))
+//
+// v is encoded for each request, so a pointer serves the current state of
+// what it points to. Other methods are answered with status 405. The
+// responses are written as with [HTTPHandlerFunc].
+//
+// It is not named Handler, which is the handler of a [Conn] method.
+func HTTPHandler(v any, opts ...Option) http.Handler {
+	return &handler{
+		fn:      func(*http.Request) (any, error) { return v, nil },
+		opts:    opts,
+		methods: "GET, HEAD",
+	}
+}
+
+// HTTPHandlerFunc returns an [http.Handler] serving the value returned by fn,
+// encoded with [Bytes] and opts, with status 200:
+//
+//	http.Handle("GET /healthz", jsonify.HTTPHandlerFunc(func(r *http.Request) (any, error) {
+//		return s.health(r.Context())
+//	}))
+//
+// An error returned by fn is answered with a structured error body, as
+// with [WriteJSON], e.g.
+//
+//	{"error":{"code":404,"message":"Not Found"}}
+//
+// A [*RequestError], e.g. from [ReadJSON], is answered with its status
+// and message, so fn can return one to choose them. Other errors are
+// answered without their messages, which may reveal details of the server:
+// errors matching [fs.ErrNotExist] with status 404, [fs.ErrPermission] with
+// 403, [errors.ErrUnsupported] with 501, [context.DeadlineExceeded] and
+// [context.Canceled] with 503, and any other error with 500. fn should log
+// the errors it returns if needed.
+//
+// A HEAD request is answered with the headers of the response to a GET
+// request, including its Content-Length, but without the body. fn is
+// called for requests of any method, and can check r.Method itself.
+func HTTPHandlerFunc(fn func(r *http.Request) (any, error), opts ...Option) http.Handler {
+	return &handler{fn: fn, opts: opts}
+}
+
+type handler struct {
+	fn   func(*http.Request) (any, error)
+	opts []Option
+
+	// methods is the value of the Allow header, if the handler serves only
+	// those methods.
+	methods string
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodHead {
+		w = bodylessResponseWriter{w}
+	}
+	if h.methods != "" && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", h.methods)
+		writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	v, err := h.fn(r)
+	if err != nil {
+		status, message := errorStatus(err)
+		writeError(w, status, message)
+		return
+	}
+	WriteJSON(w, http.StatusOK, v, h.opts...)
+}
+
+// errorStatus returns the status and message of the response to err, the
+// error of the function of an [HTTPHandlerFunc].
+func errorStatus(err error) (int, string) {
+	var re *RequestError
+	if errors.As(err, &re) {
+		return re.Status, re.Message
+	}
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		status = http.StatusForbidden
+	case errors.Is(err, errors.ErrUnsupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		status = http.StatusServiceUnavailable
+	}
+	return status, http.StatusText(status)
+}
+
+// writeError writes a structured error body with status and message.
+func writeError(w http.ResponseWriter, status int, message string) {
+	b := fmt.Appendf(nil, `{"error":{"code":%d,"message":`, status)
+	b = append(appendString(b, message), "}}"...)
+	writeJSONBody(w, status, b)
+}
+
+// bodylessResponseWriter discards the body of the response to a HEAD
+// request, keeping its headers.
+type bodylessResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w bodylessResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
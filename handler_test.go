@@ -0,0 +1,114 @@
+package jsonify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleHTTPHandler() {
+	config := map[string]any{"debug": true, "workers": 4}
+	h := jsonify.HTTPHandler(config)
+	for _, method := range []string{"GET", "HEAD", "POST"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/config", nil))
+		fmt.Printf("%s %d %s %q\n", method, rec.Code, rec.Header().Get("Content-Length"), rec.Body.String())
+	}
+	// Output:
+	// GET 200 26 "{\"debug\":true,\"workers\":4}"
+	// HEAD 200 26 ""
+	// POST 405 53 "{\"error\":{\"code\":405,\"message\":\"Method Not Allowed\"}}"
+}
+
+func ExampleHTTPHandlerFunc() {
+	users := map[string]string{"1": "Ann"}
+	h := jsonify.HTTPHandlerFunc(func(r *http.Request) (any, error) {
+		name, ok := users[r.URL.Query().Get("id")]
+		if !ok {
+			return nil, &jsonify.RequestError{Status: http.StatusNotFound, Message: "no such user"}
+		}
+		return map[string]string{"name": name}, nil
+	})
+	for _, target := range []string{"/users?id=1", "/users?id=2"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		fmt.Println(rec.Code, rec.Body.String())
+	}
+	// Output:
+	// 200 {"name":"Ann"}
+	// 404 {"error":{"code":404,"message":"no such user"}}
+}
+
+func TestHTTPHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		value      any
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "ok", method: "GET", value: []int{1, 2}, wantStatus: 200, wantBody: `[1,2]`},
+		{name: "post", method: "POST", value: "created", wantStatus: 200, wantBody: `"created"`},
+		{name: "head", method: "HEAD", value: []int{1, 2}, wantStatus: 200},
+		{name: "head error", method: "HEAD", err: fs.ErrNotExist, wantStatus: 404},
+		{name: "request error", method: "GET", err: fmt.Errorf("reading: %w", &jsonify.RequestError{Status: 413, Message: "too large"}), wantStatus: 413, wantBody: `{"error":{"code":413,"message":"too large"}}`},
+		{name: "not exist", method: "GET", err: fmt.Errorf("open x: %w", fs.ErrNotExist), wantStatus: 404, wantBody: `{"error":{"code":404,"message":"Not Found"}}`},
+		{name: "permission", method: "GET", err: fs.ErrPermission, wantStatus: 403, wantBody: `{"error":{"code":403,"message":"Forbidden"}}`},
+		{name: "unsupported", method: "GET", err: errors.ErrUnsupported, wantStatus: 501, wantBody: `{"error":{"code":501,"message":"Not Implemented"}}`},
+		{name: "deadline", method: "GET", err: context.DeadlineExceeded, wantStatus: 503, wantBody: `{"error":{"code":503,"message":"Service Unavailable"}}`},
+		{name: "other", method: "GET", err: errors.New("db password is hunter2"), wantStatus: 500, wantBody: `{"error":{"code":500,"message":"Internal Server Error"}}`},
+		{name: "encode error", method: "GET", value: make(chan int), wantStatus: 500, wantBody: `{"error":{"code":500,"message":"failed to encode the response"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := jsonify.HTTPHandlerFunc(func(r *http.Request) (any, error) {
+				return tt.value, tt.err
+			})
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", nil))
+			if rec.Code != tt.wantStatus || rec.Body.String() != tt.wantBody {
+				t.Errorf("ServeHTTP() wrote %d %s, want %d %s", rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	n := 1
+	h := jsonify.HTTPHandler(&n, jsonify.WithTrailingNewline())
+	n = 2
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if want := "2\n"; rec.Code != 200 || rec.Body.String() != want {
+		t.Errorf("ServeHTTP() wrote %d %q, want 200 %q", rec.Code, rec.Body.String(), want)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/", nil))
+	if rec.Code != 405 || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("ServeHTTP() of DELETE wrote %d with Allow %q, want 405 with GET, HEAD", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestHTTPHandler_server(t *testing.T) {
+	srv := httptest.NewServer(jsonify.HTTPHandler(map[string]bool{"ok": true}))
+	defer srv.Close()
+	resp, err := http.Head(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || resp.ContentLength != int64(len(`{"ok":true}`)) {
+		t.Errorf("HEAD = %d with length %d, want 200 with length 11", resp.StatusCode, resp.ContentLength)
+	}
+}
@@ -0,0 +1,97 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxHeaderSize is the largest header section [ReadHeaderFrame] accepts.
+const maxHeaderSize = 4096
+
+// HeaderFrame writes v encoded with [Bytes] to w with a Content-Length
+// header, as the Language Server Protocol and the Debug Adapter Protocol
+// frame their messages on stdio:
+//
+//	Content-Length: 17\r\n
+//	\r\n
+//	{"method":"exit"}
+func HeaderFrame(w io.Writer, v any) error {
+	payload, err := Bytes(v)
+	if err != nil {
+		return err
+	}
+	b := make([]byte, 0, 32+len(payload))
+	b = append(b, "Content-Length: "...)
+	b = strconv.AppendInt(b, int64(len(payload)), 10)
+	b = append(b, "\r\n\r\n"...)
+	b = append(b, payload...)
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadHeaderFrame reads a message written by [HeaderFrame] from r and
+// returns its payload.
+//
+// The header section must have a Content-Length header; other headers, such
+// as Content-Type, are ignored. Lines may end with "\n" as well as "\r\n".
+// ReadHeaderFrame reads r byte by byte up to the payload, so r need not be
+// buffered and nothing past the message is consumed.
+//
+// It returns [io.EOF] if r is at the end before the message starts,
+// [io.ErrUnexpectedEOF] if r ends within the message, and
+// [ErrFrameTooLarge] if the payload is larger than [MaxFrameSize].
+func ReadHeaderFrame(r io.Reader) (json.RawMessage, error) {
+	br := byteReader{r}
+	size := -1
+	var line []byte
+	for total := 0; ; {
+		c, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF && (total > 0 || size >= 0) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if total++; total > maxHeaderSize {
+			return nil, fmt.Errorf("jsonify: header section exceeds %d bytes", maxHeaderSize)
+		}
+		if c != '\n' {
+			line = append(line, c)
+			continue
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) == 0 {
+			break
+		}
+		name, value, ok := strings.Cut(string(line), ":")
+		if !ok {
+			return nil, fmt.Errorf("jsonify: invalid header line %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("jsonify: invalid Content-Length %q", value)
+			}
+			size = n
+		}
+		line = line[:0]
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("jsonify: missing Content-Length header")
+	}
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return payload, nil
+}
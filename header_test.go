@@ -0,0 +1,83 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleHeaderFrame() {
+	var stdout bytes.Buffer
+	jsonify.HeaderFrame(&stdout, map[string]any{"jsonrpc": "2.0", "method": "exit"})
+	fmt.Printf("%q\n", stdout.String())
+
+	payload, err := jsonify.ReadHeaderFrame(&stdout)
+	fmt.Printf("%s %v\n", payload, err)
+	// Output:
+	// "Content-Length: 33\r\n\r\n{\"jsonrpc\":\"2.0\",\"method\":\"exit\"}"
+	// {"jsonrpc":"2.0","method":"exit"} <nil>
+}
+
+func TestReadHeaderFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "crlf", input: "Content-Length: 2\r\n\r\n{}", expected: `{}`},
+		{name: "lf", input: "content-length:2\nContent-Type: application/vscode-jsonrpc; charset=utf-8\n\n[]", expected: `[]`},
+		{name: "empty", input: "", err: io.EOF},
+		{name: "truncated header", input: "Content-Length: 2\r\n", err: io.ErrUnexpectedEOF},
+		{name: "truncated payload", input: "Content-Length: 5\r\n\r\n{}", err: io.ErrUnexpectedEOF},
+		{name: "too large", input: "Content-Length: 99999999999\r\n\r\n", err: jsonify.ErrFrameTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.ReadHeaderFrame(iotest.HalfReader(strings.NewReader(tt.input)))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("ReadHeaderFrame() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.expected {
+				t.Errorf("ReadHeaderFrame() = %s, %v, want %s", got, err, tt.expected)
+			}
+		})
+	}
+
+	invalid := []string{
+		"\r\n{}",
+		"Content-Length: x\r\n\r\n",
+		"Content-Length 2\r\n\r\n{}",
+		strings.Repeat("X-Padding: 0\r\n", 1000),
+	}
+	for _, input := range invalid {
+		if _, err := jsonify.ReadHeaderFrame(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadHeaderFrame(%.20q) error = nil, want error", input)
+		}
+	}
+}
+
+func TestReadHeaderFrameSequence(t *testing.T) {
+	var buf bytes.Buffer
+	for i := range 3 {
+		jsonify.HeaderFrame(&buf, i)
+	}
+	for i := range 3 {
+		got, err := jsonify.ReadHeaderFrame(&buf)
+		if err != nil || string(got) != fmt.Sprint(i) {
+			t.Errorf("ReadHeaderFrame() = %s, %v, want %d", got, err, i)
+		}
+	}
+	if _, err := jsonify.ReadHeaderFrame(&buf); err != io.EOF {
+		t.Errorf("ReadHeaderFrame() at end error = %v, want EOF", err)
+	}
+}
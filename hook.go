@@ -0,0 +1,68 @@
+package jsonify
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// RegisterFieldHook registers fn to transform the value of the named field
+// of struct type S while encoding, without modifying S, e.g. to lowercase
+// emails or truncate long strings in types you don't own:
+//
+//	jsonify.RegisterFieldHook[User]("Email", func(s string) any {
+//		return strings.ToLower(s)
+//	})
+//
+// field is the Go field name, not the JSON name. The value returned by fn is
+// encoded in place of the field value. Fields with omitempty are omitted
+// based on the original value.
+//
+// RegisterFieldHook panics if S is not a struct type or has no field named
+// field of type F.
+//
+// Registering again for the same field replaces the previous hook.
+// Registrations apply to the [Jsoniter] backend. They are meant to be made
+// during program initialization.
+func RegisterFieldHook[S, F any](field string, fn func(v F) any) {
+	typ := reflect.TypeOf((*S)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonify: RegisterFieldHook of non-struct type %v", typ))
+	}
+	f, ok := typ.FieldByName(field)
+	if !ok || len(f.Index) != 1 {
+		panic(fmt.Sprintf("jsonify: %v has no field %s", typ, field))
+	}
+	if want := reflect.TypeOf((*F)(nil)).Elem(); f.Type != want {
+		panic(fmt.Sprintf("jsonify: field %v.%s has type %v, not %v", typ, field, f.Type, want))
+	}
+	hook := func(v any) any {
+		f, _ := v.(F) // v is nil for a nil interface field.
+		return fn(f)
+	}
+	update(func(ext *extension) {
+		hooks := make(map[string]func(any) any, len(ext.fieldHooks[typ])+1)
+		for name, h := range ext.fieldHooks[typ] {
+			hooks[name] = h
+		}
+		hooks[field] = hook
+		ext.fieldHooks[typ] = hooks
+	})
+}
+
+type fieldHookEncoder struct {
+	typ  reflect2.Type
+	fn   func(any) any
+	next jsoniter.ValEncoder
+}
+
+func (e *fieldHookEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+func (e *fieldHookEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteVal(e.fn(e.typ.UnsafeIndirect(ptr)))
+}
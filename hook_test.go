@@ -0,0 +1,107 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type account struct {
+	Email string   `json:"email"`
+	Bio   string   `json:"bio,omitempty"`
+	Tags  []string `json:"tags"`
+	Note  any      `json:"note"`
+}
+
+func init() {
+	jsonify.RegisterFieldHook[account]("Email", func(s string) any {
+		return strings.ToLower(s)
+	})
+	jsonify.RegisterFieldHook[account]("Bio", func(s string) any {
+		if len(s) > 5 {
+			return s[:5] + "..."
+		}
+		return s
+	})
+	jsonify.RegisterFieldHook[account]("Tags", func(tags []string) any {
+		return len(tags)
+	})
+	jsonify.RegisterFieldHook[account]("Note", func(v any) any {
+		if v == nil {
+			return "none"
+		}
+		return v
+	})
+}
+
+func ExampleRegisterFieldHook() {
+	type Contact struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	jsonify.RegisterFieldHook[Contact]("Email", func(s string) any {
+		return strings.ToLower(s)
+	})
+
+	fmt.Println(jsonify.MustString(Contact{Name: "Ann", Email: "Ann@Example.COM"}))
+	// Output:
+	// {"name":"Ann","email":"ann@example.com"}
+}
+
+func TestRegisterFieldHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{
+			name:     "all hooks",
+			input:    account{Email: "A@B.C", Bio: "long biography", Tags: []string{"x", "y"}},
+			expected: `{"email":"a@b.c","bio":"long ...","tags":2,"note":"none"}`,
+		},
+		{
+			name:     "omitempty uses the original value",
+			input:    &account{Email: "x", Note: 1},
+			expected: `{"email":"x","tags":0,"note":1}`,
+		},
+		{
+			name:     "nested",
+			input:    map[string][]account{"a": {{Email: "Q"}}},
+			expected: `{"a":[{"email":"q","tags":0,"note":"none"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterFieldHookPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func()
+	}{
+		{"not a struct", func() { jsonify.RegisterFieldHook[int]("X", func(int) any { return nil }) }},
+		{"no field", func() { jsonify.RegisterFieldHook[account]("Missing", func(string) any { return nil }) }},
+		{"wrong type", func() { jsonify.RegisterFieldHook[account]("Email", func(int) any { return nil }) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("RegisterFieldHook() did not panic")
+				}
+			}()
+			tt.register()
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// encodeErrorBody is the body of the response of [WriteJSON] when the value
+// cannot be encoded. It does not include the error, which may reveal
+// details of the server.
+const encodeErrorBody = `{"error":{"code":500,"message":"failed to encode the response"}}`
+
+// WriteJSON writes v, encoded with [Bytes] and opts, as the body of an HTTP
+// response with status, and with the Content-Type application/json:
+//
+//	func (s *server) getUser(w http.ResponseWriter, r *http.Request) {
+//		user, err := s.users.Get(r.Context(), r.PathValue("id"))
+//		...
+//		if err := jsonify.WriteJSON(w, http.StatusOK, user); err != nil {
+//			s.logger.Error("writing response", "error", err)
+//		}
+//	}
+//
+// As v is encoded before anything is written, a v that cannot be encoded
+// is answered with status 500 and a structured error body,
+//
+//	{"error":{"code":500,"message":"failed to encode the response"}}
+//
+// and the error is returned for the caller to log. Statuses without a
+// body, 204 and 304, are written without one. An error writing the body is
+// returned too.
+func WriteJSON(w http.ResponseWriter, status int, v any, opts ...Option) error {
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return nil
+	}
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		writeJSONBody(w, http.StatusInternalServerError, []byte(encodeErrorBody))
+		return err
+	}
+	return writeJSONBody(w, status, b)
+}
+
+func writeJSONBody(w http.ResponseWriter, status int, b []byte) error {
+	return writeBody(w, status, "application/json", b)
+}
+
+func writeBody(w http.ResponseWriter, status int, contentType string, b []byte) error {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Length", strconv.Itoa(len(b)))
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, err := w.Write(b)
+	return err
+}
+
+// DefaultMaxBodyBytes is the size of the largest request body [ReadJSON]
+// accepts unless [WithMaxBodyBytes] is given.
+const DefaultMaxBodyBytes = 1 << 20
+
+// WithMaxBodyBytes returns a [DecodeOption] that makes [ReadJSON] accept
+// request bodies of up to n bytes, rather than [DefaultMaxBodyBytes].
+func WithMaxBodyBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) {
+		o.maxBodyBytes = n
+	}
+}
+
+// RequestError is the error of [ReadJSON] for a request that cannot be
+// read, with a message suitable for returning to the client.
+type RequestError struct {
+	Status  int    // e.g. 400, 413 or 415
+	Message string // e.g. `request body has unknown field "nmae"`
+	Err     error  // the underlying error, for logs
+}
+
+func (e *RequestError) Error() string { return e.Message }
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// ReadJSON decodes the JSON body of r into v with [Decode] and opts, for
+// HTTP handlers:
+//
+//	var req createUserRequest
+//	if err := jsonify.ReadJSON(r, &req); err != nil {
+//		var re *jsonify.RequestError
+//		errors.As(err, &re)
+//		jsonify.WriteJSON(w, re.Status, map[string]string{"error": re.Message})
+//		return
+//	}
+//
+// The request must have the Content-Type application/json, or another
+// type ending with +json, and a body of at most [DefaultMaxBodyBytes] or
+// the size given with [WithMaxBodyBytes]. Unknown fields are rejected, as
+// with [WithDisallowUnknownFields], to catch mistakes of clients early.
+//
+// The errors are [*RequestError] values, whose messages describe what is
+// wrong without revealing Go types, e.g. `request body field "age" is a
+// string, want an integer`.
+func ReadJSON(r *http.Request, v any, opts ...DecodeOption) error {
+	o := decodeOptions{maxBodyBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return &RequestError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type must be application/json", Err: err}
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, o.maxBodyBytes+1))
+	if err != nil {
+		return &RequestError{Status: http.StatusBadRequest, Message: "failed to read request body", Err: err}
+	}
+	if int64(len(data)) > o.maxBodyBytes {
+		return &RequestError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("request body is larger than %d bytes", o.maxBodyBytes),
+		}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &RequestError{Status: http.StatusBadRequest, Message: "request body is empty"}
+	}
+	opts = append([]DecodeOption{WithDisallowUnknownFields()}, opts...)
+	if err := Decode(data, v, opts...); err != nil {
+		return &RequestError{Status: http.StatusBadRequest, Message: requestErrorMessage(data, v, err), Err: err}
+	}
+	return nil
+}
+
+// unknownFieldPattern matches the unknown field errors of jsoniter and of
+// protojson.
+var unknownFieldPattern = regexp.MustCompile(`found unknown field: ([^,]*),|unknown field "([^"]*)"`)
+
+// requestErrorMessage describes err, the error of decoding data into v, for
+// a client.
+func requestErrorMessage(data []byte, v any, err error) string {
+	var syntaxErr *json.SyntaxError
+	if jerr := json.Unmarshal(data, new(any)); errors.As(jerr, &syntaxErr) {
+		return fmt.Sprintf("request body is not valid JSON: %s at offset %d", syntaxErr, syntaxErr.Offset)
+	}
+	if m := unknownFieldPattern.FindStringSubmatch(err.Error()); m != nil {
+		return fmt.Sprintf("request body has unknown field %q", m[1]+m[2])
+	}
+	if isProtoValue(v) {
+		// protojson errors name fields, not Go types.
+		return "request body is invalid: " + strings.TrimPrefix(err.Error(), "proto: ")
+	}
+	// encoding/json reports the path of a mismatched value.
+	var typeErr *json.UnmarshalTypeError
+	if t := reflect.TypeOf(v); t != nil && t.Kind() == reflect.Pointer {
+		if jerr := json.Unmarshal(data, reflect.New(t.Elem()).Interface()); errors.As(jerr, &typeErr) {
+			if typeErr.Field == "" {
+				return fmt.Sprintf("request body is %s, want %s", jsonArticle(typeErr.Value), jsonKindOf(typeErr.Type))
+			}
+			return fmt.Sprintf("request body field %q is %s, want %s", typeErr.Field, jsonArticle(typeErr.Value), jsonKindOf(typeErr.Type))
+		}
+	}
+	return "request body is invalid"
+}
+
+// jsonKindOf returns the JSON type of the values t decodes, with an article.
+func jsonKindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Pointer:
+		return jsonKindOf(t.Elem())
+	}
+	return "another value"
+}
+
+// jsonArticle returns the kind of value of a [json.UnmarshalTypeError],
+// e.g. "string" or "number 1.5", with an article.
+func jsonArticle(value string) string {
+	if strings.HasPrefix(value, "array") || strings.HasPrefix(value, "object") {
+		return "an " + value
+	}
+	return "a " + value
+}
@@ -0,0 +1,148 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWriteJSON() {
+	rec := httptest.NewRecorder()
+	jsonify.WriteJSON(rec, http.StatusCreated, map[string]int{"id": 1})
+	fmt.Println(rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	// Output:
+	// 201 application/json {"id":1}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		v          any
+		opts       []jsonify.Option
+		wantStatus int
+		wantBody   string
+		wantErr    bool
+	}{
+		{name: "ok", status: http.StatusOK, v: []int{1}, wantStatus: 200, wantBody: "[1]"},
+		{name: "options", status: http.StatusOK, v: 1, opts: []jsonify.Option{jsonify.WithTrailingNewline()}, wantStatus: 200, wantBody: "1\n"},
+		{name: "error status", status: http.StatusNotFound, v: map[string]string{"error": "not found"}, wantStatus: 404, wantBody: `{"error":"not found"}`},
+		{name: "no content", status: http.StatusNoContent, v: 1, wantStatus: 204, wantBody: ""},
+		{
+			name:       "encode error",
+			status:     http.StatusOK,
+			v:          math.NaN(),
+			wantStatus: 500,
+			wantBody:   `{"error":{"code":500,"message":"failed to encode the response"}}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := jsonify.WriteJSON(rec, tt.status, tt.v, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if rec.Code != tt.wantStatus || rec.Body.String() != tt.wantBody {
+				t.Errorf("WriteJSON() wrote %d %q, want %d %q", rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			h := rec.Header()
+			if h.Get("Content-Type") != "application/json" || h.Get("Content-Length") != fmt.Sprint(len(tt.wantBody)) {
+				t.Errorf("WriteJSON() headers = %v", h)
+			}
+		})
+	}
+}
+
+func ExampleReadJSON() {
+	type createUser struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req createUser
+		if err := jsonify.ReadJSON(r, &req); err != nil {
+			var re *jsonify.RequestError
+			errors.As(err, &re)
+			jsonify.WriteJSON(w, re.Status, map[string]string{"error": re.Message})
+			return
+		}
+		jsonify.WriteJSON(w, http.StatusCreated, req)
+	}
+	for _, body := range []string{`{"name":"Ann","age":30}`, `{"name":"Ann","age":"30"}`, `{"nmae":"Ann"}`} {
+		r := httptest.NewRequest("POST", "/users", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		fmt.Println(rec.Code, rec.Body.String())
+	}
+	// Output:
+	// 201 {"name":"Ann","age":30}
+	// 400 {"error":"request body field \"age\" is a string, want an integer"}
+	// 400 {"error":"request body has unknown field \"nmae\""}
+}
+
+func TestReadJSON(t *testing.T) {
+	type item struct {
+		ID    int      `json:"id"`
+		Tags  []string `json:"tags"`
+		Inner struct {
+			OK bool `json:"ok"`
+		} `json:"inner"`
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []jsonify.DecodeOption
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "ok", contentType: "application/json", body: `{"id":1}`},
+		{name: "charset", contentType: "application/json; charset=utf-8", body: `{"id":1}`},
+		{name: "suffix", contentType: "application/merge-patch+json", body: `{"id":1}`},
+		{name: "no content type", body: `{}`, wantStatus: 415, wantMessage: "Content-Type must be application/json"},
+		{name: "text", contentType: "text/plain", body: `{}`, wantStatus: 415, wantMessage: "Content-Type must be application/json"},
+		{name: "empty", contentType: "application/json", body: " \n", wantStatus: 400, wantMessage: "request body is empty"},
+		{name: "too large", contentType: "application/json", body: `{"id":12345}`, opts: []jsonify.DecodeOption{jsonify.WithMaxBodyBytes(10)}, wantStatus: 413, wantMessage: "request body is larger than 10 bytes"},
+		{name: "limit", contentType: "application/json", body: `{"id":1234}`, opts: []jsonify.DecodeOption{jsonify.WithMaxBodyBytes(11)}},
+		{name: "syntax", contentType: "application/json", body: `{"id":1,}`, wantStatus: 400, wantMessage: "request body is not valid JSON: invalid character '}' looking for beginning of object key string at offset 9"},
+		{name: "unknown field", contentType: "application/json", body: `{"inner":{"ko":true}}`, wantStatus: 400, wantMessage: `request body has unknown field "ko"`},
+		{name: "nested type", contentType: "application/json", body: `{"inner":{"ok":"yes"}}`, wantStatus: 400, wantMessage: `request body field "inner.ok" is a string, want a boolean`},
+		{name: "array type", contentType: "application/json", body: `{"tags":"a"}`, wantStatus: 400, wantMessage: `request body field "tags" is a string, want an array`},
+		{name: "top-level type", contentType: "application/json", body: `[1]`, wantStatus: 400, wantMessage: `request body is an array, want an object`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			var v item
+			err := jsonify.ReadJSON(r, &v, tt.opts...)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("ReadJSON() error = %v", err)
+				}
+				return
+			}
+			var re *jsonify.RequestError
+			if !errors.As(err, &re) {
+				t.Fatalf("ReadJSON() error = %v, want a *RequestError", err)
+			}
+			if re.Status != tt.wantStatus || re.Message != tt.wantMessage {
+				t.Errorf("ReadJSON() error = %d %q, want %d %q", re.Status, re.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package jsonify
+
+import (
+	"reflect"
+	"slices"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// WithTranslator returns an [Option] that encodes the string fields tagged
+// with the i18n flag of the jsonify tag as translate of their value, e.g. to
+// turn message keys into text of the caller's locale:
+//
+//	type Problem struct {
+//		Code    string `json:"code"`
+//		Message string `json:"message" jsonify:"i18n"`
+//	}
+//
+// Flags come before the keys of the tag, as in `jsonify:"i18n,aud=admin"`.
+// The translator applies to structs at any depth, except inside values that
+// marshal themselves. Without WithTranslator, the flag is ignored.
+func WithTranslator(translate func(key string) string) Option {
+	return func(o *options) {
+		o.translate = translate
+		o.mode.i18n = translate != nil
+	}
+}
+
+// translateFields wraps the encoders of the string fields of desc that are
+// tagged with the i18n flag.
+func translateFields(desc *jsoniter.StructDescriptor) {
+	for _, binding := range desc.Fields {
+		if binding.Field.Type().Kind() != reflect.String {
+			continue
+		}
+		if slices.Contains(jsonifyTag(binding.Field.Tag())[""], "i18n") {
+			binding.Encoder = &translateEncoder{binding.Encoder}
+		}
+	}
+}
+
+type translateEncoder struct {
+	next jsoniter.ValEncoder
+}
+
+func (e *translateEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+func (e *translateEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	o, _ := stream.Attachment.(*options)
+	if o == nil || o.translate == nil {
+		e.next.Encode(ptr, stream)
+		return
+	}
+	stream.WriteString(o.translate(*(*string)(ptr)))
+}
@@ -0,0 +1,60 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithTranslator() {
+	type Problem struct {
+		Code    string `json:"code"`
+		Message string `json:"message" jsonify:"i18n"`
+	}
+	de := map[string]string{"err.not_found": "Nicht gefunden"}
+	translate := func(key string) string {
+		if s, ok := de[key]; ok {
+			return s
+		}
+		return key
+	}
+	p := Problem{Code: "404", Message: "err.not_found"}
+	fmt.Println(jsonify.MustString(p))
+	fmt.Println(jsonify.MustString(p, jsonify.WithTranslator(translate)))
+	// Output:
+	// {"code":"404","message":"err.not_found"}
+	// {"code":"404","message":"Nicht gefunden"}
+}
+
+func TestWithTranslator(t *testing.T) {
+	type label struct {
+		Text  string `json:"text" jsonify:"i18n"`
+		Hint  string `json:"hint,omitempty" jsonify:"i18n,aud=admin"`
+		Count int    `json:"count" jsonify:"i18n"`
+	}
+	upper := jsonify.WithTranslator(strings.ToUpper)
+	tests := []struct {
+		name     string
+		input    any
+		opts     []jsonify.Option
+		expected string
+	}{
+		{name: "struct", input: label{Text: "a", Hint: "b", Count: 1}, opts: []jsonify.Option{upper}, expected: `{"text":"A","hint":"B","count":1}`},
+		{name: "omitempty", input: label{Text: "a"}, opts: []jsonify.Option{upper}, expected: `{"text":"A","count":0}`},
+		{name: "nested", input: map[string][]label{"l": {{Text: "x"}}}, opts: []jsonify.Option{upper}, expected: `{"l":[{"text":"X","count":0}]}`},
+		{name: "per call", input: label{Text: "a"}, opts: []jsonify.Option{jsonify.WithTranslator(func(string) string { return "z" })}, expected: `{"text":"z","count":0}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, tt.opts...)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package jsonify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// IdempotencyKey derives an idempotency key for a request from its method,
+// path and body, so that a retried request gets the same key, and a
+// different request a different one:
+//
+//	req.Header.Set("Idempotency-Key", jsonify.IdempotencyKey("POST", "/v1/payments", payment))
+//
+// The key is the hex SHA-256 of the method, the path and the canonical
+// encoding of body, in which the members of every object are sorted by key.
+// It does not depend on the order of struct fields or on the options of the
+// call that encodes the body. The method is case-insensitive, while the path
+// is used as is.
+//
+// If body cannot be encoded, IdempotencyKey behaves like [MustBytes]: it
+// panics, or returns "" if a handler is set with [SetPanicHandler].
+func IdempotencyKey(method, path string, body any) string {
+	b, err := canonicalJSON(body)
+	if err != nil {
+		mustFail(body, err)
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(strings.ToUpper(method)))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON encodes v with the members of every object sorted by key.
+// Numbers keep the text they are encoded with.
+func canonicalJSON(v any) ([]byte, error) {
+	b, err := marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := Decode(b, &doc, UseNumber()); err != nil {
+		return nil, err
+	}
+	return marshal(doc)
+}
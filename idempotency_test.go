@@ -0,0 +1,64 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleIdempotencyKey() {
+	type payment struct {
+		Amount   int    `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	key := jsonify.IdempotencyKey("POST", "/v1/payments", payment{Amount: 100, Currency: "EUR"})
+	fmt.Println(key == jsonify.IdempotencyKey("post", "/v1/payments", map[string]any{"currency": "EUR", "amount": 100}))
+	fmt.Println(len(key))
+	// Output:
+	// true
+	// 64
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	type payment struct {
+		Amount   int    `json:"amount"`
+		Currency string `json:"currency"`
+		Note     string `json:"note,omitempty"`
+	}
+	type reordered struct {
+		Currency string `json:"currency"`
+		Amount   int    `json:"amount"`
+	}
+	key := jsonify.IdempotencyKey("POST", "/v1/payments", payment{Amount: 100, Currency: "EUR"})
+
+	same := map[string]string{
+		"reordered fields": jsonify.IdempotencyKey("POST", "/v1/payments", reordered{Amount: 100, Currency: "EUR"}),
+		"raw message":      jsonify.IdempotencyKey("POST", "/v1/payments", json.RawMessage(`{ "currency": "EUR", "amount": 100 }`)),
+		"lower case":       jsonify.IdempotencyKey("post", "/v1/payments", payment{Amount: 100, Currency: "EUR"}),
+	}
+	for name, got := range same {
+		if got != key {
+			t.Errorf("IdempotencyKey() with %s = %v, want %v", name, got, key)
+		}
+	}
+	different := map[string]string{
+		"method": jsonify.IdempotencyKey("PUT", "/v1/payments", payment{Amount: 100, Currency: "EUR"}),
+		"path":   jsonify.IdempotencyKey("POST", "/v1/refunds", payment{Amount: 100, Currency: "EUR"}),
+		"body":   jsonify.IdempotencyKey("POST", "/v1/payments", payment{Amount: 101, Currency: "EUR"}),
+		"split":  jsonify.IdempotencyKey("POST", "/v1/payments\x00", nil),
+	}
+	for name, got := range different {
+		if got == key {
+			t.Errorf("IdempotencyKey() with another %s = %v, want a different key", name, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("IdempotencyKey() of an unsupported body did not panic")
+		}
+	}()
+	jsonify.IdempotencyKey("POST", "/", make(chan int))
+}
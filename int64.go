@@ -0,0 +1,85 @@
+package jsonify
+
+import (
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// Int64Policy is how 64-bit integers are encoded. JavaScript numbers lose
+// precision beyond 2^53, so browser consumers may need them as strings.
+type Int64Policy int
+
+const (
+	// Int64AsNumber encodes 64-bit integers as numbers. It is the default.
+	Int64AsNumber Int64Policy = iota
+
+	// Int64AsStringIfUnsafe encodes 64-bit integers as strings if they are
+	// unsafe for JavaScript, outside the range ±(2^53-1) that a float64
+	// represents exactly, and as numbers otherwise.
+	Int64AsStringIfUnsafe
+
+	// Int64AsString encodes all 64-bit integers as strings, as protojson does
+	// for int64 and uint64 fields of protobuf messages.
+	Int64AsString
+)
+
+// maxSafeInteger is the largest integer that a float64 represents exactly,
+// along with all smaller integers.
+const maxSafeInteger = 1<<53 - 1
+
+// WithInt64 returns an [Option] that encodes 64-bit integers according to
+// policy.
+//
+// It applies to values of the kinds int64 and uint64, and int, uint and
+// uintptr on 64-bit platforms, except types implementing [json.Marshaler] or
+// [encoding.TextMarshaler] and types with a registered encoder. Protobuf
+// messages are encoded by protojson, which always encodes int64 fields as
+// strings.
+func WithInt64(policy Int64Policy) Option {
+	return func(o *options) {
+		o.mode.int64 = policy
+	}
+}
+
+func int64EncoderOf(typ reflect2.Type, policy Int64Policy) jsoniter.ValEncoder {
+	if policy == Int64AsNumber || typ.Type1().Size() != 8 || hasMarshaler(typ) {
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int64:
+		return &int64Encoder{policy: policy}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &int64Encoder{policy: policy, unsigned: true}
+	}
+	return nil
+}
+
+type int64Encoder struct {
+	policy   Int64Policy
+	unsigned bool
+}
+
+func (e *int64Encoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*uint64)(ptr) == 0
+}
+
+func (e *int64Encoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	var s string
+	var safe bool
+	if e.unsigned {
+		u := *(*uint64)(ptr)
+		s, safe = strconv.FormatUint(u, 10), u <= maxSafeInteger
+	} else {
+		i := *(*int64)(ptr)
+		s, safe = strconv.FormatInt(i, 10), -maxSafeInteger <= i && i <= maxSafeInteger
+	}
+	if e.policy == Int64AsStringIfUnsafe && safe {
+		stream.WriteRaw(s)
+		return
+	}
+	stream.WriteRaw(`"` + s + `"`)
+}
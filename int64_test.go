@@ -0,0 +1,58 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithInt64() {
+	ids := []int64{42, 1 << 60}
+	fmt.Println(jsonify.MustString(ids, jsonify.WithInt64(jsonify.Int64AsStringIfUnsafe)))
+	fmt.Println(jsonify.MustString(ids, jsonify.WithInt64(jsonify.Int64AsString)))
+	// Output:
+	// [42,"1152921504606846976"]
+	// ["42","1152921504606846976"]
+}
+
+func TestWithInt64(t *testing.T) {
+	type row struct {
+		ID    uint64 `json:"id"`
+		Count int32  `json:"count"`
+		Skip  int64  `json:"skip,omitempty"`
+	}
+	tests := []struct {
+		name     string
+		input    any
+		policy   jsonify.Int64Policy
+		expected string
+	}{
+		{name: "number", input: int64(1 << 60), policy: jsonify.Int64AsNumber, expected: `1152921504606846976`},
+		{name: "safe bound", input: []int64{1<<53 - 1, -(1<<53 - 1)}, policy: jsonify.Int64AsStringIfUnsafe, expected: `[9007199254740991,-9007199254740991]`},
+		{name: "unsafe bound", input: []int64{1 << 53, math.MinInt64}, policy: jsonify.Int64AsStringIfUnsafe, expected: `["9007199254740992","-9223372036854775808"]`},
+		{name: "uint64", input: uint64(math.MaxUint64), policy: jsonify.Int64AsStringIfUnsafe, expected: `"18446744073709551615"`},
+		{name: "struct", input: row{ID: 7, Count: 3}, policy: jsonify.Int64AsString, expected: `{"id":"7","count":3}`},
+		{name: "any", input: map[string]any{"n": int64(5)}, policy: jsonify.Int64AsString, expected: `{"n":"5"}`},
+		{name: "Optional", input: jsonify.Some(int64(5)), policy: jsonify.Int64AsString, expected: `"5"`},
+		{name: "Optional field", input: struct {
+			N jsonify.Optional[uint64] `json:"n"`
+		}{N: jsonify.Some[uint64](1 << 60)}, policy: jsonify.Int64AsStringIfUnsafe, expected: `{"n":"1152921504606846976"}`},
+		{name: "Set", input: jsonify.NewSet[int64](1, 1<<60), policy: jsonify.Int64AsStringIfUnsafe, expected: `[1,"1152921504606846976"]`},
+		{name: "Sparse", input: jsonify.Sparse[int64]{0, 5}, policy: jsonify.Int64AsString, expected: `{"len":2,"entries":{"1":"5"}}`},
+		{name: "Range", input: jsonify.Range[int64]{Start: 1, End: 2, StartInclusive: true}, policy: jsonify.Int64AsString, expected: `{"start":"1","end":"2","bounds":"[)"}`},
+		{name: "Table", input: jsonify.Table([]row{{ID: 7, Count: 3}}), policy: jsonify.Int64AsString, expected: `{"columns":["id","count"],"rows":[["7",3]]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, jsonify.WithInt64(tt.policy))
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -13,44 +13,77 @@
 //
 // This configuration is similar to [jsoniter.ConfigCompatibleWithStandardLibrary].
 // The only difference is that EscapeHTML is set to false.
+//
+// The encoder can be replaced with [SetBackend], e.g. with [Stdlib].
+//
+// A [proto.Message] is encoded and decoded with [protojson], both at the top
+// level and nested in other values, such as the elements of a []*pb.User,
+// []proto.Message or map[string]*pb.User, or a struct field, unless a backend
+// other than [Jsoniter] is set. A [protoreflect.Message] is handled as the
+// message it reflects, so that reflection-driven code can pass it directly.
+// Protobuf enums outside of messages are encoded as numbers, like other Go
+// integers, or by name, as inside messages, with [WithProtoEnumNames].
+//
+// Support for [proto.Message] can be excluded with the jsonify_noproto build
+// tag, so that programs which never encode protobuf messages don't link
+// [protojson] and the protobuf runtime. With the tag, protobuf messages are
+// encoded by the backend like any other value.
 package jsonify
 
-import (
-	"encoding/json"
-
-	jsoniter "github.com/json-iterator/go"
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/proto"
-)
-
-var config = jsoniter.Config{
-	SortMapKeys:            true,
-	ValidateJsonRawMessage: true,
-}.Froze()
-
 // Bytes encodes the given value as JSON and returns it as a byte slice.
 //
 // It handles [json.RawMessage], [proto.Message], and other types differently.
 // For [json.RawMessage], it returns the raw bytes.
-// For [proto.Message], it uses [protojson] for marshaling.
-// For other types, it uses a custom [jsoniter] configuration.
-func Bytes(v any) ([]byte, error) {
-	switch v := v.(type) {
-	case json.RawMessage:
-		return []byte(v), nil
-	case proto.Message:
-		return protojson.Marshal(v)
+// For [proto.Message], it uses [protojson] for marshaling, normalizing the
+// whitespace that protojson varies so that the output is reproducible.
+// For other types, it uses the current [Backend], which defaults to a custom
+// [jsoniter] configuration.
+//
+// The encoding can be configured with opts, e.g. [Lenient] or
+// [WithSmartIndent].
+// The hook set by [SetPostMarshalHook], if any, is applied to the result.
+func Bytes(v any, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	b, err := o.marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if o.fingerprint != "" {
+		if b, err = addFingerprint(b, o.fingerprint, v); err != nil {
+			return nil, err
+		}
+	}
+	if o.format != nil {
+		if b, err = o.format(b); err != nil {
+			return nil, err
+		}
 	}
-	return config.Marshal(v)
+	if o.trailingNewline {
+		// b may be the caller's, e.g. a json.RawMessage.
+		b = append(b[:len(b):len(b)], '\n')
+	}
+	if err := o.checkOutput(b); err != nil {
+		return nil, err
+	}
+	return postMarshal(v, b), nil
+}
+
+// marshal is [Bytes] without formatting and the post-marshal hook. It is
+// used for values that are encoded as a part of another value.
+func marshal(v any, opts ...Option) ([]byte, error) {
+	return newOptions(opts).marshal(v)
 }
 
 // MustBytes is similar to [Bytes] but panics if an error occurs during encoding.
 //
 // It's useful when you're certain that the encoding will succeed.
-func MustBytes(v any) []byte {
-	b, err := Bytes(v)
+// If a handler is set with [SetPanicHandler], the handler is called instead
+// of panicking, and MustBytes returns nil.
+func MustBytes(v any, opts ...Option) []byte {
+	b, err := Bytes(v, opts...)
 	if err != nil {
-		panic(err)
+		mustFail(v, err)
+		return nil
 	}
 	return b
 }
@@ -60,25 +93,50 @@ func MustBytes(v any) []byte {
 // It handles [json.RawMessage], [proto.Message], and other types differently.
 // For [json.RawMessage], it returns the raw message as a string.
 // For [proto.Message], it uses [protojson] for marshaling.
-// For other types, it uses a custom [jsoniter] configuration.
-func String(v any) (string, error) {
-	switch v := v.(type) {
-	case json.RawMessage:
-		return string(v), nil
-	case proto.Message:
-		b, err := protojson.Marshal(v)
-		return string(b), err
-	}
-	return config.MarshalToString(v)
+// For other types, it uses the current [Backend], which defaults to a custom
+// [jsoniter] configuration.
+//
+// The encoding can be configured with opts, e.g. [Lenient] or
+// [WithSmartIndent].
+// The hook set by [SetPostMarshalHook], if any, is applied to the result.
+func String(v any, opts ...Option) (string, error) {
+	b, err := Bytes(v, opts...)
+	return string(b), err
 }
 
 // MustString is similar to [String] but panics if an error occurs during encoding.
 //
 // It's useful when you're certain that the encoding will succeed.
-func MustString(v any) string {
-	s, err := String(v)
+// If a handler is set with [SetPanicHandler], the handler is called instead
+// of panicking, and MustString returns "".
+func MustString(v any, opts ...Option) string {
+	s, err := String(v, opts...)
 	if err != nil {
-		panic(err)
+		mustFail(v, err)
+		return ""
 	}
 	return s
 }
+
+var panicHandler func(v any, err error)
+
+// SetPanicHandler sets a handler that is called by [MustBytes] and
+// [MustString] instead of panicking when encoding fails, e.g. to report the
+// error without crashing a logging path. A nil handler restores the default
+// behavior of panicking.
+//
+// The handler receives the value that failed to encode and the error. The
+// Must functions return an empty result after the handler returns.
+//
+// SetPanicHandler is meant to be called during program initialization; it
+// must not be called concurrently with encoding.
+func SetPanicHandler(handler func(v any, err error)) {
+	panicHandler = handler
+}
+
+func mustFail(v any, err error) {
+	if panicHandler == nil {
+		panic(err)
+	}
+	panicHandler(v, err)
+}
@@ -9,7 +9,6 @@ import (
 	"unicode"
 
 	"github.com/goaux/jsonify"
-	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func ExampleBytes() {
@@ -206,33 +205,26 @@ func TestMustString(t *testing.T) {
 	})
 }
 
-func TestProtobufMessage(t *testing.T) {
-	pbMsg, err := structpb.NewStruct(map[string]any{
-		"foo": "bar",
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	t.Run("Bytes with protobuf", func(t *testing.T) {
-		got, err := jsonify.Bytes(pbMsg)
-		if err != nil {
-			t.Fatalf("Bytes() error = %v", err)
-		}
-		expected := []byte(`{"foo":"bar"}`)
-		if !reflect.DeepEqual(got, expected) {
-			t.Errorf("Bytes() = %s, want %s", got, expected)
+func TestSetPanicHandler(t *testing.T) {
+	var reported []error
+	jsonify.SetPanicHandler(func(v any, err error) {
+		if _, ok := v.(chan int); !ok {
+			t.Errorf("handler got %T, want chan int", v)
 		}
+		reported = append(reported, err)
 	})
+	defer jsonify.SetPanicHandler(nil)
 
-	t.Run("String with protobuf", func(t *testing.T) {
-		got, err := jsonify.String(pbMsg)
-		if err != nil {
-			t.Fatalf("String() error = %v", err)
-		}
-		expected := `{"foo":"bar"}`
-		if got != expected {
-			t.Errorf("String() = %v, want %v", got, expected)
-		}
-	})
+	if got := jsonify.MustBytes(make(chan int)); got != nil {
+		t.Errorf("MustBytes() = %s, want nil", got)
+	}
+	if got := jsonify.MustString(make(chan int)); got != "" {
+		t.Errorf("MustString() = %v, want empty", got)
+	}
+	if got := jsonify.MustString(1); got != "1" {
+		t.Errorf("MustString() = %v, want 1", got)
+	}
+	if len(reported) != 2 || reported[0] == nil || reported[1] == nil {
+		t.Errorf("handler got %v, want 2 errors", reported)
+	}
 }
@@ -0,0 +1,334 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goaux/jsonify"
+)
+
+// NewServerCodec returns a [rpc.ServerCodec] for JSON-RPC 2.0 on conn.
+//
+// The method of a request is the "Service.Method" name of net/rpc. The
+// params are decoded into the argument of the method, or the only element
+// of the params is if they are an array of one, as sent by clients of
+// [net/rpc/jsonrpc]. Notifications are served but not answered, and the
+// responses to a batch are written together once all of them are done.
+//
+// Errors returned by methods are answered with [CodeServerError], or with
+// [CodeMethodNotFound] and [CodeInvalidParams] where net/rpc reports those.
+func NewServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
+	return &serverCodec{
+		conn:    conn,
+		dec:     json.NewDecoder(conn),
+		pending: map[uint64]*serverCall{},
+	}
+}
+
+// ServeConn serves a single connection with [rpc.DefaultServer] using
+// [NewServerCodec], blocking until the client hangs up.
+func ServeConn(conn io.ReadWriteCloser) {
+	rpc.ServeCodec(NewServerCodec(conn))
+}
+
+// NewClientCodec returns a [rpc.ClientCodec] for JSON-RPC 2.0 on conn.
+//
+// The argument of a call is sent as the params if it encodes as an object or
+// an array, and wrapped in an array otherwise. An error response is reported
+// by net/rpc as an [rpc.ServerError] holding the error message.
+func NewClientCodec(conn io.ReadWriteCloser) rpc.ClientCodec {
+	return &clientCodec{
+		conn:    conn,
+		dec:     json.NewDecoder(conn),
+		pending: map[uint64]string{},
+	}
+}
+
+// NewClient returns a new [rpc.Client] using [NewClientCodec].
+func NewClient(conn io.ReadWriteCloser) *rpc.Client {
+	return rpc.NewClientWithCodec(NewClientCodec(conn))
+}
+
+// Dial connects to a JSON-RPC 2.0 server at the specified network address.
+func Dial(network, address string) (*rpc.Client, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+type serverCodec struct {
+	conn io.ReadWriteCloser
+	dec  *json.Decoder
+
+	// queue holds the requests of a batch not yet read by net/rpc.
+	queue []queuedRequest
+
+	// params is the params of the request being read by net/rpc.
+	params json.RawMessage
+
+	mu      sync.Mutex // guards seq, pending and batch
+	seq     uint64
+	pending map[uint64]*serverCall
+
+	wmu sync.Mutex // serializes writes to conn
+}
+
+type queuedRequest struct {
+	req   Request
+	batch *batch
+}
+
+// serverCall is a request that net/rpc is serving.
+type serverCall struct {
+	id            json.RawMessage
+	batch         *batch
+	invalidParams bool
+}
+
+// batch collects the responses to a batch of requests.
+type batch struct {
+	remaining int
+	responses []Response
+}
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	for len(c.queue) == 0 {
+		var raw json.RawMessage
+		if err := c.dec.Decode(&raw); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				c.write(Response{Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			}
+			return err
+		}
+		if err := c.enqueue(raw); err != nil {
+			return err
+		}
+	}
+	q := c.queue[0]
+	c.queue = c.queue[1:]
+	c.mu.Lock()
+	c.seq++
+	c.pending[c.seq] = &serverCall{id: q.req.ID, batch: q.batch}
+	r.Seq = c.seq
+	c.mu.Unlock()
+	r.ServiceMethod = q.req.Method
+	c.params = q.req.Params
+	return nil
+}
+
+// enqueue queues the requests in raw, answering invalid ones right away.
+func (c *serverCodec) enqueue(raw json.RawMessage) error {
+	elems, isBatch, err := splitBatch(raw)
+	if err != nil {
+		return c.write(Response{Error: err.(*Error)})
+	}
+	if !isBatch {
+		var req Request
+		if err := req.UnmarshalJSON(elems[0]); err != nil {
+			return c.write(Response{Error: asError(err)})
+		}
+		c.queue = append(c.queue, queuedRequest{req: req})
+		return nil
+	}
+	b := &batch{}
+	c.mu.Lock()
+	for _, elem := range elems {
+		var req Request
+		if err := req.UnmarshalJSON(elem); err != nil {
+			b.responses = append(b.responses, Response{Error: asError(err)})
+			continue
+		}
+		c.queue = append(c.queue, queuedRequest{req: req, batch: b})
+		b.remaining++
+	}
+	done := b.remaining == 0
+	c.mu.Unlock()
+	if done {
+		return c.write(b.responses)
+	}
+	return nil
+}
+
+func (c *serverCodec) ReadRequestBody(x any) error {
+	params := c.params
+	c.params = nil
+	if x == nil || len(params) == 0 {
+		return nil
+	}
+	err := jsonify.Decode(params, x)
+	if err != nil && params[0] == '[' {
+		var elems []json.RawMessage
+		if jsonify.Decode(params, &elems) == nil && len(elems) == 1 {
+			err = jsonify.Decode(elems[0], x)
+		}
+	}
+	if err != nil {
+		c.mu.Lock()
+		c.pending[c.seq].invalidParams = true
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *serverCodec) WriteResponse(r *rpc.Response, body any) error {
+	c.mu.Lock()
+	call, ok := c.pending[r.Seq]
+	delete(c.pending, r.Seq)
+	c.mu.Unlock()
+	if !ok {
+		return errors.New("jsonrpc: invalid sequence number in response")
+	}
+	resp := Response{ID: call.id}
+	switch {
+	case r.Error == "":
+		result, err := jsonify.Bytes(body)
+		if err != nil {
+			resp.Error = &Error{Code: CodeInternalError, Message: err.Error()}
+		} else {
+			resp.Result = result
+		}
+	case call.invalidParams:
+		resp.Error = &Error{Code: CodeInvalidParams, Message: r.Error}
+	case strings.HasPrefix(r.Error, "rpc: can't find"):
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: r.Error}
+	default:
+		resp.Error = &Error{Code: CodeServerError, Message: r.Error}
+	}
+	notification := len(call.id) == 0
+	if call.batch == nil {
+		if notification {
+			return nil
+		}
+		return c.write(resp)
+	}
+	c.mu.Lock()
+	b := call.batch
+	if !notification {
+		b.responses = append(b.responses, resp)
+	}
+	b.remaining--
+	done := b.remaining == 0 && len(b.responses) > 0
+	c.mu.Unlock()
+	if done {
+		return c.write(b.responses)
+	}
+	return nil
+}
+
+func (c *serverCodec) write(v any) error {
+	b, err := jsonify.Bytes(v)
+	if err != nil {
+		return err
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err = c.conn.Write(append(b, '\n'))
+	return err
+}
+
+func (c *serverCodec) Close() error {
+	return c.conn.Close()
+}
+
+// asError returns err as an [*Error], wrapping it in an internal error if
+// it is not one.
+func asError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+type clientCodec struct {
+	conn io.ReadWriteCloser
+	dec  *json.Decoder
+
+	// result is the result of the response being read by net/rpc.
+	result json.RawMessage
+
+	mu      sync.Mutex // guards pending
+	pending map[uint64]string
+}
+
+func (c *clientCodec) WriteRequest(r *rpc.Request, param any) error {
+	var params json.RawMessage
+	if param != nil {
+		b, err := jsonify.Bytes(param)
+		if err != nil {
+			return err
+		}
+		if b[0] != '{' && b[0] != '[' {
+			b = append(append([]byte{'['}, b...), ']')
+		}
+		params = b
+	}
+	b, err := jsonify.Bytes(Request{
+		ID:     strconv.AppendUint(nil, r.Seq, 10),
+		Method: r.ServiceMethod,
+		Params: params,
+	})
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.pending[r.Seq] = r.ServiceMethod
+	c.mu.Unlock()
+	_, err = c.conn.Write(append(b, '\n'))
+	return err
+}
+
+func (c *clientCodec) ReadResponseHeader(r *rpc.Response) error {
+	var raw json.RawMessage
+	if err := c.dec.Decode(&raw); err != nil {
+		return err
+	}
+	var resp Response
+	if err := resp.UnmarshalJSON(raw); err != nil {
+		return err
+	}
+	seq, err := strconv.ParseUint(string(resp.ID), 10, 64)
+	if err != nil {
+		if resp.Error != nil {
+			return resp.Error
+		}
+		return fmt.Errorf("jsonrpc: invalid response id %s", resp.ID)
+	}
+	c.mu.Lock()
+	r.ServiceMethod = c.pending[seq]
+	delete(c.pending, seq)
+	c.mu.Unlock()
+	r.Seq = seq
+	r.Error = ""
+	c.result = resp.Result
+	if resp.Error != nil {
+		r.Error = resp.Error.Message
+		if r.Error == "" {
+			r.Error = "unspecified error"
+		}
+	}
+	return nil
+}
+
+func (c *clientCodec) ReadResponseBody(x any) error {
+	result := c.result
+	c.result = nil
+	if x == nil {
+		return nil
+	}
+	return jsonify.Decode(result, x)
+}
+
+func (c *clientCodec) Close() error {
+	return c.conn.Close()
+}
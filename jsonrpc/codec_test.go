@@ -0,0 +1,129 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify/jsonrpc"
+)
+
+type Greeter struct{}
+
+type GreetArgs struct {
+	Name string `json:"name"`
+}
+
+func (Greeter) Greet(args GreetArgs, reply *string) error {
+	if args.Name == "" {
+		return errors.New("name is required")
+	}
+	*reply = "Hello, " + args.Name
+	return nil
+}
+
+func newServer(t testing.TB) net.Conn {
+	server := rpc.NewServer()
+	if err := server.Register(Greeter{}); err != nil {
+		t.Fatal(err)
+	}
+	client, conn := net.Pipe()
+	go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return client
+}
+
+func ExampleNewClient() {
+	server := rpc.NewServer()
+	server.Register(Greeter{})
+	client, conn := net.Pipe()
+	go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+	c := jsonrpc.NewClient(client)
+	defer c.Close()
+	var reply string
+	err := c.Call("Greeter.Greet", GreetArgs{Name: "<Ann>"}, &reply)
+	fmt.Println(reply, err)
+	// Output:
+	// Hello, <Ann> <nil>
+}
+
+func TestClient(t *testing.T) {
+	c := jsonrpc.NewClient(newServer(t))
+	defer c.Close()
+	var reply string
+	if err := c.Call("Greeter.Greet", GreetArgs{Name: "Bob"}, &reply); err != nil || reply != "Hello, Bob" {
+		t.Errorf("Call() = %q, %v", reply, err)
+	}
+	err := c.Call("Greeter.Greet", GreetArgs{}, &reply)
+	if _, ok := err.(rpc.ServerError); !ok || err.Error() != "name is required" {
+		t.Errorf("Call() error = %v, want a server error", err)
+	}
+}
+
+func TestServerCodec(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "request",
+			input:    `{"jsonrpc":"2.0","id":1,"method":"Greeter.Greet","params":{"name":"<b>"}}`,
+			expected: `{"jsonrpc":"2.0","id":1,"result":"Hello, <b>"}`,
+		},
+		{
+			name:     "by-position params",
+			input:    `{"jsonrpc":"2.0","id":"a","method":"Greeter.Greet","params":[{"name":"Ann"}]}`,
+			expected: `{"jsonrpc":"2.0","id":"a","result":"Hello, Ann"}`,
+		},
+		{
+			name:     "method error",
+			input:    `{"jsonrpc":"2.0","id":1,"method":"Greeter.Greet","params":{}}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"name is required"}}`,
+		},
+		{
+			name:     "method not found",
+			input:    `{"jsonrpc":"2.0","id":1,"method":"Greeter.Wave"}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"rpc: can't find method Greeter.Wave"}}`,
+		},
+		{
+			name:     "invalid params",
+			input:    `{"jsonrpc":"2.0","id":1,"method":"Greeter.Greet","params":{"name":1}}`,
+			expected: `"code":-32602`,
+		},
+		{
+			name:     "invalid request",
+			input:    `{"jsonrpc":"1.0","id":1,"method":"Greeter.Greet"}`,
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"jsonrpc version \"1.0\", want \"2.0\""}}`,
+		},
+		{
+			name: "batch",
+			input: `[{"jsonrpc":"2.0","id":1,"method":"Greeter.Greet","params":{"name":"A"}},` +
+				`{"jsonrpc":"2.0","method":"Greeter.Greet","params":{"name":"B"}},1]`,
+			expected: `{"jsonrpc":"2.0","id":1,"result":"Hello, A"}`,
+		},
+		{
+			name:     "notification",
+			input:    `{"jsonrpc":"2.0","method":"Greeter.Greet","params":{"name":"A"}}` + "\n" + `{"jsonrpc":"2.0","id":2,"method":"Greeter.Greet","params":{"name":"B"}}`,
+			expected: `{"jsonrpc":"2.0","id":2,"result":"Hello, B"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newServer(t)
+			defer conn.Close()
+			go conn.Write([]byte(tt.input + "\n"))
+			got, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				t.Fatalf("ReadString() error = %v", err)
+			}
+			if !strings.Contains(got, tt.expected) {
+				t.Errorf("response = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
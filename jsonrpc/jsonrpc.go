@@ -0,0 +1,210 @@
+// Package jsonrpc implements the messages of JSON-RPC 2.0, encoded and
+// decoded with [jsonify], and a codec to use them with [net/rpc].
+//
+// Unlike [net/rpc/jsonrpc], which implements JSON-RPC 1.0, it supports
+// notifications, batches and error objects, and does not escape HTML
+// characters in strings.
+//
+// The messages are encoded as JSON-RPC 2.0 requires regardless of their
+// fields, e.g. with "jsonrpc":"2.0" and with exactly one of "result" and
+// "error" in a response:
+//
+//	b, err := jsonify.Bytes(jsonrpc.Request{ID: json.RawMessage("1"), Method: "ping"})
+//	// {"jsonrpc":"2.0","id":1,"method":"ping"}
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goaux/jsonify"
+)
+
+// Version is the value of the "jsonrpc" member of every message.
+const Version = "2.0"
+
+// Error codes defined by JSON-RPC 2.0.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeServerError is the first of the codes from -32000 to -32099
+	// reserved for implementation-defined server errors.
+	CodeServerError = -32000
+)
+
+// Request is a JSON-RPC 2.0 request, or a notification if ID is empty.
+type Request struct {
+	// ID is the raw JSON string or number identifying the request.
+	ID json.RawMessage
+
+	Method string
+
+	// Params is the raw JSON array or object of the parameters, if any.
+	Params json.RawMessage
+}
+
+// IsNotification reports whether r is a notification, which is not
+// answered by a response.
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a JSON-RPC 2.0 response. Result is ignored if Error is set,
+// and an empty Result is encoded as null.
+type Response struct {
+	// ID is the raw JSON ID of the request, or null if it could not be
+	// determined, e.g. for a parse error.
+	ID json.RawMessage
+
+	Result json.RawMessage
+	Error  *Error
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: %s (code %d)", e.Message, e.Code)
+}
+
+type requestJSON struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type responseJSON struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (r Request) MarshalJSON() ([]byte, error) {
+	return jsonify.Bytes(requestJSON{Version: Version, ID: r.ID, Method: r.Method, Params: r.Params})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It returns an [*Error] with
+// [CodeInvalidRequest] if data is not a valid request.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var v requestJSON
+	if err := jsonify.Decode(data, &v); err != nil {
+		return invalidRequest(err.Error())
+	}
+	if v.Version != Version {
+		return invalidRequest(fmt.Sprintf("jsonrpc version %q, want %q", v.Version, Version))
+	}
+	if v.Method == "" {
+		return invalidRequest("missing method")
+	}
+	if !validID(v.ID) {
+		return invalidRequest(fmt.Sprintf("invalid id %s", v.ID))
+	}
+	if len(v.Params) > 0 && v.Params[0] != '[' && v.Params[0] != '{' {
+		return invalidRequest("params must be an array or an object")
+	}
+	*r = Request{ID: v.ID, Method: v.Method, Params: v.Params}
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (r Response) MarshalJSON() ([]byte, error) {
+	v := responseJSON{Version: Version, ID: r.ID, Result: r.Result, Error: r.Error}
+	if len(v.ID) == 0 {
+		v.ID = json.RawMessage("null")
+	}
+	if v.Error != nil {
+		v.Result = nil
+	} else if len(v.Result) == 0 {
+		v.Result = json.RawMessage("null")
+	}
+	return jsonify.Bytes(v)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var v responseJSON
+	if err := jsonify.Decode(data, &v); err != nil {
+		return err
+	}
+	if v.Version != Version {
+		return fmt.Errorf("jsonrpc: version %q, want %q", v.Version, Version)
+	}
+	if (len(v.Result) == 0) == (v.Error == nil) {
+		return fmt.Errorf("jsonrpc: response must have exactly one of result and error")
+	}
+	*r = Response{ID: v.ID, Result: v.Result, Error: v.Error}
+	return nil
+}
+
+// DecodeRequests decodes a request or a batch of requests, reporting whether
+// data is a batch. It fails if any request of the batch is invalid.
+func DecodeRequests(data []byte) ([]Request, bool, error) {
+	return decodeBatch(data, (*Request).UnmarshalJSON)
+}
+
+// DecodeResponses decodes a response or a batch of responses, reporting
+// whether data is a batch.
+func DecodeResponses(data []byte) ([]Response, bool, error) {
+	return decodeBatch(data, (*Response).UnmarshalJSON)
+}
+
+// decodeBatch decodes data with unmarshal, which is called directly rather
+// than through [jsonify.Decode] so that its error is returned unwrapped.
+func decodeBatch[T any](data []byte, unmarshal func(*T, []byte) error) ([]T, bool, error) {
+	elems, batch, err := splitBatch(data)
+	if err != nil {
+		return nil, false, err
+	}
+	values := make([]T, len(elems))
+	for i, elem := range elems {
+		if err := unmarshal(&values[i], elem); err != nil {
+			return nil, false, err
+		}
+	}
+	return values, batch, nil
+}
+
+// splitBatch splits a batch into its elements, or returns data as the only
+// element if it is not a batch.
+func splitBatch(data []byte) ([]json.RawMessage, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		return []json.RawMessage{data}, false, nil
+	}
+	var elems []json.RawMessage
+	if err := jsonify.Decode(data, &elems); err != nil {
+		return nil, true, &Error{Code: CodeParseError, Message: err.Error()}
+	}
+	if len(elems) == 0 {
+		return nil, true, invalidRequest("empty batch")
+	}
+	return elems, true, nil
+}
+
+// validID reports whether id is absent, a string, a number or null.
+func validID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+	switch c := id[0]; {
+	case c == '"', c == '-', '0' <= c && c <= '9':
+		return true
+	}
+	return string(id) == "null"
+}
+
+func invalidRequest(message string) *Error {
+	return &Error{Code: CodeInvalidRequest, Message: message}
+}
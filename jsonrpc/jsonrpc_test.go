@@ -0,0 +1,95 @@
+package jsonrpc_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"github.com/goaux/jsonify/jsonrpc"
+)
+
+func Example() {
+	req := jsonrpc.Request{ID: json.RawMessage("1"), Method: "greet", Params: json.RawMessage(`["<b>"]`)}
+	fmt.Println(jsonify.MustString(req))
+	fmt.Println(jsonify.MustString(jsonrpc.Response{ID: req.ID, Result: json.RawMessage(`"hi <b>"`)}))
+	// Output:
+	// {"jsonrpc":"2.0","id":1,"method":"greet","params":["<b>"]}
+	// {"jsonrpc":"2.0","id":1,"result":"hi <b>"}
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{
+			name:     "notification",
+			input:    jsonrpc.Request{Method: "log"},
+			expected: `{"jsonrpc":"2.0","method":"log"}`,
+		},
+		{
+			name:     "null result",
+			input:    jsonrpc.Response{ID: json.RawMessage(`"a"`)},
+			expected: `{"jsonrpc":"2.0","id":"a","result":null}`,
+		},
+		{
+			name:     "error",
+			input:    jsonrpc.Response{Result: json.RawMessage("1"), Error: &jsonrpc.Error{Code: jsonrpc.CodeParseError, Message: "bad"}},
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"bad"}}`,
+		},
+		{
+			name:     "batch",
+			input:    []jsonrpc.Request{{ID: json.RawMessage("1"), Method: "a"}, {Method: "b"}},
+			expected: `[{"jsonrpc":"2.0","id":1,"method":"a"},{"jsonrpc":"2.0","method":"b"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeRequests(t *testing.T) {
+	reqs, batch, err := jsonrpc.DecodeRequests([]byte(` [{"jsonrpc":"2.0","id":"x","method":"a","params":{"n":1}},{"jsonrpc":"2.0","method":"b"}]`))
+	if err != nil || !batch || len(reqs) != 2 {
+		t.Fatalf("DecodeRequests() = %v, %v, %v", reqs, batch, err)
+	}
+	if string(reqs[0].ID) != `"x"` || string(reqs[0].Params) != `{"n":1}` || reqs[0].IsNotification() || !reqs[1].IsNotification() {
+		t.Errorf("DecodeRequests() = %+v", reqs)
+	}
+
+	invalid := []string{
+		`{"id":1,"method":"a"}`,
+		`{"jsonrpc":"2.0","id":1}`,
+		`{"jsonrpc":"2.0","id":{},"method":"a"}`,
+		`{"jsonrpc":"2.0","id":1,"method":"a","params":3}`,
+		`[]`,
+	}
+	for _, data := range invalid {
+		_, _, err := jsonrpc.DecodeRequests([]byte(data))
+		var e *jsonrpc.Error
+		if !errors.As(err, &e) || e.Code != jsonrpc.CodeInvalidRequest {
+			t.Errorf("DecodeRequests(%s) error = %v, want an invalid request", data, err)
+		}
+	}
+}
+
+func TestDecodeResponses(t *testing.T) {
+	resps, batch, err := jsonrpc.DecodeResponses([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"no"}}`))
+	if err != nil || batch || len(resps) != 1 || resps[0].Error.Code != jsonrpc.CodeMethodNotFound {
+		t.Fatalf("DecodeResponses() = %v, %v, %v", resps, batch, err)
+	}
+	if _, _, err := jsonrpc.DecodeResponses([]byte(`{"jsonrpc":"2.0","id":1}`)); err == nil {
+		t.Errorf("DecodeResponses() without result error = nil, want error")
+	}
+}
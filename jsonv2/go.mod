@@ -0,0 +1,17 @@
+module github.com/goaux/jsonify/jsonv2
+
+go 1.24
+
+require (
+	github.com/go-json-experiment/json v0.0.0-20250725192818-e39067aee2d2
+	github.com/goaux/jsonify v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+replace github.com/goaux/jsonify => ../
@@ -0,0 +1,44 @@
+// Package jsonv2 provides a [jsonify.Backend] built on the experimental
+// encoding/json/v2 implementation at [github.com/go-json-experiment/json].
+//
+// It is opt-in and meant for evaluating the semantics and performance of
+// encoding/json/v2 behind jsonify's API:
+//
+//	jsonify.SetBackend(jsonv2.Backend)
+//
+// Note that encoding/json/v2 differs from encoding/json in several ways,
+// e.g. nil slices and maps encode as [] and {} rather than null.
+// Use [New] with [github.com/go-json-experiment/json/v1.DefaultOptionsV1]
+// to get the v1 behavior.
+//
+// Map keys are always sorted, as required by [jsonify.Backend].
+//
+// The package is a module of its own, github.com/goaux/jsonify/jsonv2, so
+// that the experimental dependency is only required by programs using it.
+package jsonv2
+
+import (
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/goaux/jsonify"
+)
+
+// Backend is a [jsonify.Backend] using the default encoding/json/v2 options.
+var Backend jsonify.Backend = New()
+
+// New returns a [jsonify.Backend] marshaling with the given options.
+//
+// [json.Deterministic] is always enabled and HTML escaping is always
+// disabled, regardless of opts.
+func New(opts ...json.Options) jsonify.Backend {
+	opts = append(opts, json.Deterministic(true), jsontext.EscapeForHTML(false))
+	return backend{opts: json.JoinOptions(opts...)}
+}
+
+type backend struct {
+	opts json.Options
+}
+
+func (b backend) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v, b.opts)
+}
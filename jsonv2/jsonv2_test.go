@@ -0,0 +1,59 @@
+package jsonv2_test
+
+import (
+	"fmt"
+	"testing"
+
+	jsonv1 "github.com/go-json-experiment/json/v1"
+	"github.com/goaux/jsonify"
+	"github.com/goaux/jsonify/jsonv2"
+)
+
+func Example() {
+	jsonify.SetBackend(jsonv2.Backend)
+	defer jsonify.SetBackend(nil)
+
+	fmt.Println(jsonify.MustString(map[string]any{"B": "<b>", "A": []int(nil)}))
+	// Output:
+	// {"A":[],"B":"<b>"}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  jsonify.Backend
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "sorted keys",
+			backend:  jsonv2.Backend,
+			input:    map[string]int{"c": 3, "a": 1, "b": 2},
+			expected: `{"a":1,"b":2,"c":3}`,
+		},
+		{
+			name:     "v1 options",
+			backend:  jsonv2.New(jsonv1.DefaultOptionsV1()),
+			input:    map[string]any{"s": []int(nil), "h": "<&>"},
+			expected: `{"h":"<&>","s":null}`,
+		},
+		{
+			name:    "channel",
+			backend: jsonv2.Backend,
+			input:   make(chan int),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.backend.Marshal(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.expected {
+				t.Errorf("Marshal() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package jsonify
+
+import (
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// Lenient returns an [Option] that encodes values of kinds JSON cannot
+// represent, such as channels, funcs and complex numbers, as placeholder
+// strings instead of failing:
+//
+//	{"done":"chan struct {} (unsupported)"}
+//
+// It is meant for debug dumps of arbitrary values, where partial output is
+// better than an error.
+func Lenient() Option {
+	return func(o *options) {
+		o.mode.lenient = true
+	}
+}
+
+func lenientEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return &placeholderEncoder{typ.Type1(), typ.String() + " (unsupported)"}
+	}
+	return nil
+}
+
+type placeholderEncoder struct {
+	typ  reflect.Type
+	text string
+}
+
+func (e *placeholderEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return reflect.NewAt(e.typ, ptr).Elem().IsZero()
+}
+
+func (e *placeholderEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString(e.text)
+}
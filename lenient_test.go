@@ -0,0 +1,51 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleLenient() {
+	v := struct {
+		Name string        `json:"name"`
+		Done chan struct{} `json:"done"`
+	}{Name: "worker", Done: make(chan struct{})}
+
+	fmt.Println(jsonify.MustString(v, jsonify.Lenient()))
+	// Output:
+	// {"name":"worker","done":"chan struct {} (unsupported)"}
+}
+
+func TestLenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "chan", input: make(chan int), expected: `"chan int (unsupported)"`},
+		{name: "func", input: map[string]any{"f": func() {}}, expected: `{"f":"func() (unsupported)"}`},
+		{name: "complex", input: []complex128{1i}, expected: `["complex128 (unsupported)"]`},
+		{name: "omitempty", input: struct {
+			F func() `json:"f,omitempty"`
+			N int    `json:"n"`
+		}{}, expected: `{"n":0}`},
+		{name: "supported", input: []int{1}, expected: `[1]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, jsonify.Lenient())
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := jsonify.String(make(chan int)); err == nil {
+		t.Errorf("String() without Lenient() error = nil, want error")
+	}
+}
@@ -0,0 +1,101 @@
+package jsonify
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// ErrOutputTooLarge is returned when the output exceeds the limit set by
+// [WithMaxOutputBytes].
+var ErrOutputTooLarge = errors.New("jsonify: output too large")
+
+// WithMaxOutputBytes returns an [Option] that fails the encoding with
+// [ErrOutputTooLarge] once the output exceeds n bytes, e.g. to keep logging
+// a value that unexpectedly holds a huge blob from exhausting memory.
+//
+// The [Jsoniter] backend checks the limit as each value is encoded, and
+// rejects a string or byte slice longer than the rest of the limit before
+// encoding it, so it stops soon after the limit is reached. Other backends
+// and protobuf messages are checked once encoded. The limit applies to the
+// formatted output. A limit of zero or less means no limit.
+func WithMaxOutputBytes(n int) Option {
+	return func(o *options) {
+		o.maxOutput = n
+		o.mode.limitOutput = n > 0
+	}
+}
+
+// checkOutput reports whether b is within the limit of o.
+func (o *options) checkOutput(b []byte) error {
+	if o.maxOutput > 0 && len(b) > o.maxOutput {
+		return o.outputTooLarge()
+	}
+	return nil
+}
+
+func (o *options) outputTooLarge() error {
+	return fmt.Errorf("%w: more than %d bytes", ErrOutputTooLarge, o.maxOutput)
+}
+
+// limitEncoder fails the encoding once the output exceeds the limit of the
+// call.
+type limitEncoder struct {
+	kind  reflect.Kind
+	bytes bool // whether the type is a byte slice
+	next  jsoniter.ValEncoder
+
+	// sortsMap is whether next sorts the entries of a map in a separate
+	// stream, as jsoniter does.
+	sortsMap bool
+}
+
+func (e *limitEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	o, _ := stream.Attachment.(*options)
+	if o == nil || o.maxOutput <= 0 {
+		e.next.Encode(ptr, stream)
+		return
+	}
+	if o.err != nil {
+		// Encoders of maps and slices carry on after an element fails.
+		return
+	}
+	size := o.outputBase + stream.Buffered()
+	switch {
+	case e.kind == reflect.String:
+		size += len(*(*string)(ptr))
+	case e.bytes:
+		size += len(*(*[]byte)(ptr))
+	}
+	if size > o.maxOutput {
+		o.fail(stream, o.outputTooLarge())
+		return
+	}
+	if e.sortsMap {
+		// Map keys are sorted in a separate stream, which is appended to
+		// this one once all of its entries are encoded.
+		base := o.outputBase
+		o.outputBase += stream.Buffered()
+		e.next.Encode(ptr, stream)
+		o.outputBase = base
+	} else {
+		e.next.Encode(ptr, stream)
+	}
+	if o.outputBase+stream.Buffered() > o.maxOutput {
+		o.fail(stream, o.outputTooLarge())
+	}
+}
+
+func (e *limitEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+func decorateLimit(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder {
+	bytes := typ.Kind() == reflect.Slice && typ.Type1().Elem().Kind() == reflect.Uint8
+	_, streamed := enc.(*streamMapEncoder)
+	return &limitEncoder{kind: typ.Kind(), bytes: bytes, next: enc, sortsMap: typ.Kind() == reflect.Map && !streamed}
+}
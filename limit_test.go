@@ -0,0 +1,58 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithMaxOutputBytes() {
+	type entry struct {
+		Msg  string
+		Blob []byte
+	}
+	_, err := jsonify.Bytes(entry{Msg: "upload", Blob: make([]byte, 200<<20)}, jsonify.WithMaxOutputBytes(1<<20))
+	fmt.Println(err)
+	// Output:
+	// jsonify: output too large: more than 1048576 bytes
+}
+
+func TestWithMaxOutputBytes(t *testing.T) {
+	big := strings.Repeat("x", 100)
+	many := map[string]string{}
+	for i := range 100 {
+		many[fmt.Sprint(i)] = big
+	}
+	tests := []struct {
+		name    string
+		input   any
+		opts    []jsonify.Option
+		wantErr bool
+	}{
+		{name: "within", input: []string{"a", "b"}, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(9)}},
+		{name: "exceeds", input: []string{"a", "b"}, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(8)}, wantErr: true},
+		{name: "long string", input: struct{ S string }{big}, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(50)}, wantErr: true},
+		{name: "map", input: many, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(5000)}, wantErr: true},
+		{name: "numbers", input: []int{1, 2, 3, 4, 5}, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(10)}, wantErr: true},
+		{name: "indented", input: map[string][]int{"a": {1, 2}}, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(11), jsonify.WithSmartIndent(0)}, wantErr: true},
+		{name: "raw", input: []byte(`"abc"`), opts: []jsonify.Option{jsonify.WithMaxOutputBytes(4)}, wantErr: true},
+		{name: "no limit", input: many, opts: []jsonify.Option{jsonify.WithMaxOutputBytes(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.Bytes(tt.input, tt.opts...)
+			if tt.wantErr {
+				if !errors.Is(err, jsonify.ErrOutputTooLarge) {
+					t.Errorf("Bytes() = %s, %v, want ErrOutputTooLarge", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Bytes() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package jsonify
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// WriteLine writes v encoded with [Bytes] to w as a single line, for
+// line-delimited protocols such as those of tool and plugin subprocesses
+// talking over stdin and stdout. Strings never contain raw newlines once
+// encoded, so the line ends only at the trailing newline.
+//
+// Processes speaking such a protocol on stdout should write diagnostics to
+// stderr; [LineReader] skips stray non-JSON lines but cannot recover a line
+// that mixes both.
+func WriteLine(w io.Writer, v any) error {
+	b, err := Bytes(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// LineReader reads JSON objects written one per line by [WriteLine].
+//
+// Lines that are not a JSON object, such as log output a subprocess printed
+// to stdout by mistake, are skipped rather than failing the read.
+type LineReader struct {
+	// OnSkip, if set, is called with each skipped line, e.g. to forward it
+	// to a log. The line is only valid during the call.
+	OnSkip func(line []byte)
+
+	scanner *bufio.Scanner
+}
+
+// NewLineReader returns a [LineReader] reading from r. Lines longer than
+// [MaxFrameSize] fail with [ErrFrameTooLarge].
+func NewLineReader(r io.Reader) *LineReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, MaxFrameSize)
+	return &LineReader{scanner: scanner}
+}
+
+// Next returns the next line holding a JSON object, or [io.EOF] if there is
+// none left.
+func (lr *LineReader) Next() (json.RawMessage, error) {
+	for lr.scanner.Scan() {
+		line := bytes.TrimSpace(lr.scanner.Bytes())
+		if len(line) > 0 && line[0] == '{' && json.Valid(line) {
+			return bytes.Clone(line), nil
+		}
+		if lr.OnSkip != nil && len(line) > 0 {
+			lr.OnSkip(line)
+		}
+	}
+	if err := lr.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line longer than %d bytes", ErrFrameTooLarge, MaxFrameSize)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// ReadLine reads the next JSON object from lr and decodes it into a T with
+// [Decode].
+func ReadLine[T any](lr *LineReader) (T, error) {
+	var v T
+	line, err := lr.Next()
+	if err != nil {
+		return v, err
+	}
+	err = Decode(line, &v)
+	return v, err
+}
+
+// CallLine writes req to w with [WriteLine] and reads the response from lr
+// with [ReadLine], for a subprocess answering each request line with a
+// response line.
+func CallLine[Req, Resp any](w io.Writer, lr *LineReader, req Req) (Resp, error) {
+	if err := WriteLine(w, req); err != nil {
+		var zero Resp
+		return zero, err
+	}
+	resp, err := ReadLine[Resp](lr)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return resp, err
+}
+
+// ServeLines reads requests from r, one JSON object per line, and writes the
+// response of handle to each of them to w, until r ends, in which case it
+// returns nil. Non-JSON lines of r are skipped.
+//
+// ServeLines stops with the error of handle, or with a request that does not
+// decode into Req, so errors meant for the peer belong in Resp.
+func ServeLines[Req, Resp any](r io.Reader, w io.Writer, handle func(req Req) (Resp, error)) error {
+	lr := NewLineReader(r)
+	for {
+		line, err := lr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req Req
+		if err := Decode(line, &req); err != nil {
+			return fmt.Errorf("jsonify: invalid request: %w", err)
+		}
+		resp, err := handle(req)
+		if err != nil {
+			return err
+		}
+		if err := WriteLine(w, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// linesBufferSize is the size from which [Lines] writes its buffered lines.
+const linesBufferSize = 64 << 10
+
+// Lines writes the elements of v, a slice, an array or an [iter.Seq], to w
+// as JSON Lines: each element encoded with [Bytes] and opts, one per line.
+// The lines are written in batches from a buffer reused between them, so
+// that exporting millions of records costs few writes to w. Indentation
+// options are ignored, as each element must fit on one line.
+//
+// Lines stops at the first error, after writing the lines before it. It
+// fails if v is of another kind, without writing anything.
+func Lines(w io.Writer, v any, opts ...Option) error {
+	var buf []byte
+	write := func(elem any) error {
+		b, err := Bytes(elem, opts...)
+		if err == nil && bytes.IndexByte(b, '\n') >= 0 {
+			b, err = Compact(b)
+		}
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, b...), '\n')
+		if len(buf) >= linesBufferSize {
+			_, err = w.Write(buf)
+			buf = buf[:0]
+		}
+		return err
+	}
+	err := eachElement("Lines", v, write)
+	if len(buf) > 0 {
+		if _, werr := w.Write(buf); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// eachElement calls fn with each element of v, a slice, an array or an
+// [iter.Seq], until fn fails. caller names the function in errors.
+func eachElement(caller string, v any, fn func(elem any) error) error {
+	if seq, ok := v.(iter.Seq[any]); ok {
+		var err error
+		for elem := range seq {
+			if err = fn(elem); err != nil {
+				break
+			}
+		}
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			if err := fn(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Func:
+		if t := rv.Type(); isSeqFunc(t) {
+			var err error
+			rv.Call([]reflect.Value{reflect.MakeFunc(t.In(0), func(args []reflect.Value) []reflect.Value {
+				err = fn(args[0].Interface())
+				return []reflect.Value{reflect.ValueOf(err == nil)}
+			})})
+			return err
+		}
+	}
+	return fmt.Errorf("jsonify: %s of %T, want a slice, an array or an iter.Seq", caller, v)
+}
+
+// isSequence reports whether v is a slice, an array or an [iter.Seq], whose
+// elements [eachElement] calls its function with.
+func isSequence(v any) bool {
+	if v == nil {
+		return false
+	}
+	switch t := reflect.TypeOf(v); t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	case reflect.Func:
+		return isSeqFunc(t)
+	}
+	return false
+}
+
+// isSeqFunc reports whether t is a function type of the shape of an
+// [iter.Seq], func(yield func(V) bool).
+func isSeqFunc(t reflect.Type) bool {
+	if t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func && yield.NumIn() == 1 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+// DecodeLines returns a sequence of the values of the JSON Lines read from
+// r, as written by [Lines], each decoded into a T with [Decode] and opts.
+// T may be a protobuf message pointer, e.g. *pb.User, which is decoded with
+// protojson. Blank lines are skipped.
+//
+// Lines are read one at a time, so memory is bounded by the longest line;
+// lines longer than [MaxFrameSize] fail with [ErrFrameTooLarge]. A line that
+// does not decode yields its error, with its line number, and the sequence
+// continues with the next line if the loop does; a read error ends it.
+//
+//	for user, err := range jsonify.DecodeLines[User](f) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func DecodeLines[T any](r io.Reader, opts ...DecodeOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, MaxFrameSize)
+		for n := 1; scanner.Scan(); n++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var v T
+			if err := Decode(line, &v, opts...); err != nil {
+				var zero T
+				if !yield(zero, fmt.Errorf("jsonify: line %d: %w", n, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				err = fmt.Errorf("%w: line longer than %d bytes", ErrFrameTooLarge, MaxFrameSize)
+			}
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleServeLines() {
+	type request struct {
+		Tool string         `json:"tool"`
+		Args map[string]int `json:"args"`
+	}
+	type response struct {
+		Result int `json:"result"`
+	}
+	stdin := strings.NewReader(`{"tool":"add","args":{"a":1,"b":2}}` + "\n" +
+		"warning: not a JSON line\n" +
+		`{"tool":"add","args":{"a":3,"b":4}}` + "\n")
+	var stdout bytes.Buffer
+	err := jsonify.ServeLines(stdin, &stdout, func(req request) (response, error) {
+		return response{Result: req.Args["a"] + req.Args["b"]}, nil
+	})
+	fmt.Print(stdout.String())
+	fmt.Println(err)
+	// Output:
+	// {"result":3}
+	// {"result":7}
+	// <nil>
+}
+
+func TestLineReader(t *testing.T) {
+	input := "starting...\n\n" + `{"n":1}` + "\n" + `[1,2]` + "\n" + `{"n": 2 }` + "\r\n" + `{"n":` + "\n"
+	lr := jsonify.NewLineReader(strings.NewReader(input))
+	var skipped []string
+	lr.OnSkip = func(line []byte) { skipped = append(skipped, string(line)) }
+
+	type msg struct{ N int }
+	for _, want := range []int{1, 2} {
+		got, err := jsonify.ReadLine[msg](lr)
+		if err != nil || got.N != want {
+			t.Errorf("ReadLine() = %v, %v, want %d", got, err, want)
+		}
+	}
+	if _, err := lr.Next(); err != io.EOF {
+		t.Errorf("Next() at end error = %v, want EOF", err)
+	}
+	if want := []string{"starting...", "[1,2]", `{"n":`}; fmt.Sprint(skipped) != fmt.Sprint(want) {
+		t.Errorf("skipped = %q, want %q", skipped, want)
+	}
+
+	long := jsonify.NewLineReader(io.MultiReader(strings.NewReader(`{"s":"`), strings.NewReader(strings.Repeat("x", jsonify.MaxFrameSize))))
+	if _, err := long.Next(); !errors.Is(err, jsonify.ErrFrameTooLarge) {
+		t.Errorf("Next() of a long line error = %v, want ErrFrameTooLarge", err)
+	}
+}
+
+func TestCallLine(t *testing.T) {
+	var stdin bytes.Buffer
+	stdout := jsonify.NewLineReader(strings.NewReader("loading\n" + `{"ok":true}` + "\n"))
+	type result struct{ OK bool }
+	got, err := jsonify.CallLine[map[string]string, result](&stdin, stdout, map[string]string{"q": "<x>"})
+	if err != nil || !got.OK {
+		t.Errorf("CallLine() = %v, %v", got, err)
+	}
+	if stdin.String() != `{"q":"<x>"}`+"\n" {
+		t.Errorf("CallLine() wrote %q", stdin.String())
+	}
+	if _, err := jsonify.CallLine[int, result](&stdin, stdout, 1); err != io.ErrUnexpectedEOF {
+		t.Errorf("CallLine() without a response error = %v, want ErrUnexpectedEOF", err)
+	}
+}
+
+func TestServeLines(t *testing.T) {
+	var out bytes.Buffer
+	fail := errors.New("fail")
+	err := jsonify.ServeLines(strings.NewReader(`{"n":1}`+"\n"+`{"n":2}`+"\n"), &out, func(req struct{ N int }) (int, error) {
+		if req.N == 2 {
+			return 0, fail
+		}
+		return req.N * 10, nil
+	})
+	if err != fail || out.String() != "10\n" {
+		t.Errorf("ServeLines() = %q, %v", out.String(), err)
+	}
+	err = jsonify.ServeLines(strings.NewReader(`{"n":"x"}`+"\n"), &out, func(req struct{ N int }) (int, error) { return 0, nil })
+	if err == nil {
+		t.Errorf("ServeLines() of an invalid request error = nil, want error")
+	}
+}
+
+func ExampleLines() {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	users := []user{{1, "Ann"}, {2, "Bob"}}
+	jsonify.Lines(os.Stdout, users)
+	// Output:
+	// {"id":1,"name":"Ann"}
+	// {"id":2,"name":"Bob"}
+}
+
+// countingWriter counts the calls of Write.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{name: "slice", v: []any{1, "a", nil, map[string]int{"b": 2}}, want: "1\n\"a\"\nnull\n{\"b\":2}\n"},
+		{name: "array", v: [2]bool{true, false}, want: "true\nfalse\n"},
+		{name: "empty", v: []int{}, want: ""},
+		{name: "nil slice", v: []int(nil), want: ""},
+		{name: "iter.Seq", v: slices.Values([]string{"a", "b"}), want: "\"a\"\n\"b\"\n"},
+		{name: "iter.Seq of any", v: iter.Seq[any](slices.Values([]any{1, "b"})), want: "1\n\"b\"\n"},
+		{name: "indent", v: [][]int{{1, 2}}, opts: []jsonify.Option{jsonify.WithIndent("", "  ")}, want: "[1,2]\n"},
+		{name: "error", v: []any{1, func() {}, 2}, want: "1\n", wantErr: true},
+		{name: "error in iter.Seq", v: slices.Values([]any{1, make(chan int), 2}), want: "1\n", wantErr: true},
+		{name: "not a sequence", v: 1, wantErr: true},
+		{name: "nil", v: nil, wantErr: true},
+		{name: "other func", v: func(int) {}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			err := jsonify.Lines(&sb, tt.v, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Lines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("Lines() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines_batches(t *testing.T) {
+	records := make([]string, 100_000)
+	for i := range records {
+		records[i] = strings.Repeat("x", 10)
+	}
+	var w countingWriter
+	if err := jsonify.Lines(&w, records); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Len(), len(records)*13; got != want {
+		t.Errorf("Lines() wrote %d bytes, want %d", got, want)
+	}
+	if w.writes > w.Len()/(64<<10)+1 {
+		t.Errorf("Lines() wrote %d times, want batches", w.writes)
+	}
+}
+
+func ExampleDecodeLines() {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	r := strings.NewReader("{\"id\":1,\"name\":\"Ann\"}\n\n{\"id\":2,\"name\":\"Bob\"}\n")
+	for u, err := range jsonify.DecodeLines[user](r) {
+		fmt.Println(u, err)
+	}
+	// Output:
+	// {1 Ann} <nil>
+	// {2 Bob} <nil>
+}
+
+func TestDecodeLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []jsonify.DecodeOption
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "values", input: "{\"a\":1}\r\n  {\"a\":2}  \n\n", want: []string{"1 <nil>", "2 <nil>"}},
+		{name: "no final newline", input: `{"a":1}`, want: []string{"1 <nil>"}},
+		{
+			name:  "invalid line",
+			input: "{\"a\":1}\n{\"a\":\"x\"}\n{\"a\":3}\n",
+			want:  []string{"1 <nil>", "0 error on line 2", "3 <nil>"},
+		},
+		{
+			name:  "options",
+			input: "{\"a\":1,\"b\":2}\n",
+			opts:  []jsonify.DecodeOption{jsonify.WithDisallowUnknownFields()},
+			want:  []string{"0 error on line 1"},
+		},
+		{
+			name:  "too long",
+			input: "{\"a\":1}\n" + strings.Repeat(" ", jsonify.MaxFrameSize+1) + "\n{\"a\":3}\n",
+			want:  []string{"1 <nil>", "0 frame too large"},
+		},
+	}
+	type value struct {
+		A int `json:"a"`
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for v, err := range jsonify.DecodeLines[value](strings.NewReader(tt.input), tt.opts...) {
+				switch {
+				case err == nil:
+					got = append(got, fmt.Sprint(v.A, " <nil>"))
+				case errors.Is(err, jsonify.ErrFrameTooLarge):
+					got = append(got, fmt.Sprint(v.A, " frame too large"))
+				case strings.Contains(err.Error(), "line "):
+					line := strings.TrimPrefix(err.Error(), "jsonify: line ")
+					got = append(got, fmt.Sprint(v.A, " error on line ", line[:1]))
+				default:
+					got = append(got, fmt.Sprint(v.A, " ", err))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DecodeLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeLines_break(t *testing.T) {
+	n := 0
+	for range jsonify.DecodeLines[int](strings.NewReader("1\n2\n3\n")) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("DecodeLines() yielded %d values after break, want 1", n)
+	}
+}
+
+func TestDecodeLines_roundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	in := []map[string]any{{"a": "x"}, {"b": []any{1.0, true}}}
+	if err := jsonify.Lines(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	var out []map[string]any
+	for v, err := range jsonify.DecodeLines[map[string]any](&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, v)
+	}
+	if fmt.Sprint(out) != fmt.Sprint(in) {
+		t.Errorf("DecodeLines(Lines()) = %v, want %v", out, in)
+	}
+}
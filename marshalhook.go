@@ -0,0 +1,88 @@
+package jsonify
+
+import (
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// BeforeJSONifier is implemented by types that normalize themselves before
+// they are encoded.
+//
+// BeforeJSONify is called right before a value is encoded, at any depth of
+// the encoded value. If the method has a pointer receiver, it is called on a
+// copy of the value, which is then encoded; the caller's value is never
+// modified.
+//
+// BeforeJSONify is called by the [Jsoniter] backend only.
+type BeforeJSONifier interface {
+	BeforeJSONify()
+}
+
+var beforeJSONifierType = reflect2.TypeOfPtr((*BeforeJSONifier)(nil)).Elem()
+
+var postMarshalHook func(v any, out []byte) []byte
+
+// SetPostMarshalHook sets a hook that is applied to the output of [Bytes],
+// [String] and their variants, e.g. to inject a schema version field.
+// A nil hook removes the current one.
+//
+// The hook receives the value given to the encoding function and the encoded
+// JSON, and returns the JSON to use instead. It is applied once per call, not
+// to values nested inside the encoded value.
+//
+// SetPostMarshalHook is meant to be called during program initialization; it
+// must not be called concurrently with encoding.
+func SetPostMarshalHook(hook func(v any, out []byte) []byte) {
+	postMarshalHook = hook
+}
+
+func postMarshal(v any, out []byte) []byte {
+	if postMarshalHook == nil {
+		return out
+	}
+	return postMarshalHook(v, out)
+}
+
+func init() {
+	encoderDecorators = append(encoderDecorators, decorateBeforeJSONifier)
+}
+
+func decorateBeforeJSONifier(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder {
+	if typ.Kind() == reflect.Interface || typ.Kind() == reflect.Pointer {
+		return enc
+	}
+	if typ.Implements(beforeJSONifierType) {
+		return &beforeJSONifyEncoder{typ: typ, next: enc}
+	}
+	if reflect2.PtrTo(typ).Implements(beforeJSONifierType) {
+		return &beforeJSONifyEncoder{typ: typ, next: enc, copy: true}
+	}
+	return enc
+}
+
+type beforeJSONifyEncoder struct {
+	typ  reflect2.Type
+	next jsoniter.ValEncoder
+	copy bool // the method has a pointer receiver
+}
+
+func (e *beforeJSONifyEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+func (e *beforeJSONifyEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if !e.copy {
+		e.typ.UnsafeIndirect(ptr).(BeforeJSONifier).BeforeJSONify()
+		e.next.Encode(ptr, stream)
+		return
+	}
+	// The value may live in read-only memory, e.g. when it is passed by
+	// value in an interface, so the method is called on a copy.
+	c := e.typ.UnsafeNew()
+	e.typ.UnsafeSet(c, ptr)
+	reflect2.PtrTo(e.typ).UnsafeIndirect(unsafe.Pointer(&c)).(BeforeJSONifier).BeforeJSONify()
+	e.next.Encode(c, stream)
+}
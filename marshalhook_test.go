@@ -0,0 +1,91 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type normalized struct {
+	Email string `json:"email"`
+}
+
+func (n *normalized) BeforeJSONify() {
+	n.Email = strings.ToLower(n.Email)
+}
+
+type counted struct {
+	calls *int
+}
+
+func (c counted) BeforeJSONify() {
+	*c.calls++
+}
+
+func (c counted) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprint(*c.calls)), nil
+}
+
+func ExampleSetPostMarshalHook() {
+	jsonify.SetPostMarshalHook(func(v any, out []byte) []byte {
+		if _, ok := v.(map[string]any); ok && bytes.HasPrefix(out, []byte("{")) {
+			return append([]byte(`{"schema":2,`), out[1:]...)
+		}
+		return out
+	})
+	defer jsonify.SetPostMarshalHook(nil)
+
+	fmt.Println(jsonify.MustString(map[string]any{"name": "Ann"}))
+	// Output:
+	// {"schema":2,"name":"Ann"}
+}
+
+func TestBeforeJSONify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "pointer", input: &normalized{Email: "A@B"}, expected: `{"email":"a@b"}`},
+		{name: "value", input: normalized{Email: "A@B"}, expected: `{"email":"a@b"}`},
+		{name: "nested", input: map[string][]normalized{"x": {{Email: "Q"}}}, expected: `{"x":[{"email":"q"}]}`},
+		{name: "value receiver", input: []counted{{new(int)}}, expected: `[1]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	n := &normalized{Email: "UPPER"}
+	jsonify.MustBytes(n)
+	if n.Email != "UPPER" {
+		t.Errorf("BeforeJSONify() modified the caller's value: %v", n.Email)
+	}
+}
+
+func TestPostMarshalHook(t *testing.T) {
+	var calls int
+	jsonify.SetPostMarshalHook(func(v any, out []byte) []byte {
+		calls++
+		return append(out, '!')
+	})
+	defer jsonify.SetPostMarshalHook(nil)
+
+	got := jsonify.MustString(jsonify.NewSet(1, 2))
+	if got != `[1,2]!` {
+		t.Errorf("MustString() = %v, want %v", got, `[1,2]!`)
+	}
+	if calls != 1 {
+		t.Errorf("hook called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// BytesMasked is [Bytes] of m with only the fields selected by mask, for API
+// handlers implementing partial responses:
+//
+//	jsonify.BytesMasked(user, &fieldmaskpb.FieldMask{Paths: []string{"name", "address.city"}})
+//	// {"name":"Ann","address":{"city":"Paris"}}
+//
+// The paths of mask use the field names of the .proto file, and must be valid
+// for m as [fieldmaskpb.FieldMask.IsValid] reports. An empty mask selects all
+// the fields, as for a read mask. The other fields are encoded as if they were
+// unset, so they are omitted unless the EmitUnpopulated option of
+// [WithProtoOptions] is set. m itself is not modified.
+//
+// BytesMasked is not available with the jsonify_noproto build tag.
+func BytesMasked(m proto.Message, mask *fieldmaskpb.FieldMask, opts ...Option) ([]byte, error) {
+	if len(mask.GetPaths()) == 0 {
+		return Bytes(m, opts...)
+	}
+	return bytesMasked(m, mask, false, opts)
+}
+
+// BytesExcluding is [Bytes] of m without the fields selected by mask, which
+// must be valid for m as for [BytesMasked]. m itself is not modified.
+//
+// BytesExcluding is not available with the jsonify_noproto build tag.
+func BytesExcluding(m proto.Message, mask *fieldmaskpb.FieldMask, opts ...Option) ([]byte, error) {
+	return bytesMasked(m, mask, true, opts)
+}
+
+func bytesMasked(m proto.Message, mask *fieldmaskpb.FieldMask, exclude bool, opts []Option) ([]byte, error) {
+	if !m.ProtoReflect().IsValid() {
+		return Bytes(m, opts...)
+	}
+	if !mask.IsValid(m) {
+		return nil, fmt.Errorf("jsonify: invalid field mask %v for %s", mask.GetPaths(), m.ProtoReflect().Descriptor().FullName())
+	}
+	c := proto.Clone(m)
+	pruneMessage(c.ProtoReflect(), newMaskTree(mask.GetPaths()), exclude)
+	return Bytes(c, opts...)
+}
+
+// maskTree is a field mask as a tree of field names. A name without a
+// subtree selects the whole field.
+type maskTree map[protoreflect.Name]maskTree
+
+func newMaskTree(paths []string) maskTree {
+	root := maskTree{}
+	for _, path := range paths {
+		node := root
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			n := protoreflect.Name(name)
+			child, ok := node[n]
+			if ok && child == nil {
+				break // A shorter path already selects the whole field.
+			}
+			if i == len(names)-1 {
+				node[n] = nil
+				break
+			}
+			if !ok {
+				child = maskTree{}
+				node[n] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
+
+// pruneMessage clears the fields of m that are not selected by tree, or
+// those that are if exclude is set.
+func pruneMessage(m protoreflect.Message, tree maskTree, exclude bool) {
+	var clear, descend []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		sub, ok := tree[fd.Name()]
+		switch {
+		case sub != nil:
+			descend = append(descend, fd)
+		case ok == exclude:
+			clear = append(clear, fd)
+		}
+		return true
+	})
+	for _, fd := range clear {
+		m.Clear(fd)
+	}
+	for _, fd := range descend {
+		// A valid mask descends into singular message fields only.
+		pruneMessage(m.Mutable(fd).Message(), tree[fd.Name()], exclude)
+	}
+}
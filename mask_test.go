@@ -0,0 +1,69 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func ExampleBytesMasked() {
+	m := &descriptorpb.DescriptorProto{
+		Name:    proto.String("User"),
+		Field:   []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id")}},
+		Options: &descriptorpb.MessageOptions{Deprecated: proto.Bool(true), MapEntry: proto.Bool(false)},
+	}
+	b, err := jsonify.BytesMasked(m, &fieldmaskpb.FieldMask{Paths: []string{"name", "options.deprecated"}})
+	fmt.Println(string(b), err)
+	// Output:
+	// {"name":"User","options":{"deprecated":true}} <nil>
+}
+
+func TestBytesMasked(t *testing.T) {
+	m := &descriptorpb.DescriptorProto{
+		Name:    proto.String("User"),
+		Field:   []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id")}},
+		Options: &descriptorpb.MessageOptions{Deprecated: proto.Bool(true), MapEntry: proto.Bool(false)},
+	}
+	all := `{"name":"User","field":[{"name":"id"}],"options":{"deprecated":true,"mapEntry":false}}`
+
+	tests := []struct {
+		name     string
+		fn       func(proto.Message, *fieldmaskpb.FieldMask, ...jsonify.Option) ([]byte, error)
+		paths    []string
+		expected string
+		wantErr  bool
+	}{
+		{name: "fields", fn: jsonify.BytesMasked, paths: []string{"name", "field"}, expected: `{"name":"User","field":[{"name":"id"}]}`},
+		{name: "subfield", fn: jsonify.BytesMasked, paths: []string{"options.map_entry"}, expected: `{"options":{"mapEntry":false}}`},
+		{name: "overlapping", fn: jsonify.BytesMasked, paths: []string{"options.map_entry", "options"}, expected: `{"options":{"deprecated":true,"mapEntry":false}}`},
+		{name: "empty", fn: jsonify.BytesMasked, paths: nil, expected: all},
+		{name: "invalid", fn: jsonify.BytesMasked, paths: []string{"bogus"}, wantErr: true},
+		{name: "through repeated", fn: jsonify.BytesMasked, paths: []string{"field.name"}, wantErr: true},
+		{name: "excluding", fn: jsonify.BytesExcluding, paths: []string{"field", "options.deprecated"}, expected: `{"name":"User","options":{"mapEntry":false}}`},
+		{name: "excluding nothing", fn: jsonify.BytesExcluding, paths: nil, expected: all},
+		{name: "excluding invalid", fn: jsonify.BytesExcluding, paths: []string{"bogus"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(m, &fieldmaskpb.FieldMask{Paths: tt.paths})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+	if got := jsonify.MustString(m); got != all {
+		t.Errorf("the message was modified: %s", got)
+	}
+	if got, err := jsonify.BytesMasked((*descriptorpb.DescriptorProto)(nil), &fieldmaskpb.FieldMask{Paths: []string{"name"}}); err != nil || string(got) != "null" {
+		t.Errorf("BytesMasked() of a nil message = %s, %v", got, err)
+	}
+}
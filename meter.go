@@ -0,0 +1,81 @@
+package jsonify
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Meter encodes values like an [Encoder] and counts the bytes it produces
+// per tenant, for billing or enforcing quotas on payload volume. The tenant
+// of a call is the value of type T stored in its context under a key:
+//
+//	type tenantKey struct{}
+//
+//	var meter = jsonify.NewMeter[string](tenantKey{})
+//
+//	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+//	b, err := meter.Bytes(ctx, resp)
+//
+// Calls whose context has no tenant are counted under the zero value of T.
+// Failed calls are not counted. A Meter is safe for concurrent use.
+type Meter[T comparable] struct {
+	key any
+	enc *Encoder
+
+	mu     sync.Mutex
+	totals map[T]int64
+}
+
+// NewMeter returns a [Meter] reading the tenant from the context value of
+// key, and applying opts to every call.
+func NewMeter[T comparable](key any, opts ...Option) *Meter[T] {
+	return &Meter[T]{key: key, enc: New(opts...), totals: map[T]int64{}}
+}
+
+// Bytes is [Encoder.Bytes], counting the result for the tenant of ctx.
+func (m *Meter[T]) Bytes(ctx context.Context, v any, opts ...Option) ([]byte, error) {
+	b, err := m.enc.Bytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m.add(ctx, len(b))
+	return b, nil
+}
+
+// String is [Encoder.String], counting the result for the tenant of ctx.
+func (m *Meter[T]) String(ctx context.Context, v any, opts ...Option) (string, error) {
+	b, err := m.Bytes(ctx, v, opts...)
+	return string(b), err
+}
+
+func (m *Meter[T]) add(ctx context.Context, n int) {
+	tenant, _ := ctx.Value(m.key).(T)
+	m.mu.Lock()
+	m.totals[tenant] += int64(n)
+	m.mu.Unlock()
+}
+
+// Total returns the number of bytes encoded for tenant.
+func (m *Meter[T]) Total(tenant T) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals[tenant]
+}
+
+// Totals returns the number of bytes encoded for each tenant.
+func (m *Meter[T]) Totals() map[T]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.totals)
+}
+
+// Reset returns the totals, like [Meter.Totals], and sets them to zero, e.g.
+// to report the usage of each billing period exactly once.
+func (m *Meter[T]) Reset() map[T]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	totals := m.totals
+	m.totals = map[T]int64{}
+	return totals
+}
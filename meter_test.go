@@ -0,0 +1,57 @@
+package jsonify_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type tenantKey struct{}
+
+func ExampleMeter() {
+	meter := jsonify.NewMeter[string](tenantKey{})
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	meter.Bytes(ctx, map[string]int{"id": 1})
+	meter.Bytes(ctx, []string{"a", "b"})
+	fmt.Println(meter.Total("acme"))
+	// Output:
+	// 17
+}
+
+func TestMeter(t *testing.T) {
+	meter := jsonify.NewMeter[int64](tenantKey{}, jsonify.WithIndent("", " "))
+	acme := context.WithValue(context.Background(), tenantKey{}, int64(1))
+	other := context.WithValue(context.Background(), tenantKey{}, "not an int64")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			meter.Bytes(acme, []int{1}) // "[\n 1\n]" is 6 bytes.
+		}()
+	}
+	wg.Wait()
+	if s, err := meter.String(other, 12); err != nil || s != "12" {
+		t.Errorf("String() = %v, %v", s, err)
+	}
+	if _, err := meter.Bytes(acme, make(chan int)); err == nil {
+		t.Errorf("Bytes() of an unsupported value error = nil, want error")
+	}
+
+	want := map[int64]int64{1: 60, 0: 2}
+	if got := meter.Totals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Totals() = %v, want %v", got, want)
+	}
+	if got := meter.Reset(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reset() = %v, want %v", got, want)
+	}
+	if got := meter.Total(1); got != 0 {
+		t.Errorf("Total() after Reset() = %v, want 0", got)
+	}
+}
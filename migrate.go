@@ -0,0 +1,141 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Migrations decodes documents persisted by older versions of a program into
+// the current version of T, e.g. after a field was renamed or restructured.
+//
+// Each document holds its version in a field of its top-level object, and
+// each registered step migrates a document from one version to the next:
+//
+//	var userMigrations = jsonify.NewMigrations[User]("version").
+//		Register(1, func(old map[string]any) map[string]any {
+//			old["fullName"] = old["name"] // version 2 renamed name.
+//			delete(old, "name")
+//			return old
+//		})
+//
+// The current version is the one after the last step; a document without a
+// version field is at version 1. A Migrations is safe for concurrent use.
+type Migrations[T any] struct {
+	field string
+
+	mu    sync.RWMutex
+	steps map[int]func(old map[string]any) map[string]any
+}
+
+// NewMigrations returns [Migrations] for documents holding their version
+// in the given field, e.g. "version".
+func NewMigrations[T any](field string) *Migrations[T] {
+	return &Migrations[T]{field: field, steps: map[int]func(map[string]any) map[string]any{}}
+}
+
+// Register registers fn to migrate a document from version to version+1,
+// and returns m. fn may modify old and return it. The numbers in old are
+// [json.Number] values, and the version field is set by m after fn returns.
+//
+// Register panics if version is less than 1 or already has a step.
+func (m *Migrations[T]) Register(version int, fn func(old map[string]any) map[string]any) *Migrations[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version < 1 {
+		panic(fmt.Sprintf("jsonify: migration from version %d, want 1 or more", version))
+	}
+	if _, ok := m.steps[version]; ok {
+		panic(fmt.Sprintf("jsonify: migration from version %d registered twice", version))
+	}
+	m.steps[version] = fn
+	return m
+}
+
+// Version returns the current version, the one after the last registered
+// step.
+func (m *Migrations[T]) Version() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.version()
+}
+
+func (m *Migrations[T]) version() int {
+	current := 1
+	for v := range m.steps {
+		current = max(current, v+1)
+	}
+	return current
+}
+
+// Decode decodes data, a JSON object, into v with [Decode], after applying
+// the steps from its version to the current one, and setting its version
+// field to the current version.
+//
+// It fails if the document is newer than the current version, or if a step
+// between its version and the current one is missing.
+func (m *Migrations[T]) Decode(data []byte, v *T, opts ...DecodeOption) error {
+	var doc map[string]any
+	if err := Decode(data, &doc, append(opts[:len(opts):len(opts)], UseNumber())...); err != nil {
+		return err
+	}
+	if doc == nil {
+		return fmt.Errorf("jsonify: migrated document is null, want an object")
+	}
+	version, err := m.documentVersion(doc)
+	if err != nil {
+		return err
+	}
+	steps, err := m.pending(version)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if doc = step(doc); doc == nil {
+			return fmt.Errorf("jsonify: migration from version %d returned nil", version)
+		}
+		version++
+	}
+	doc[m.field] = json.Number(strconv.Itoa(version))
+	if data, err = marshal(doc); err != nil {
+		return err
+	}
+	return Decode(data, v, opts...)
+}
+
+// pending returns the steps from version to the current version.
+func (m *Migrations[T]) pending(version int) ([]func(map[string]any) map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	current := m.version()
+	if version > current {
+		return nil, fmt.Errorf("jsonify: document version %d is newer than %d", version, current)
+	}
+	var steps []func(map[string]any) map[string]any
+	for ; version < current; version++ {
+		step, ok := m.steps[version]
+		if !ok {
+			return nil, fmt.Errorf("jsonify: no migration from version %d", version)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// documentVersion returns the version of doc, 1 if it has none.
+func (m *Migrations[T]) documentVersion(doc map[string]any) (int, error) {
+	raw, ok := doc[m.field]
+	if !ok || raw == nil {
+		return 1, nil
+	}
+	n, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("jsonify: version field %q is %v, want an integer", m.field, raw)
+	}
+	version, err := n.Int64()
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("jsonify: version field %q is %v, want an integer of 1 or more", m.field, raw)
+	}
+	return int(version), nil
+}
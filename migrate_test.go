@@ -0,0 +1,90 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type migratedUser struct {
+	Version  int      `json:"version"`
+	FullName string   `json:"fullName"`
+	Tags     []string `json:"tags"`
+}
+
+func ExampleMigrations() {
+	migrations := jsonify.NewMigrations[migratedUser]("version").
+		Register(1, func(old map[string]any) map[string]any {
+			old["fullName"] = old["name"]
+			delete(old, "name")
+			return old
+		})
+
+	var u migratedUser
+	err := migrations.Decode([]byte(`{"name":"Ann"}`), &u)
+	fmt.Printf("%+v %v\n", u, err)
+	// Output:
+	// {Version:2 FullName:Ann Tags:[]} <nil>
+}
+
+func TestMigrations(t *testing.T) {
+	migrations := jsonify.NewMigrations[migratedUser]("version").
+		Register(1, func(old map[string]any) map[string]any {
+			old["fullName"] = old["name"]
+			delete(old, "name")
+			return old
+		}).
+		Register(2, func(old map[string]any) map[string]any {
+			if tag, ok := old["tag"].(string); ok {
+				old["tags"] = []string{tag}
+			}
+			delete(old, "tag")
+			return old
+		})
+	if got := migrations.Version(); got != 3 {
+		t.Errorf("Version() = %v, want 3", got)
+	}
+
+	want := migratedUser{Version: 3, FullName: "Ann", Tags: []string{"admin"}}
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "unversioned", data: `{"name":"Ann","tag":"admin"}`},
+		{name: "version 1", data: `{"version":1,"name":"Ann","tag":"admin"}`},
+		{name: "version 2", data: `{"version":2,"fullName":"Ann","tag":"admin"}`},
+		{name: "current", data: `{"version":3,"fullName":"Ann","tags":["admin"]}`},
+		{name: "newer", data: `{"version":4}`, wantErr: true},
+		{name: "invalid version", data: `{"version":"2"}`, wantErr: true},
+		{name: "zero version", data: `{"version":0}`, wantErr: true},
+		{name: "not an object", data: `[]`, wantErr: true},
+		{name: "null", data: `null`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got migratedUser
+			err := migrations.Decode([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("Decode() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	gap := jsonify.NewMigrations[migratedUser]("version").
+		Register(2, func(old map[string]any) map[string]any { return old })
+	var u migratedUser
+	if err := gap.Decode([]byte(`{}`), &u); err == nil {
+		t.Errorf("Decode() with a missing migration error = nil, want error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() of a version twice did not panic")
+		}
+	}()
+	gap.Register(2, func(old map[string]any) map[string]any { return old })
+}
@@ -0,0 +1,167 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money in a specific currency.
+//
+// The amount is kept as a decimal string so that it is never rounded through
+// a float. Money encodes as
+//
+//	{"amount":"12.34","currency":"USD"}
+//
+// Both encoding and decoding validate the value: the amount must be a plain
+// decimal number such as "-0.5" or "100", and the currency must be a
+// three-letter upper-case ISO 4217 code. Decoding rejects amounts given as
+// JSON numbers.
+//
+// The zero Money, e.g. an unset field, encodes as null, and decoding null
+// leaves a Money unchanged, as for other types.
+type Money struct {
+	Amount   string
+	Currency string
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// NewMoney returns a Money after validating amount and currency.
+func NewMoney(amount, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: currency}
+	return m, m.Validate()
+}
+
+// MoneyFromUnits returns a Money from the units/nanos representation used by
+// google.type.Money.
+//
+// units and nanos must have the same sign, and nanos must be in the range
+// (-1e9, 1e9).
+func MoneyFromUnits(units int64, nanos int32, currency string) (Money, error) {
+	if nanos <= -1e9 || nanos >= 1e9 || (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return Money{}, fmt.Errorf("jsonify: invalid money units %d and nanos %d", units, nanos)
+	}
+	amount := strconv.FormatInt(units, 10)
+	if nanos != 0 {
+		if units == 0 && nanos < 0 {
+			amount = "-0"
+		}
+		frac := fmt.Sprintf("%09d", int64(math.Abs(float64(nanos))))
+		amount += "." + strings.TrimRight(frac, "0")
+	}
+	return NewMoney(amount, currency)
+}
+
+// Units returns m in the units/nanos representation used by google.type.Money.
+//
+// It fails if m is invalid, has more than nine fractional digits, or does not
+// fit into int64 units.
+func (m Money) Units() (units int64, nanos int32, err error) {
+	if err := m.Validate(); err != nil {
+		return 0, 0, err
+	}
+	neg := strings.HasPrefix(m.Amount, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(m.Amount, "-"), ".")
+	if len(frac) > 9 {
+		return 0, 0, fmt.Errorf("jsonify: money amount %q has more than nine fractional digits", m.Amount)
+	}
+	units, err = strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("jsonify: money amount %q is out of range", m.Amount)
+	}
+	n, _ := strconv.ParseInt((frac + "000000000")[:9], 10, 32)
+	if neg {
+		units, n = -units, -n
+	}
+	return units, int32(n), nil
+}
+
+// Validate reports whether m has a valid amount and currency.
+func (m Money) Validate() error {
+	if !validDecimal(m.Amount) {
+		return fmt.Errorf("jsonify: invalid money amount %q", m.Amount)
+	}
+	if !validCurrency(m.Currency) {
+		return fmt.Errorf("jsonify: invalid currency code %q", m.Currency)
+	}
+	return nil
+}
+
+// String returns the amount followed by the currency, e.g. "12.34 USD".
+func (m Money) String() string {
+	return m.Amount + " " + m.Currency
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m == (Money{}) {
+		return []byte("null"), nil
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	b := append([]byte(`{"amount":`), strconv.Quote(m.Amount)...)
+	b = append(b, `,"currency":`...)
+	b = append(b, strconv.Quote(m.Currency)...)
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Amount) == 0 || v.Amount[0] != '"' {
+		return fmt.Errorf("jsonify: money amount must be a string, got %s", v.Amount)
+	}
+	var amount string
+	if err := json.Unmarshal(v.Amount, &amount); err != nil {
+		return err
+	}
+	money, err := NewMoney(amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+func validDecimal(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || (len(whole) > 1 && whole[0] == '0') || !allDigits(whole) {
+		return false
+	}
+	return !hasFrac || (frac != "" && allDigits(frac))
+}
+
+func validCurrency(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
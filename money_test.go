@@ -0,0 +1,119 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleMoney() {
+	price, _ := jsonify.NewMoney("19.99", "EUR")
+	fmt.Println(jsonify.MustString(map[string]any{"price": price}))
+	// Output:
+	// {"price":{"amount":"19.99","currency":"EUR"}}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		wantErr  bool
+	}{
+		{name: "integer", amount: "100", currency: "JPY"},
+		{name: "decimal", amount: "12.34", currency: "USD"},
+		{name: "negative", amount: "-0.5", currency: "USD"},
+		{name: "leading zero", amount: "012", currency: "USD", wantErr: true},
+		{name: "exponent", amount: "1e3", currency: "USD", wantErr: true},
+		{name: "empty fraction", amount: "1.", currency: "USD", wantErr: true},
+		{name: "lower-case currency", amount: "1", currency: "usd", wantErr: true},
+		{name: "long currency", amount: "1", currency: "USDT", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := jsonify.Money{Amount: tt.amount, Currency: tt.currency}
+			got, err := jsonify.String(m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var decoded jsonify.Money
+			if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if decoded != m {
+				t.Errorf("round trip = %v, want %v", decoded, m)
+			}
+		})
+	}
+}
+
+func TestMoneyZero(t *testing.T) {
+	type order struct {
+		ID    int           `json:"id"`
+		Price jsonify.Money `json:"price"`
+	}
+	got, err := jsonify.String(order{ID: 1})
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+	if want := `{"id":1,"price":null}`; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+
+	price, _ := jsonify.NewMoney("1", "USD")
+	for name, decode := range map[string]func([]byte, any) error{
+		"Decode":    func(data []byte, v any) error { return jsonify.Decode(data, v) },
+		"Unmarshal": json.Unmarshal,
+	} {
+		o := order{Price: price}
+		if err := decode([]byte(`{"id":2,"price":null}`), &o); err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if o.Price != price {
+			t.Errorf("%s() of null changed the price to %v", name, o.Price)
+		}
+	}
+}
+
+func TestMoneyUnmarshalRejectsNumbers(t *testing.T) {
+	var m jsonify.Money
+	if err := json.Unmarshal([]byte(`{"amount":12.34,"currency":"USD"}`), &m); err == nil {
+		t.Errorf("Unmarshal() accepted a numeric amount")
+	}
+}
+
+func TestMoneyUnits(t *testing.T) {
+	tests := []struct {
+		units    int64
+		nanos    int32
+		expected string
+	}{
+		{12, 340000000, "12.34"},
+		{-1, -750000000, "-1.75"},
+		{0, -500000000, "-0.5"},
+		{5, 0, "5"},
+		{0, 1, "0.000000001"},
+	}
+	for _, tt := range tests {
+		m, err := jsonify.MoneyFromUnits(tt.units, tt.nanos, "USD")
+		if err != nil {
+			t.Fatalf("MoneyFromUnits(%d, %d) error = %v", tt.units, tt.nanos, err)
+		}
+		if m.Amount != tt.expected {
+			t.Errorf("MoneyFromUnits(%d, %d) = %q, want %q", tt.units, tt.nanos, m.Amount, tt.expected)
+		}
+		units, nanos, err := m.Units()
+		if err != nil || units != tt.units || nanos != tt.nanos {
+			t.Errorf("Units() = %d, %d, %v, want %d, %d", units, nanos, err, tt.units, tt.nanos)
+		}
+	}
+
+	if _, err := jsonify.MoneyFromUnits(1, -1, "USD"); err == nil {
+		t.Errorf("MoneyFromUnits() with mismatched signs did not fail")
+	}
+}
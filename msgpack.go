@@ -0,0 +1,147 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MsgpackBytes returns v encoded as MessagePack, transcoded from its
+// encoding with [Bytes] and opts, for services that want a binary format
+// with the same field semantics as the JSON APIs: the same field names, the
+// same map key order, [json.RawMessage] values transcoded, and protobuf
+// messages in their protojson mapping.
+//
+// Objects become maps with string keys, and arrays become arrays, in the
+// smallest formats that hold them. Integers become the smallest integer
+// format that holds them, and other numbers become 64-bit floats. As the
+// encoding goes through JSON, byte slices are base64 strings, as in JSON,
+// rather than binary values.
+func MsgpackBytes(v any, opts ...Option) ([]byte, error) {
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(b) {
+		return nil, errors.New("jsonify: MsgpackBytes of invalid JSON")
+	}
+	return appendMsgpack(nil, bytes.TrimSpace(b))
+}
+
+// appendMsgpack appends the JSON value raw, without leading or trailing
+// space, to b as MessagePack.
+func appendMsgpack(b []byte, raw []byte) ([]byte, error) {
+	switch raw[0] {
+	case '{':
+		keys, values, err := objectMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+		b = appendMsgpackHeader(b, len(keys), 0x80, 0xde, 0xdf)
+		for i, key := range keys {
+			b = appendMsgpackString(b, key)
+			if b, err = appendMsgpack(b, values[i]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '[':
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, err
+		}
+		b = appendMsgpackHeader(b, len(elems), 0x90, 0xdc, 0xdd)
+		for _, elem := range elems {
+			var err error
+			if b, err = appendMsgpack(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '"':
+		s, err := unquote(raw)
+		if err != nil {
+			return nil, err
+		}
+		return appendMsgpackString(b, s), nil
+	case 'n':
+		return append(b, 0xc0), nil
+	case 't':
+		return append(b, 0xc3), nil
+	case 'f':
+		return append(b, 0xc2), nil
+	}
+	return appendMsgpackNumber(b, string(raw))
+}
+
+// appendMsgpackHeader appends the header of a map or an array of n entries,
+// in the fix, 16-bit or 32-bit format of the given codes.
+func appendMsgpackHeader(b []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackNumber appends the JSON number s as the smallest integer
+// format that holds it, or else as a 64-bit float.
+func appendMsgpackNumber(b []byte, s string) ([]byte, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch {
+		case i >= 0:
+			return appendMsgpackUint(b, uint64(i)), nil
+		case i >= -32:
+			return append(b, byte(i)), nil // Negative fixint.
+		case i >= math.MinInt8:
+			return append(b, 0xd0, byte(i)), nil
+		case i >= math.MinInt16:
+			return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i)), nil
+		case i >= math.MinInt32:
+			return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i)), nil
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i)), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return appendMsgpackUint(b, u), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: MsgpackBytes of number %s: %w", s, err)
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+}
+
+func appendMsgpackUint(b []byte, u uint64) []byte {
+	switch {
+	case u < 128:
+		return append(b, byte(u)) // Positive fixint.
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(u))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+}
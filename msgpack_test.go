@@ -0,0 +1,70 @@
+package jsonify_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleMsgpackBytes() {
+	b, err := jsonify.MsgpackBytes(map[string]any{"id": 1, "tags": []string{"a"}})
+	fmt.Printf("% x\n", b)
+	fmt.Println(err)
+	// Output:
+	// 82 a2 69 64 01 a4 74 61 67 73 91 a1 61
+	// <nil>
+}
+
+func TestMsgpackBytes(t *testing.T) {
+	type user struct {
+		ID   int64  `json:"id,string"`
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		want    string // Hex.
+		wantErr bool
+	}{
+		{name: "nil", v: nil, want: "c0"},
+		{name: "bools", v: []bool{true, false}, want: "92c3c2"},
+		{name: "positive fixint", v: 127, want: "7f"},
+		{name: "uint8", v: 128, want: "cc80"},
+		{name: "uint16", v: 256, want: "cd0100"},
+		{name: "uint32", v: 1 << 16, want: "ce00010000"},
+		{name: "uint64", v: uint64(math.MaxUint64), want: "cfffffffffffffffff"},
+		{name: "negative fixint", v: -32, want: "e0"},
+		{name: "int8", v: -33, want: "d0df"},
+		{name: "int16", v: -129, want: "d1ff7f"},
+		{name: "int32", v: -1 << 16, want: "d2ffff0000"},
+		{name: "int64", v: int64(math.MinInt64), want: "d38000000000000000"},
+		{name: "float", v: 1.5, want: "cb3ff8000000000000"},
+		{name: "exponent", v: json.RawMessage("1e2"), want: "cb4059000000000000"},
+		{name: "fixstr", v: "é", want: "a2c3a9"},
+		{name: "str8", v: strings.Repeat("a", 32), want: "d920" + strings.Repeat("61", 32)},
+		{name: "str16", v: strings.Repeat("a", 256), want: "da0100" + strings.Repeat("61", 256)},
+		{name: "array16", v: make([]int, 16), want: "dc0010" + strings.Repeat("00", 16)},
+		{name: "empty", v: []any{map[string]int{}, []int{}}, want: "928090"},
+		{name: "struct", v: user{ID: 1, Name: "A"}, want: "82a26964a131a46e616d65a141"},
+		{name: "raw message", v: json.RawMessage(` {"a" : [ null ]} `), want: "81a16191c0"},
+		{name: "out of range", v: json.RawMessage("1e400"), wantErr: true},
+		{name: "invalid raw message", v: json.RawMessage("{"), wantErr: true},
+		{name: "NaN", v: math.NaN(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.MsgpackBytes(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MsgpackBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("MsgpackBytes() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
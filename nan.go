@@ -0,0 +1,48 @@
+package jsonify
+
+import (
+	"encoding"
+	"encoding/json"
+
+	"github.com/modern-go/reflect2"
+)
+
+// NaNPolicy is how NaN and infinite floats are encoded, which JSON cannot
+// represent as numbers.
+type NaNPolicy int
+
+const (
+	// NaNError fails the encoding. It is the default.
+	NaNError NaNPolicy = iota
+
+	// NaNAsNull encodes NaN and infinities as null.
+	NaNAsNull
+
+	// NaNAsString encodes NaN and infinities as the strings "NaN",
+	// "Infinity" and "-Infinity", as protojson does.
+	NaNAsString
+)
+
+// WithNaN returns an [Option] that encodes NaN and infinite floats according
+// to policy.
+//
+// It applies to values of float kinds, except types implementing
+// [json.Marshaler] or [encoding.TextMarshaler] and types with a registered
+// encoder.
+func WithNaN(policy NaNPolicy) Option {
+	return func(o *options) {
+		o.mode.nan = policy
+	}
+}
+
+var (
+	jsonMarshalerType = reflect2.TypeOfPtr((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect2.TypeOfPtr((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// hasMarshaler reports whether typ or a pointer to it marshals itself.
+func hasMarshaler(typ reflect2.Type) bool {
+	ptr := reflect2.PtrTo(typ)
+	return typ.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType) ||
+		typ.Implements(textMarshalerType) || ptr.Implements(textMarshalerType)
+}
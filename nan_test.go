@@ -0,0 +1,55 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type celsius float64
+
+func (c celsius) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%v C"`, float64(c))), nil
+}
+
+func ExampleWithNaN() {
+	samples := []float64{1.5, math.NaN(), math.Inf(1)}
+	fmt.Println(jsonify.MustString(samples, jsonify.WithNaN(jsonify.NaNAsNull)))
+	fmt.Println(jsonify.MustString(samples, jsonify.WithNaN(jsonify.NaNAsString)))
+	// Output:
+	// [1.5,null,null]
+	// [1.5,"NaN","Infinity"]
+}
+
+func TestWithNaN(t *testing.T) {
+	type reading struct {
+		Value float32 `json:"value"`
+		Skip  float64 `json:"skip,omitempty"`
+	}
+	tests := []struct {
+		name     string
+		input    any
+		policy   jsonify.NaNPolicy
+		expected string
+		wantErr  bool
+	}{
+		{name: "error", input: math.NaN(), policy: jsonify.NaNError, wantErr: true},
+		{name: "null", input: map[string]any{"x": math.Inf(-1)}, policy: jsonify.NaNAsNull, expected: `{"x":null}`},
+		{name: "string", input: []float64{math.Inf(-1), 0.1}, policy: jsonify.NaNAsString, expected: `["-Infinity",0.1]`},
+		{name: "float32", input: reading{Value: float32(math.NaN())}, policy: jsonify.NaNAsString, expected: `{"value":"NaN"}`},
+		{name: "marshaler", input: celsius(math.NaN()), policy: jsonify.NaNAsNull, expected: `"NaN C"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, jsonify.WithNaN(tt.policy))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
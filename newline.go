@@ -0,0 +1,22 @@
+package jsonify
+
+// WithTrailingNewline returns an [Option] that ends the output with "\n",
+// as POSIX text files and the output of tools such as jq do, e.g. for
+// output written to a file or to stdout. By default, the output has no
+// trailing newline.
+//
+// It applies to whole outputs, e.g. of [Bytes] and [Encode]; functions
+// that delimit values themselves, e.g. [Lines] and [WriteSeq], ignore it.
+func WithTrailingNewline() Option {
+	return func(o *options) {
+		o.trailingNewline = true
+	}
+}
+
+// noTrailingNewline returns opts overriding [WithTrailingNewline], for
+// functions that delimit values themselves.
+func noTrailingNewline(opts []Option) []Option {
+	return append(opts[:len(opts):len(opts)], func(o *options) {
+		o.trailingNewline = false
+	})
+}
@@ -0,0 +1,63 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithTrailingNewline() {
+	b := jsonify.MustBytes(map[string]int{"a": 1}, jsonify.WithTrailingNewline())
+	os.Stdout.Write(b)
+	os.Stdout.Write(b)
+	// Output:
+	// {"a":1}
+	// {"a":1}
+}
+
+func TestWithTrailingNewline(t *testing.T) {
+	raw := make(json.RawMessage, 0, 10)
+	raw = append(raw, "[1]"...)
+	tests := []struct {
+		name string
+		v    any
+		opts []jsonify.Option
+		want string
+	}{
+		{name: "scalar", v: 1, want: "1\n"},
+		{name: "raw message", v: raw, want: "[1]\n"},
+		{name: "indent", v: []int{1}, opts: []jsonify.Option{jsonify.WithIndent("", " ")}, want: "[\n 1\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append(tt.opts, jsonify.WithTrailingNewline())
+			if got := jsonify.MustString(tt.v, opts...); got != tt.want {
+				t.Errorf("MustString() = %q, want %q", got, tt.want)
+			}
+			if len(tt.opts) > 0 {
+				return // Encode does not indent.
+			}
+			var buf bytes.Buffer
+			if err := jsonify.Encode(&buf, tt.v, opts...); err != nil || buf.String() != tt.want {
+				t.Errorf("Encode() wrote %q, %v, want %q", buf.String(), err, tt.want)
+			}
+		})
+	}
+	if string(raw[:cap(raw)][3:4]) == "\n" {
+		t.Error("MustString() modified the json.RawMessage value")
+	}
+	if got := jsonify.MustString(1); got != "1" {
+		t.Errorf("MustString() without the option = %q", got)
+	}
+
+	var buf bytes.Buffer
+	jsonify.WriteSeq(&buf, 1, jsonify.WithTrailingNewline())
+	jsonify.Lines(&buf, []int{2}, jsonify.WithTrailingNewline())
+	jsonify.Colorize(&buf, 3, jsonify.WithTrailingNewline())
+	if want := "\x1e1\n2\n3\n"; buf.String() != want {
+		t.Errorf("WriteSeq(), Lines() and Colorize() wrote %q, want %q", buf.String(), want)
+	}
+}
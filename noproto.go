@@ -0,0 +1,53 @@
+//go:build jsonify_noproto
+
+package jsonify
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// protoOptions is empty because protobuf support is excluded by the
+// jsonify_noproto build tag.
+type protoOptions struct{}
+
+// protoDecodeOptions is empty because protobuf support is excluded by the
+// jsonify_noproto build tag.
+type protoDecodeOptions struct{}
+
+// WithProtoAsGo returns an [Option] that encodes protobuf messages like other
+// Go structs, which is how they are encoded with the jsonify_noproto build
+// tag anyway.
+func WithProtoAsGo() Option {
+	return func(o *options) {}
+}
+
+// marshalProto always reports false because protobuf support is excluded by
+// the jsonify_noproto build tag.
+func marshalProto(v any, o *options) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+// unmarshalProto always reports false because protobuf support is excluded
+// by the jsonify_noproto build tag.
+func unmarshalProto(data []byte, v any, o *decodeOptions) (bool, error) {
+	return false, nil
+}
+
+// protoEncoderOf always returns nil because protobuf support is excluded by
+// the jsonify_noproto build tag.
+func protoEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	return nil
+}
+
+// protoDecoderOf always returns nil because protobuf support is excluded by
+// the jsonify_noproto build tag.
+func protoDecoderOf(typ reflect2.Type, m mode) jsoniter.ValDecoder {
+	return nil
+}
+
+// isProtoValue always reports false because protobuf support is excluded
+// by the jsonify_noproto build tag.
+func isProtoValue(v any) bool {
+	return false
+}
@@ -0,0 +1,25 @@
+//go:build jsonify_noproto
+
+package jsonify_test
+
+import (
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type fakeMessage struct {
+	Foo string `json:"foo"`
+}
+
+func (*fakeMessage) ProtoReflect() {}
+
+func TestNoProto(t *testing.T) {
+	got, err := jsonify.String(&fakeMessage{Foo: "bar"})
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+	if expected := `{"foo":"bar"}`; got != expected {
+		t.Errorf("String() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,63 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// objectMembers splits a JSON object into its keys and raw values, keeping
+// the order of the input.
+func objectMembers(data []byte) ([]string, []json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("jsonify: expected a JSON object, got %s", data)
+	}
+	var keys []string
+	var values []json.RawMessage
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, tok.(string))
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+// appendObject appends a JSON object built from keys and values to b.
+// An empty value is written as null.
+func appendObject(b []byte, keys []string, values []json.RawMessage) []byte {
+	b = append(b, '{')
+	for i, key := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendString(b, key)
+		b = append(b, ':')
+		if len(values[i]) == 0 {
+			b = append(b, "null"...)
+		} else {
+			b = append(b, values[i]...)
+		}
+	}
+	return append(b, '}')
+}
+
+// appendString appends s as a JSON string to b, without escaping HTML.
+func appendString(b []byte, s string) []byte {
+	api := config()
+	stream := api.BorrowStream(nil)
+	defer api.ReturnStream(stream)
+	stream.WriteString(s)
+	return append(b, stream.Buffer()...)
+}
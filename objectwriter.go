@@ -0,0 +1,89 @@
+package jsonify
+
+import (
+	"fmt"
+	"io"
+)
+
+// ObjectWriter writes a JSON object to an io.Writer one member at a time,
+// for objects too large to hold in memory, e.g. exports of maps keyed by
+// ID:
+//
+//	ow := jsonify.NewObjectWriter(w)
+//	ow.RejectDuplicates = true
+//	ow.Begin()
+//	for id, user := range users {
+//		if err := ow.Field(id, user); err != nil {
+//			return err
+//		}
+//	}
+//	return ow.End()
+//
+// The output is buffered as for an [ArrayWriter]. An ObjectWriter is not
+// safe for concurrent use.
+type ObjectWriter struct {
+	// FlushBytes is the size of the buffered output from which it is
+	// written, 64 KiB if it is 0 or less.
+	FlushBytes int
+
+	// RejectDuplicates makes Field fail with a key it already wrote, which
+	// consumers would read inconsistently. The keys are kept in memory to
+	// detect them.
+	RejectDuplicates bool
+
+	c    containerWriter
+	keys map[string]struct{}
+}
+
+// NewObjectWriter returns an [ObjectWriter] writing to w, encoding the
+// values with [Bytes] and opts. Indentation options are ignored, and
+// members are written compactly.
+func NewObjectWriter(w io.Writer, opts ...Option) *ObjectWriter {
+	return &ObjectWriter{c: containerWriter{name: "ObjectWriter", w: w, opts: opts}}
+}
+
+// Begin writes the start of the object.
+func (ow *ObjectWriter) Begin() error {
+	return ow.c.begin('{', ow.FlushBytes)
+}
+
+// Field writes the member key with the value v. If v cannot be encoded, or
+// key is a duplicate rejected with RejectDuplicates, Field returns the error
+// and writes nothing, so that the object remains valid; errors writing to
+// the io.Writer are returned by every later call.
+func (ow *ObjectWriter) Field(key string, v any) error {
+	if err := ow.c.check("Field"); err != nil {
+		return err
+	}
+	if ow.RejectDuplicates {
+		if _, ok := ow.keys[key]; ok {
+			return fmt.Errorf("jsonify: duplicate key %q", key)
+		}
+	}
+	b, err := ow.c.encode(v)
+	if err != nil {
+		return err
+	}
+	if ow.RejectDuplicates {
+		if ow.keys == nil {
+			ow.keys = map[string]struct{}{}
+		}
+		ow.keys[key] = struct{}{}
+	}
+	return ow.c.add(appendString(nil, key), b, ow.FlushBytes)
+}
+
+// End writes the end of the object, and flushes the output.
+func (ow *ObjectWriter) End() error {
+	return ow.c.end('}')
+}
+
+// Len returns the number of members written.
+func (ow *ObjectWriter) Len() int {
+	return ow.c.n
+}
+
+// Flush is [ArrayWriter.Flush] for the members written so far.
+func (ow *ObjectWriter) Flush() error {
+	return ow.c.flush()
+}
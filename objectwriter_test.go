@@ -0,0 +1,92 @@
+package jsonify_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleObjectWriter() {
+	ow := jsonify.NewObjectWriter(os.Stdout)
+	ow.Begin()
+	ow.Field("u1", map[string]string{"name": "Ann"})
+	ow.Field("u2", map[string]string{"name": "Bob"})
+	ow.End()
+	// Output:
+	// {"u1":{"name":"Ann"},"u2":{"name":"Bob"}}
+}
+
+func TestObjectWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		duplicates bool
+		fields     []string
+		want       string
+		wantErrs   int
+	}{
+		{name: "empty", want: `{}`},
+		{name: "fields", fields: []string{"a", "<b>", "a"}, want: `{"a":0,"<b>":1,"a":2}`},
+		{name: "rejected duplicates", duplicates: true, fields: []string{"a", "b", "a"}, want: `{"a":0,"b":1}`, wantErrs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			ow := jsonify.NewObjectWriter(&sb)
+			ow.RejectDuplicates = tt.duplicates
+			if err := ow.Begin(); err != nil {
+				t.Fatal(err)
+			}
+			errs := 0
+			for i, key := range tt.fields {
+				if err := ow.Field(key, i); err != nil {
+					errs++
+				}
+			}
+			if err := ow.End(); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("ObjectWriter wrote %s, want %s", got, tt.want)
+			}
+			if errs != tt.wantErrs {
+				t.Errorf("Field() failed %d times, want %d", errs, tt.wantErrs)
+			}
+			if want := len(tt.fields) - tt.wantErrs; ow.Len() != want {
+				t.Errorf("Len() = %d, want %d", ow.Len(), want)
+			}
+		})
+	}
+}
+
+func TestObjectWriter_errors(t *testing.T) {
+	var sb strings.Builder
+	ow := jsonify.NewObjectWriter(&sb)
+	ow.RejectDuplicates = true
+	if err := ow.Field("a", 1); err == nil {
+		t.Error("Field() before Begin() error = nil, want error")
+	}
+	ow.Begin()
+	if err := ow.Field("a", math.Inf(1)); err == nil {
+		t.Error("Field(Inf) error = nil, want error")
+	}
+	if err := ow.Field("a", 1); err != nil {
+		t.Errorf("Field() after a failed Field() with the same key error = %v", err)
+	}
+	ow.End()
+	if got, want := sb.String(), `{"a":1}`; got != want {
+		t.Errorf("ObjectWriter wrote %s, want %s", got, want)
+	}
+
+	ow = jsonify.NewObjectWriter(errorWriter{})
+	ow.FlushBytes = 1
+	if err := ow.Begin(); !errors.Is(err, errWrite) {
+		t.Errorf("Begin() error = %v, want %v", err, errWrite)
+	}
+	if err := ow.End(); !errors.Is(err, errWrite) {
+		t.Errorf("End() after a write error = %v, want %v", err, errWrite)
+	}
+}
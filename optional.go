@@ -0,0 +1,97 @@
+package jsonify
+
+// Optional is a value of T that distinguishes three states, as needed for
+// PATCH semantics:
+//
+//   - absent: the zero Optional; the field was not given
+//   - null: the field was given as null, see [Null]
+//   - present: the field was given with a value, see [Some]
+//
+// An absent Optional struct field is omitted when tagged with omitempty (or
+// omitzero with encoding/json); otherwise it encodes as null. A null
+// Optional encodes as null.
+//
+// When decoding, a field missing from the input leaves the Optional absent,
+// null makes it null, and any other value makes it present.
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+type optionalState uint8
+
+const (
+	optionalAbsent optionalState = iota
+	optionalNull
+	optionalPresent
+)
+
+// Some returns a present Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, state: optionalPresent}
+}
+
+// Null returns a null Optional.
+func Null[T any]() Optional[T] {
+	return Optional[T]{state: optionalNull}
+}
+
+// IsAbsent reports whether o is absent.
+func (o Optional[T]) IsAbsent() bool {
+	return o.state == optionalAbsent
+}
+
+// IsNull reports whether o is null.
+func (o Optional[T]) IsNull() bool {
+	return o.state == optionalNull
+}
+
+// IsPresent reports whether o holds a value.
+func (o Optional[T]) IsPresent() bool {
+	return o.state == optionalPresent
+}
+
+// IsZero reports whether o is absent. It allows encoding/json to omit
+// absent fields tagged with omitzero.
+func (o Optional[T]) IsZero() bool {
+	return o.IsAbsent()
+}
+
+// Get returns the value of o and whether o is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.IsPresent()
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return o.marshalJSON(newOptions(nil))
+}
+
+func (o Optional[T]) marshalJSON(opts *options) ([]byte, error) {
+	if !o.IsPresent() {
+		return []byte("null"), nil
+	}
+	return opts.marshal(o.value)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := Decode(data, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+func (o Optional[T]) isAbsent() bool {
+	return o.IsAbsent()
+}
+
+type absenter interface {
+	isAbsent() bool
+}
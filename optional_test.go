@@ -0,0 +1,87 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type patch struct {
+	Name  jsonify.Optional[string] `json:"name,omitempty"`
+	Email jsonify.Optional[string] `json:"email,omitempty"`
+	Age   jsonify.Optional[int]    `json:"age,omitempty"`
+}
+
+func ExampleOptional() {
+	var p patch
+	jsonify.Decode([]byte(`{"name":"Ann","email":null}`), &p)
+	fmt.Println(p.Name.IsPresent(), p.Email.IsNull(), p.Age.IsAbsent())
+	fmt.Println(jsonify.MustString(p))
+	// Output:
+	// true true true
+	// {"name":"Ann","email":null}
+}
+
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "all absent", input: patch{}, expected: `{}`},
+		{name: "null", input: patch{Email: jsonify.Null[string]()}, expected: `{"email":null}`},
+		{name: "present", input: patch{Age: jsonify.Some(0)}, expected: `{"age":0}`},
+		{name: "pointer", input: &patch{Name: jsonify.Some("x")}, expected: `{"name":"x"}`},
+		{name: "without omitempty", input: struct{ V jsonify.Optional[int] }{}, expected: `{"V":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptionalDecode(t *testing.T) {
+	var p patch
+	if err := jsonify.Decode([]byte(`{"email":null,"age":42}`), &p); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !p.Name.IsAbsent() || !p.Email.IsNull() {
+		t.Errorf("Decode() = %+v", p)
+	}
+	if age, ok := p.Age.Get(); !ok || age != 42 {
+		t.Errorf("Age.Get() = %v, %v", age, ok)
+	}
+	if err := jsonify.Decode([]byte(`{"age":"x"}`), &p); err == nil {
+		t.Errorf("Decode() error = nil, want error")
+	}
+}
+
+func TestOptionalStdlib(t *testing.T) {
+	type stdPatch struct {
+		Name jsonify.Optional[string] `json:"name,omitzero"`
+		Age  jsonify.Optional[int]    `json:"age,omitzero"`
+	}
+	b, err := json.Marshal(stdPatch{Age: jsonify.Null[int]()})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if expected := `{"age":null}`; string(b) != expected {
+		t.Errorf("Marshal() = %s, want %s", b, expected)
+	}
+	var p stdPatch
+	if err := json.Unmarshal([]byte(`{"name":"x"}`), &p); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if name, ok := p.Name.Get(); !ok || name != "x" || !p.Age.IsAbsent() {
+		t.Errorf("Unmarshal() = %+v", p)
+	}
+}
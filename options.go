@@ -0,0 +1,259 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// Option configures an encoding call of [Bytes], [String] and their
+// variants.
+//
+// Options that change how types are encoded apply to the [Jsoniter] backend
+// only; other backends ignore them.
+type Option func(*options)
+
+type options struct {
+	mode mode
+
+	// format reformats the encoded output, e.g. to indent it.
+	format func(b []byte) ([]byte, error)
+
+	// indent is set by WithIndent, for Reformat, which indents as it reads.
+	indent *indentOptions
+
+	translate func(key string) string
+
+	// enumNumbers is set by WithEnumNumbers.
+	enumNumbers bool
+
+	proto protoOptions
+
+	// fingerprint is the key of WithFingerprint.
+	fingerprint string
+
+	// sortSeq2 is set by WithSortedSeq2.
+	sortSeq2 bool
+
+	// codec is set by WithCompression.
+	codec Codec
+
+	// trailingNewline is set by WithTrailingNewline.
+	trailingNewline bool
+
+	// Cycle detection state of the call.
+	depth   int
+	visited map[cycleKey]struct{}
+
+	// Output limit of the call, and the length of the output preceding the
+	// stream being encoded to.
+	maxOutput  int
+	outputBase int
+
+	// err is the error that stopped the call, which is reported as is:
+	// jsoniter prefixes the error of the stream with every enclosing field.
+	err error
+}
+
+// mode holds the options that change the jsoniter configuration. Each
+// distinct mode gets its own configuration, so it must be comparable.
+type mode struct {
+	lenient bool
+	nan     NaNPolicy
+	float   FloatFormat
+	int64   Int64Policy
+
+	audience       string
+	filterAudience bool
+	view           string
+	i18n           bool
+	limitOutput    bool
+	protoAsGo      bool
+	flatWrappers   bool
+	streaming      bool
+
+	// Options of Decode.
+	useNumber       bool
+	disallowUnknown bool
+	caseSensitive   bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// fail stops the encoding to stream with err, unless it has already failed.
+// o may be nil, for a stream without options.
+func (o *options) fail(stream *jsoniter.Stream, err error) {
+	if stream.Error == nil {
+		if o != nil {
+			o.err = err
+		}
+		stream.Error = err
+	}
+}
+
+// marshal encodes v without formatting it.
+func (o *options) marshal(v any) ([]byte, error) {
+	return o.marshalWith(backend, v)
+}
+
+// marshalWith is [options.marshal] with the given backend instead of the
+// one set by [SetBackend].
+func (o *options) marshalWith(backend Backend, v any) ([]byte, error) {
+	if v, ok := v.(json.RawMessage); ok {
+		return []byte(v), nil
+	}
+	if b, ok, err := marshalProto(v, o); ok {
+		return b, err
+	}
+	if o.mode.filterAudience || o.mode.view != "" {
+		// Backends ignoring options would encode the filtered fields.
+		backend = Jsoniter
+	}
+	if b, ok := backend.(optionsBackend); ok {
+		return b.marshalOptions(v, o)
+	}
+	return backend.Marshal(v)
+}
+
+// modeExtension is the jsoniter extension applying a mode. It is registered
+// after the extension carrying the registry, so registered encoders win.
+type modeExtension struct {
+	jsoniter.DummyExtension
+	mode mode
+	ext  *extension
+}
+
+func (ext *modeExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if !ext.mode.protoAsGo {
+		if enc := protoEncoderOf(typ); enc != nil {
+			return enc
+		}
+	}
+	if ext.mode.streaming {
+		if enc := streamMapEncoderOf(typ); enc != nil {
+			return enc
+		}
+	}
+	if ext.mode.flatWrappers {
+		if enc := wrapperEncoderOf(typ); enc != nil {
+			return enc
+		}
+	}
+	if ext.mode.lenient {
+		if enc := lenientEncoderOf(typ); enc != nil {
+			return enc
+		}
+	}
+	if enc := floatEncoderOf(typ, ext.mode); enc != nil {
+		return enc
+	}
+	if enc := int64EncoderOf(typ, ext.mode.int64); enc != nil {
+		return enc
+	}
+	return nil
+}
+
+func (ext *modeExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	return protoDecoderOf(typ, ext.mode)
+}
+
+func (ext *modeExtension) DecorateEncoder(typ reflect2.Type, enc jsoniter.ValEncoder) jsoniter.ValEncoder {
+	if ext.mode.limitOutput {
+		enc = decorateLimit(typ, enc)
+	}
+	if ext.mode.streaming {
+		enc = &flushEncoder{enc}
+	}
+	return enc
+}
+
+func (ext *modeExtension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
+	if ext.mode.filterAudience {
+		filterAudience(desc, ext.mode.audience)
+	}
+	if ext.mode.view != "" {
+		filterView(desc, ext.mode.view, ext.ext.views[desc.Type.Type1()])
+	}
+	if ext.mode.i18n {
+		translateFields(desc)
+	}
+}
+
+// optionsMarshaler is implemented by the types wrapping other values, such
+// as [Optional] and [Set], to encode the values they wrap with the options
+// of the call encoding them, e.g. to filter them with [ForAudience], rather
+// than with the default ones of their MarshalJSON method.
+type optionsMarshaler interface {
+	json.Marshaler
+	marshalJSON(o *options) ([]byte, error)
+}
+
+var optionsMarshalerType = reflect2.TypeOfPtr((*optionsMarshaler)(nil)).Elem()
+
+func init() {
+	encoderFactories = append(encoderFactories, optionsEncoderOf)
+}
+
+// optionsEncoderOf returns an encoder of the [optionsMarshaler] typ, or of a
+// pointer to one, passing it the options of the stream.
+func optionsEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	if !typ.Implements(optionsMarshalerType) {
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Interface:
+		return nil
+	case reflect.Pointer:
+		elem := typ.(reflect2.PtrType).Elem()
+		if elem.Kind() == reflect.Pointer || !elem.Implements(optionsMarshalerType) {
+			return nil
+		}
+		return &jsoniter.OptionalEncoder{ValueEncoder: &optionsEncoder{elem}}
+	}
+	return &optionsEncoder{typ}
+}
+
+type optionsEncoder struct {
+	typ reflect2.Type
+}
+
+// IsEmpty reports absent Optional values and empty slices and maps as
+// empty, so that jsoniter omits them with omitempty.
+func (e *optionsEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	v := e.typ.UnsafeIndirect(ptr)
+	if v, ok := v.(absenter); ok {
+		return v.isAbsent()
+	}
+	switch e.typ.Kind() {
+	case reflect.Slice, reflect.Map:
+		return reflect.ValueOf(v).Len() == 0
+	}
+	return false
+}
+
+func (e *optionsEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	o, _ := stream.Attachment.(*options)
+	if o == nil {
+		o = newOptions(nil)
+	}
+	// The wrapped values are encoded in separate streams, whose output is
+	// appended to this one, as for sorted maps.
+	base := o.outputBase
+	o.outputBase += stream.Buffered()
+	b, err := e.typ.UnsafeIndirect(ptr).(optionsMarshaler).marshalJSON(o)
+	o.outputBase = base
+	if err != nil {
+		o.fail(stream, err)
+		return
+	}
+	stream.Write(b)
+}
@@ -0,0 +1,380 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchChange is a change that a patch makes to a document.
+type PatchChange struct {
+	// Op is "add", "remove", "replace", "move" or "copy".
+	Op string
+
+	// Path is the JSON Pointer of the changed value, and From the JSON
+	// Pointer of the source of a move or copy.
+	Path string
+	From string
+
+	// Old is the value before the change, or nil if the change adds a
+	// value; New is the value after the change, or nil if it removes one.
+	Old, New []byte
+}
+
+// String returns the change in a human-readable form, e.g.
+// "replace /port: 8080 → 9090".
+func (c PatchChange) String() string {
+	switch c.Op {
+	case "add":
+		return fmt.Sprintf("add %s: %s", c.Path, c.New)
+	case "remove":
+		return fmt.Sprintf("remove %s (was %s)", c.Path, c.Old)
+	case "move", "copy":
+		if c.Old != nil {
+			return fmt.Sprintf("%s %s → %s: %s → %s", c.Op, c.From, c.Path, c.Old, c.New)
+		}
+		return fmt.Sprintf("%s %s → %s: %s", c.Op, c.From, c.Path, c.New)
+	}
+	return fmt.Sprintf("%s %s: %s → %s", c.Op, c.Path, c.Old, c.New)
+}
+
+// PatchSummary is the list of changes that a patch makes, in order.
+type PatchSummary []PatchChange
+
+// String returns the changes one per line.
+func (s PatchSummary) String() string {
+	lines := make([]string, len(s))
+	for i, c := range s {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExplainPatch reports what patch would change in doc, without changing
+// it, e.g. for a confirmation step before applying a configuration change.
+//
+// patch is a JSON Patch (RFC 6902) if it is an array, and a JSON Merge Patch
+// (RFC 7396) if it is an object. A merge patch replaces a doc that is not
+// an object, which is reported as a single replacement of the root.
+// ExplainPatch fails if the patch cannot be
+// applied to doc, including if a "test" operation fails. Changes that leave
+// a value as it was are not reported.
+func ExplainPatch(doc, patch []byte) (PatchSummary, error) {
+	var root, p any
+	if err := Decode(doc, &root, UseNumber()); err != nil {
+		return nil, err
+	}
+	if err := Decode(patch, &p, UseNumber()); err != nil {
+		return nil, err
+	}
+	var s PatchSummary
+	switch p := p.(type) {
+	case []any:
+		for i, op := range p {
+			var err error
+			if root, err = s.explainOp(root, op); err != nil {
+				return nil, fmt.Errorf("jsonify: patch operation %d: %w", i, err)
+			}
+		}
+	case map[string]any:
+		if _, ok := root.(map[string]any); !ok {
+			// A merge patch replaces a document that is not an object.
+			s.explainReplace(nil, root, mergeValue(p))
+			break
+		}
+		s.explainMerge(root, p, nil)
+	default:
+		return nil, fmt.Errorf("jsonify: patch must be an array or an object")
+	}
+	return s, nil
+}
+
+// explainOp records the changes of the JSON Patch operation op and returns
+// the patched document.
+func (s *PatchSummary) explainOp(root, op any) (any, error) {
+	var o struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+		From string `json:"from"`
+	}
+	if err := Decode(MustBytes(op), &o, UseNumber()); err != nil {
+		return nil, err
+	}
+	// The value is looked up in op, where a null value is not a missing one.
+	members, _ := op.(map[string]any)
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	switch o.Op {
+	case "add", "replace", "test":
+		v, ok := members["value"]
+		if !ok {
+			return nil, fmt.Errorf("%s requires a value", o.Op)
+		}
+		value = v
+	case "move", "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = pointerGet(root, from); err != nil {
+			return nil, err
+		}
+		if o.Op == "move" {
+			if o.Path == o.From {
+				// Moving a value onto itself changes nothing.
+				return root, nil
+			}
+			if strings.HasPrefix(o.Path+"/", o.From+"/") && o.Path != o.From {
+				return nil, fmt.Errorf("cannot move %q into itself", o.From)
+			}
+			if root, _, err = pointerUpdate(root, from, nil, "remove"); err != nil {
+				return nil, err
+			}
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown operation %q", o.Op)
+	}
+	if o.Op == "test" {
+		old, err := pointerGet(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(old, value) {
+			return nil, fmt.Errorf("test failed at %q", o.Path)
+		}
+		return root, nil
+	}
+	update := o.Op
+	if update == "move" || update == "copy" {
+		update = "add"
+	}
+	if n := len(path); update == "add" && n > 0 && path[n-1] == "-" {
+		// Report the index that "-" refers to.
+		if a, err := pointerGet(root, path[:n-1]); err == nil {
+			if a, ok := a.([]any); ok {
+				path[n-1] = strconv.Itoa(len(a))
+				o.Path = formatPointer(path)
+			}
+		}
+	}
+	root, old, err := pointerUpdate(root, path, value, update)
+	if err != nil {
+		return nil, err
+	}
+	c := PatchChange{Op: o.Op, Path: o.Path, Old: old}
+	if o.Op == "move" || o.Op == "copy" {
+		c.From = o.From
+	}
+	if o.Op != "remove" {
+		c.New = MustBytes(value)
+	}
+	if c.Op == "add" && c.Old != nil {
+		c.Op = "replace"
+	}
+	if c.Op != "replace" || !bytes.Equal(c.Old, c.New) {
+		*s = append(*s, c)
+	}
+	return root, nil
+}
+
+// explainMerge records the changes of the merge patch p to the object at
+// path, which is target if it is an object.
+func (s *PatchSummary) explainMerge(target any, p map[string]any, path []string) {
+	obj, _ := target.(map[string]any)
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := p[key]
+		child := append(path[:len(path):len(path)], key)
+		old, exists := obj[key]
+		if value == nil {
+			if exists {
+				*s = append(*s, PatchChange{Op: "remove", Path: formatPointer(child), Old: MustBytes(old)})
+			}
+			continue
+		}
+		if v, ok := value.(map[string]any); ok {
+			if _, ok := old.(map[string]any); ok {
+				s.explainMerge(old, v, child)
+				continue
+			}
+			// A merge patch object replacing a non-object drops its nulls.
+			value = mergeValue(v)
+		}
+		if exists {
+			s.explainReplace(child, old, value)
+		} else {
+			*s = append(*s, PatchChange{Op: "add", Path: formatPointer(child), New: MustBytes(value)})
+		}
+	}
+}
+
+// explainReplace records the replacement of old at path with value, unless
+// they are equal.
+func (s *PatchSummary) explainReplace(path []string, old, value any) {
+	if !jsonEqual(old, value) {
+		*s = append(*s, PatchChange{Op: "replace", Path: formatPointer(path), Old: MustBytes(old), New: MustBytes(value)})
+	}
+}
+
+// mergeValue returns the merge patch p applied to an empty object.
+func mergeValue(p map[string]any) map[string]any {
+	out := make(map[string]any, len(p))
+	for key, value := range p {
+		switch v := value.(type) {
+		case nil:
+		case map[string]any:
+			out[key] = mergeValue(v)
+		default:
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// jsonEqual reports whether the decoded JSON values a and b are equal, as
+// the "test" operation compares them: numbers by their values, e.g. 1 and
+// 1.0 are equal, and objects regardless of the order of their members.
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && numberEqual(a, b)
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, v := range a {
+			w, ok := b[key]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// numberEqual reports whether a and b are the same number, comparing them
+// as integers if both are, and as floats otherwise.
+func numberEqual(a, b json.Number) bool {
+	if i, err := a.Int64(); err == nil {
+		if j, err := b.Int64(); err == nil {
+			return i == j
+		}
+	}
+	x, errA := a.Float64()
+	y, errB := b.Float64()
+	return errA == nil && errB == nil && x == y
+}
+
+// arrayIndex returns the array index of the JSON Pointer token, which must
+// be a non-negative integer without leading zeros.
+func arrayIndex(token string) (int, bool) {
+	k, err := strconv.Atoi(token)
+	return k, err == nil && k >= 0 && token == strconv.Itoa(k)
+}
+
+func pointerGet(root any, path []string) (any, error) {
+	v := root
+	for i, token := range path {
+		switch c := v.(type) {
+		case map[string]any:
+			child, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", formatPointer(path[:i+1]))
+			}
+			v = child
+		case []any:
+			k, ok := arrayIndex(token)
+			if !ok || k >= len(c) {
+				return nil, fmt.Errorf("path %q not found", formatPointer(path[:i+1]))
+			}
+			v = c[k]
+		default:
+			return nil, fmt.Errorf("path %q not found", formatPointer(path[:i+1]))
+		}
+	}
+	return v, nil
+}
+
+// pointerUpdate applies the JSON Patch operation op, "add", "remove" or
+// "replace", to the value at path in root. It returns the updated root and
+// the encoded previous value at path, if any.
+func pointerUpdate(root any, path []string, value any, op string) (any, []byte, error) {
+	if len(path) == 0 {
+		if op == "remove" {
+			return nil, nil, fmt.Errorf("cannot remove the document root")
+		}
+		return value, MustBytes(root), nil
+	}
+	parent, err := pointerGet(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	key := path[len(path)-1]
+	var old []byte
+	switch c := parent.(type) {
+	case map[string]any:
+		v, ok := c[key]
+		if !ok && op != "add" {
+			return nil, nil, fmt.Errorf("path %q not found", formatPointer(path))
+		}
+		if ok {
+			old = MustBytes(v)
+		}
+		if op == "remove" {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return root, old, nil
+	case []any:
+		k, ok := arrayIndex(key)
+		if key == "-" && op == "add" {
+			k, ok = len(c), true
+		}
+		limit := len(c)
+		if op == "add" {
+			limit++
+		}
+		if !ok || k >= limit {
+			return nil, nil, fmt.Errorf("invalid array index %q", key)
+		}
+		switch op {
+		case "add":
+			c = append(c[:k], append([]any{value}, c[k:]...)...)
+		case "remove":
+			old = MustBytes(c[k])
+			c = append(c[:k], c[k+1:]...)
+		default:
+			old = MustBytes(c[k])
+			c[k] = value
+		}
+		root, _, err := pointerUpdate(root, path[:len(path)-1], c, "replace")
+		return root, old, err
+	}
+	return nil, nil, fmt.Errorf("path %q not found", formatPointer(path[:len(path)-1]))
+}
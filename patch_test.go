@@ -0,0 +1,78 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleExplainPatch() {
+	doc := []byte(`{"name":"api","port":8080,"tags":["a"],"debug":true}`)
+	patch := []byte(`[
+		{"op":"replace","path":"/port","value":9090},
+		{"op":"add","path":"/tags/-","value":"b"},
+		{"op":"remove","path":"/debug"}
+	]`)
+	summary, err := jsonify.ExplainPatch(doc, patch)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(summary)
+	// Output:
+	// replace /port: 8080 → 9090
+	// add /tags/1: "b"
+	// remove /debug (was true)
+}
+
+func TestExplainPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string // default {"a":1,"b":{"c":[1,2]},"d":"x"}
+		patch    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "add member", patch: `[{"op":"add","path":"/e","value":{"f":null}}]`, expected: `add /e: {"f":null}`},
+		{name: "add existing", patch: `[{"op":"add","path":"/a","value":2}]`, expected: `replace /a: 1 → 2`},
+		{name: "insert", patch: `[{"op":"add","path":"/b/c/0","value":0}]`, expected: `add /b/c/0: 0`},
+		{name: "unchanged", patch: `[{"op":"replace","path":"/a","value":1}]`, expected: ``},
+		{name: "move", patch: `[{"op":"move","from":"/a","path":"/b/a"}]`, expected: `move /a → /b/a: 1`},
+		{name: "copy over", patch: `[{"op":"copy","from":"/a","path":"/d"}]`, expected: `copy /a → /d: "x" → 1`},
+		{name: "sequence", patch: `[{"op":"remove","path":"/b/c/0"},{"op":"replace","path":"/b/c/0","value":3}]`, expected: "remove /b/c/0 (was 1)\nreplace /b/c/0: 2 → 3"},
+		{name: "test ok", patch: `[{"op":"test","path":"/b","value":{"c":[1,2]}}]`, expected: ``},
+		{name: "test failed", patch: `[{"op":"test","path":"/a","value":2}]`, wantErr: true},
+		{name: "missing path", patch: `[{"op":"replace","path":"/z","value":2}]`, wantErr: true},
+		{name: "bad index", patch: `[{"op":"add","path":"/b/c/5","value":2}]`, wantErr: true},
+		{name: "move into itself", patch: `[{"op":"move","from":"/b","path":"/b/x"}]`, wantErr: true},
+		{name: "unknown op", patch: `[{"op":"frob","path":"/a"}]`, wantErr: true},
+		{name: "merge", patch: `{"a":null,"b":{"c":{"x":null,"y":1}},"d":"x","e":2,"z":null}`, expected: "remove /a (was 1)\nreplace /b/c: [1,2] → {\"y\":1}\nadd /e: 2"},
+		{name: "scalar patch", patch: `1`, wantErr: true},
+		{name: "add null", patch: `[{"op":"add","path":"/e","value":null}]`, expected: `add /e: null`},
+		{name: "replace with null", patch: `[{"op":"replace","path":"/a","value":null}]`, expected: `replace /a: 1 → null`},
+		{name: "missing value", patch: `[{"op":"add","path":"/e"}]`, wantErr: true},
+		{name: "test number", patch: `[{"op":"test","path":"/a","value":1.0},{"op":"test","path":"/b","value":{"c":[1e0,2.00]}}]`, expected: ``},
+		{name: "test null", patch: `[{"op":"test","path":"/a","value":null}]`, wantErr: true},
+		{name: "move onto itself", patch: `[{"op":"move","from":"/b","path":"/b"}]`, expected: ``},
+		{name: "leading zero", patch: `[{"op":"test","path":"/b/c/01","value":2}]`, wantErr: true},
+		{name: "leading zero update", patch: `[{"op":"replace","path":"/b/c/01","value":3}]`, wantErr: true},
+		{name: "leading zero insert", patch: `[{"op":"add","path":"/b/c/00","value":3}]`, wantErr: true},
+		{name: "merge array doc", doc: `[1,2]`, patch: `{"a":1,"b":null}`, expected: `replace : [1,2] → {"a":1}`},
+		{name: "merge scalar doc", doc: `"x"`, patch: `{"a":{"b":1}}`, expected: `replace : "x" → {"a":{"b":1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := tt.doc
+			if doc == "" {
+				doc = `{"a":1,"b":{"c":[1,2]},"d":"x"}`
+			}
+			got, err := jsonify.ExplainPatch([]byte(doc), []byte(tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExplainPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.expected {
+				t.Errorf("ExplainPatch() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// protoOptions holds the protojson options of an encoding call.
+type protoOptions struct {
+	marshal  protojson.MarshalOptions
+	resolver protoResolver
+	formats  protoFormats
+
+	// enumNames is set by WithProtoEnumNames.
+	enumNames bool
+}
+
+// protoFormats holds how the protojson encoding of messages is reformatted.
+type protoFormats struct {
+	times      timeFormats
+	unknownKey string
+	oneofs     bool
+}
+
+// protoResolver is the type of [protojson.MarshalOptions.Resolver].
+type protoResolver interface {
+	protoregistry.ExtensionTypeResolver
+	protoregistry.MessageTypeResolver
+}
+
+// protoDecodeOptions holds the protojson options of a decoding call.
+type protoDecodeOptions struct {
+	resolver protoResolver
+}
+
+// protoMarshalOptions returns the protojson options of o, with the resolver
+// of [WithProtoResolver] if any, and enums as numbers with [WithEnumNumbers].
+func (o *options) protoMarshalOptions() protojson.MarshalOptions {
+	opts := o.proto.marshal
+	if o.proto.resolver != nil {
+		opts.Resolver = fallbackResolver{o.proto.resolver}
+	}
+	if o.enumNumbers {
+		opts.UseEnumNumbers = true
+	}
+	return opts
+}
+
+// WithProtoOptions returns an [Option] that marshals [proto.Message] values
+// with opts instead of the defaults of [protojson.Marshal], e.g. to keep the
+// field names of the .proto file with UseProtoNames, or to emit fields with
+// their zero values with EmitUnpopulated:
+//
+//	jsonify.Bytes(msg, jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true}))
+//
+// Use [New] to apply the same options to every call of an [Encoder].
+// WithProtoOptions is not available with the jsonify_noproto build tag.
+func WithProtoOptions(opts protojson.MarshalOptions) Option {
+	return func(o *options) {
+		o.proto.marshal = opts
+	}
+}
+
+// WithProtoAsGo returns an [Option] that encodes [proto.Message] values like
+// other Go structs, with the names and tags of the fields of the generated
+// structs, instead of with protojson, e.g. for debugging:
+//
+//	jsonify.String(&pb.User{DisplayName: "Ann"}, jsonify.WithProtoAsGo())
+//	// {"display_name":"Ann"}
+//
+// It is how messages are encoded with the jsonify_noproto build tag.
+func WithProtoAsGo() Option {
+	return func(o *options) {
+		o.mode.protoAsGo = true
+	}
+}
+
+// WithProtoResolver returns an [Option] that resolves the types of
+// google.protobuf.Any messages with r, e.g. a [protoregistry.Types] of
+// private messages, instead of [protoregistry.GlobalTypes], so that they are
+// expanded with their "@type" as protojson does.
+//
+// Unlike protojson, which fails on an Any of an unknown type, an Any whose
+// type r does not know is encoded with its value in base64:
+//
+//	{"@type":"type.googleapis.com/example.Unknown","value":"CgNmb28="}
+//
+// The resolver takes precedence over the Resolver of [WithProtoOptions].
+// WithProtoResolver is not available with the jsonify_noproto build tag.
+func WithProtoResolver(r interface {
+	protoregistry.ExtensionTypeResolver
+	protoregistry.MessageTypeResolver
+}) Option {
+	return func(o *options) {
+		o.proto.resolver = r
+	}
+}
+
+// marshalProto marshals v with [protojson] if v is a [proto.Message] or a
+// [protoreflect.Message]. It reports false if v is neither.
+func marshalProto(v any, o *options) ([]byte, bool, error) {
+	if o.mode.protoAsGo {
+		return nil, false, nil
+	}
+	m, ok := asMessage(v)
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.ProtoReflect().IsValid() {
+		// A typed nil, e.g. a nil *pb.User in an interface, is encoded as
+		// a nil pointer to a struct is.
+		return []byte("null"), true, nil
+	}
+	// The message is indented along with other values, by the format of
+	// the output, e.g. of WithIndent.
+	b, err := marshalMessage(m, o.protoMarshalOptions(), o.proto.formats)
+	return b, true, err
+}
+
+// marshalMessage marshals m with opts, and reformats its encoding with f.
+//
+// protojson deliberately varies the whitespace of its output between builds,
+// so the output is compacted, and reindented if opts ask for multiline
+// output, to make it reproducible.
+func marshalMessage(m proto.Message, opts protojson.MarshalOptions, f protoFormats) ([]byte, error) {
+	if b, ok, err := marshalStructpb(m); ok {
+		if err != nil {
+			return nil, err
+		}
+		return indentMessage(b, opts)
+	}
+	if r, ok := opts.Resolver.(fallbackResolver); ok {
+		c := proto.Clone(m)
+		changed, err := wrapUnknownAnys(c.ProtoReflect(), r.protoResolver)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			m = c
+		}
+	}
+	b, err := opts.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = Compact(b); err != nil {
+		return nil, err
+	}
+	if f.unknownKey != "" && hasUnknownFields(m.ProtoReflect()) {
+		if b, err = addUnknownFields(b, m.ProtoReflect(), f.unknownKey); err != nil {
+			return nil, err
+		}
+	}
+	if f.oneofs {
+		if b, err = addOneofDiscriminators(b, m.ProtoReflect(), opts.UseProtoNames); err != nil {
+			return nil, err
+		}
+	}
+	if f.times != (timeFormats{}) {
+		if b, err = f.times.format(b, m.ProtoReflect().Descriptor()); err != nil {
+			return nil, err
+		}
+	}
+	return indentMessage(b, opts)
+}
+
+// indentMessage indents b, a compact encoded message, if opts ask for
+// multiline output.
+func indentMessage(b []byte, opts protojson.MarshalOptions) ([]byte, error) {
+	if !opts.Multiline && opts.Indent == "" {
+		return b, nil
+	}
+	indent := opts.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var bytesValueType = (*wrapperspb.BytesValue)(nil).ProtoReflect().Type()
+
+// fallbackResolver resolves the types unknown to its resolver as
+// google.protobuf.BytesValue, which protojson encodes in an Any as
+// {"@type":...,"value":"base64"}. The values of the Any messages of those
+// types are wrapped by [wrapUnknownAnys] to match.
+type fallbackResolver struct {
+	protoResolver
+}
+
+func (r fallbackResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := r.protoResolver.FindMessageByURL(url)
+	if errors.Is(err, protoregistry.NotFound) {
+		return bytesValueType, nil
+	}
+	return mt, err
+}
+
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
+
+// wrapUnknownAnys replaces the value of every Any in m whose type r does not
+// know with a BytesValue holding it, including in the values of the Any
+// messages of known types. It reports whether it changed m.
+func wrapUnknownAnys(m protoreflect.Message, r protoResolver) (bool, error) {
+	if m.Descriptor().FullName() == anyFullName {
+		return wrapUnknownAny(m, r)
+	}
+	changed := false
+	var err error
+	walk := func(m protoreflect.Message) bool {
+		var c bool
+		c, err = wrapUnknownAnys(m, r)
+		changed = changed || c
+		return err == nil
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			list := v.List()
+			for i := range list.Len() {
+				if !walk(list.Get(i).Message()) {
+					return false
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				return walk(v.Message())
+			})
+		case fd.Message() != nil:
+			walk(v.Message())
+		}
+		return err == nil
+	})
+	return changed, err
+}
+
+func wrapUnknownAny(m protoreflect.Message, r protoResolver) (bool, error) {
+	fields := m.Descriptor().Fields()
+	typeURL := m.Get(fields.ByNumber(1)).String()
+	value := fields.ByNumber(2)
+	if typeURL == "" {
+		return false, nil
+	}
+	mt, err := r.FindMessageByURL(typeURL)
+	if errors.Is(err, protoregistry.NotFound) {
+		b, err := proto.Marshal(wrapperspb.Bytes(m.Get(value).Bytes()))
+		if err != nil {
+			return false, err
+		}
+		m.Set(value, protoreflect.ValueOfBytes(b))
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	em := mt.New()
+	opts := proto.UnmarshalOptions{AllowPartial: true, Resolver: r}
+	if err := opts.Unmarshal(m.Get(value).Bytes(), em.Interface()); err != nil {
+		// protojson reports the error.
+		return false, nil
+	}
+	changed, err := wrapUnknownAnys(em, r)
+	if !changed || err != nil {
+		return false, err
+	}
+	b, err := proto.MarshalOptions{AllowPartial: true, Deterministic: true}.Marshal(em.Interface())
+	if err != nil {
+		return false, err
+	}
+	m.Set(value, protoreflect.ValueOfBytes(b))
+	return true, nil
+}
+
+// unmarshalProto unmarshals data into v with [protojson] if v is a
+// [proto.Message] or a [protoreflect.Message]. It reports false if v is
+// neither.
+func unmarshalProto(data []byte, v any, o *decodeOptions) (bool, error) {
+	m, ok := asMessage(v)
+	if !ok {
+		return false, nil
+	}
+	if !m.ProtoReflect().IsValid() {
+		return true, fmt.Errorf("jsonify: Decode into nil %T", v)
+	}
+	opts := protojson.UnmarshalOptions{DiscardUnknown: !o.mode.disallowUnknown}
+	if o.proto.resolver != nil {
+		opts.Resolver = o.proto.resolver
+	}
+	return true, opts.Unmarshal(data, m)
+}
+
+// asMessage returns v as a [proto.Message], unwrapping a
+// [protoreflect.Message], so that reflection-driven code can pass either.
+func asMessage(v any) (proto.Message, bool) {
+	switch v := v.(type) {
+	case proto.Message:
+		return v, true
+	case protoreflect.Message:
+		return v.Interface(), true
+	}
+	return nil, false
+}
+
+// isProtoValue reports whether v is a protobuf message, which is decoded
+// with protojson.
+func isProtoValue(v any) bool {
+	_, ok := asMessage(v)
+	return ok
+}
+
+var (
+	protoMessageType        = reflect2.TypeOfPtr((*proto.Message)(nil)).Elem()
+	protoreflectMessageType = reflect2.TypeOfPtr((*protoreflect.Message)(nil)).Elem()
+)
+
+// isMessage reports whether typ is a [proto.Message] or a
+// [protoreflect.Message], and whether it is one by its pointer type only, as
+// a struct field of a message type rather than a pointer to it.
+func isMessage(typ reflect2.Type) (ok, byPointer bool) {
+	if typ.Kind() == reflect.Interface {
+		return false, false
+	}
+	if typ.Implements(protoMessageType) || typ.Implements(protoreflectMessageType) {
+		return true, false
+	}
+	if typ.Kind() != reflect.Pointer {
+		ptr := reflect2.PtrTo(typ)
+		if ptr.Implements(protoMessageType) || ptr.Implements(protoreflectMessageType) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// protoEncoderOf returns an encoder that marshals values of typ with
+// protojson if typ is a [proto.Message], so that messages nested in other
+// values are encoded as they are at the top level.
+func protoEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	if ok, byPointer := isMessage(typ); ok {
+		return &protoEncoder{typ: typ, byPointer: byPointer}
+	}
+	if isEnum(typ) {
+		return &protoEnumCodec{typ: typ}
+	}
+	return nil
+}
+
+type protoEncoder struct {
+	typ       reflect2.Type
+	byPointer bool
+}
+
+func (e *protoEncoder) message(ptr unsafe.Pointer) proto.Message {
+	var v any
+	if !e.byPointer {
+		v = e.typ.UnsafeIndirect(ptr)
+	} else {
+		// The value may live in read-only memory, and protobuf initializes
+		// the internal state of a message lazily, so a copy is marshaled.
+		c := e.typ.UnsafeNew()
+		e.typ.UnsafeSet(c, ptr)
+		v = reflect2.PtrTo(e.typ).UnsafeIndirect(unsafe.Pointer(&c))
+	}
+	m, _ := asMessage(v)
+	return m
+}
+
+func (e *protoEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if e.typ.Kind() == reflect.Pointer && *(*unsafe.Pointer)(ptr) == nil {
+		stream.WriteNil()
+		return
+	}
+	var opts protojson.MarshalOptions
+	var formats protoFormats
+	o, _ := stream.Attachment.(*options)
+	if o != nil {
+		// A nested message is indented along with the whole output.
+		opts = o.protoMarshalOptions()
+		opts.Multiline = false
+		opts.Indent = ""
+		formats = o.proto.formats
+	}
+	b, err := marshalMessage(e.message(ptr), opts, formats)
+	if err != nil {
+		if o != nil {
+			o.fail(stream, err)
+		} else if stream.Error == nil {
+			stream.Error = err
+		}
+		return
+	}
+	stream.Write(b)
+}
+
+func (e *protoEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.typ.Kind() == reflect.Pointer && *(*unsafe.Pointer)(ptr) == nil
+}
+
+// protoDecoderOf returns a decoder that unmarshals values of typ with
+// protojson if typ is a [proto.Message]. A [protoreflect.Message] that is not
+// one is a view of a message that cannot be allocated from its type alone.
+func protoDecoderOf(typ reflect2.Type, m mode) jsoniter.ValDecoder {
+	if ok, byPointer := isMessage(typ); ok && (typ.Implements(protoMessageType) || reflect2.PtrTo(typ).Implements(protoMessageType)) {
+		opts := protojson.UnmarshalOptions{DiscardUnknown: !m.disallowUnknown}
+		return &protoDecoder{typ: typ, byPointer: byPointer, opts: opts}
+	}
+	if isEnum(typ) {
+		return &protoEnumCodec{typ: typ}
+	}
+	return nil
+}
+
+type protoDecoder struct {
+	typ       reflect2.Type
+	byPointer bool
+	opts      protojson.UnmarshalOptions
+}
+
+func (d *protoDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if iter.ReadNil() {
+		if d.typ.Kind() == reflect.Pointer {
+			*(*unsafe.Pointer)(ptr) = nil
+		}
+		return
+	}
+	data := iter.SkipAndReturnBytes()
+	if iter.Error != nil {
+		return
+	}
+	var v any
+	if d.byPointer {
+		v = reflect2.PtrTo(d.typ).UnsafeIndirect(unsafe.Pointer(&ptr))
+	} else {
+		if *(*unsafe.Pointer)(ptr) == nil {
+			*(*unsafe.Pointer)(ptr) = d.typ.(reflect2.PtrType).Elem().UnsafeNew()
+		}
+		v = d.typ.UnsafeIndirect(ptr)
+	}
+	m, _ := asMessage(v)
+	if err := d.opts.Unmarshal(data, m); err != nil {
+		iter.ReportError("protojson", err.Error())
+	}
+}
@@ -0,0 +1,538 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestProtobufMessage(t *testing.T) {
+	pbMsg, err := structpb.NewStruct(map[string]any{
+		"foo": "bar",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	t.Run("Bytes with protobuf", func(t *testing.T) {
+		got, err := jsonify.Bytes(pbMsg)
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		expected := []byte(`{"foo":"bar"}`)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Bytes() = %s, want %s", got, expected)
+		}
+	})
+
+	t.Run("String with protobuf", func(t *testing.T) {
+		got, err := jsonify.String(pbMsg)
+		if err != nil {
+			t.Fatalf("String() error = %v", err)
+		}
+		expected := `{"foo":"bar"}`
+		if got != expected {
+			t.Errorf("String() = %v, want %v", got, expected)
+		}
+	})
+}
+
+func ExampleDecode_protobuf() {
+	in := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(1)}
+	b := jsonify.MustBytes(in)
+
+	var out descriptorpb.FieldDescriptorProto
+	err := jsonify.Decode(b, &out)
+	fmt.Println(proto.Equal(in, &out), err)
+
+	err = jsonify.Decode([]byte(`{"name":"id","bogus":1}`), &out, jsonify.WithDisallowUnknownFields())
+	fmt.Println(err != nil)
+	// Output:
+	// true <nil>
+	// true
+}
+
+func TestDecodeProtobufMessage(t *testing.T) {
+	data := []byte(`{"name":"id","number":1,"bogus":true}`)
+
+	var m descriptorpb.FieldDescriptorProto
+	if err := jsonify.Decode(data, &m); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if m.GetName() != "id" || m.GetNumber() != 1 {
+		t.Errorf("Decode() = %v", &m)
+	}
+	if err := jsonify.Decode(data, &m, jsonify.WithDisallowUnknownFields()); err == nil {
+		t.Errorf("Decode() with WithDisallowUnknownFields() error = nil, want error")
+	}
+}
+
+func TestWithProtoOptions(t *testing.T) {
+	m := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		TypeName: proto.String(".pkg.ID"),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+	}
+	tests := []struct {
+		name     string
+		opts     []jsonify.Option
+		expected string
+	}{
+		{name: "default", expected: `{"name":"id","type":"TYPE_MESSAGE","typeName":".pkg.ID"}`},
+		{
+			name:     "proto names",
+			opts:     []jsonify.Option{jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true})},
+			expected: `{"name":"id","type":"TYPE_MESSAGE","type_name":".pkg.ID"}`,
+		},
+		{
+			name:     "enum numbers",
+			opts:     []jsonify.Option{jsonify.WithProtoOptions(protojson.MarshalOptions{UseEnumNumbers: true})},
+			expected: `{"name":"id","type":11,"typeName":".pkg.ID"}`,
+		},
+		{
+			name:     "indent",
+			opts:     []jsonify.Option{jsonify.WithIndent("", " ")},
+			expected: "{\n \"name\": \"id\",\n \"type\": \"TYPE_MESSAGE\",\n \"typeName\": \".pkg.ID\"\n}",
+		},
+		{
+			name:     "multiline",
+			opts:     []jsonify.Option{jsonify.WithProtoOptions(protojson.MarshalOptions{Multiline: true, Indent: "\t"})},
+			expected: "{\n\t\"name\": \"id\",\n\t\"type\": \"TYPE_MESSAGE\",\n\t\"typeName\": \".pkg.ID\"\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(m, tt.opts...)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	enc := jsonify.New(jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true}))
+	if got := enc.MustString(m); got != tests[1].expected {
+		t.Errorf("Encoder.MustString() = %v, want %v", got, tests[1].expected)
+	}
+}
+
+func TestNestedProtobufMessage(t *testing.T) {
+	type envelope struct {
+		Kind   string                               `json:"kind"`
+		Field  *descriptorpb.FieldDescriptorProto   `json:"field"`
+		Fields []*descriptorpb.FieldDescriptorProto `json:"fields,omitempty"`
+		Extra  any                                  `json:"extra,omitempty"`
+	}
+	field := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), JsonName: proto.String("id")}
+	v := envelope{
+		Kind:   "field",
+		Field:  field,
+		Fields: []*descriptorpb.FieldDescriptorProto{field, nil},
+		Extra:  map[string]any{"f": field},
+	}
+	expected := `{"kind":"field","field":{"name":"id","jsonName":"id"},"fields":[{"name":"id","jsonName":"id"},null],"extra":{"f":{"name":"id","jsonName":"id"}}}`
+	got, err := jsonify.String(v)
+	if err != nil || got != expected {
+		t.Errorf("String() = %v, %v, want %v", got, err, expected)
+	}
+	opts := jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true})
+	if got := jsonify.MustString(envelope{Field: field}, opts); got != `{"kind":"","field":{"name":"id","json_name":"id"}}` {
+		t.Errorf("String() with WithProtoOptions = %v", got)
+	}
+	if got := jsonify.MustString(envelope{Field: field}, jsonify.WithIndent("", " ")); got != "{\n \"kind\": \"\",\n \"field\": {\n  \"name\": \"id\",\n  \"jsonName\": \"id\"\n }\n}" {
+		t.Errorf("String() with WithIndent = %q", got)
+	}
+
+	var decoded envelope
+	data := []byte(`{"kind":"field","field":{"name":"id","number":1,"bogus":1},"fields":[{"json_name":"x"},null]}`)
+	if err := jsonify.Decode(data, &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Field.GetName() != "id" || decoded.Field.GetNumber() != 1 || len(decoded.Fields) != 2 || decoded.Fields[0].GetJsonName() != "x" || decoded.Fields[1] != nil {
+		t.Errorf("Decode() = %+v", decoded)
+	}
+	if err := jsonify.Decode(data, &decoded, jsonify.WithDisallowUnknownFields()); err == nil {
+		t.Errorf("Decode() with WithDisallowUnknownFields() error = nil, want error")
+	}
+	if err := jsonify.Decode([]byte(`{"field":{"number":"x"}}`), &decoded); err == nil {
+		t.Errorf("Decode() of an invalid message error = nil, want error")
+	}
+}
+
+func TestProtobufMessageCollections(t *testing.T) {
+	a := &descriptorpb.FieldDescriptorProto{Name: proto.String("a")}
+	b := &descriptorpb.FieldDescriptorProto{Number: proto.Int32(2)}
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "slice", input: []*descriptorpb.FieldDescriptorProto{a, b}, expected: `[{"name":"a"},{"number":2}]`},
+		{name: "interface slice", input: []proto.Message{a, nil}, expected: `[{"name":"a"},null]`},
+		{name: "map", input: map[string]*descriptorpb.FieldDescriptorProto{"y": b, "x": a}, expected: `{"x":{"name":"a"},"y":{"number":2}}`},
+		{name: "array", input: [1]*descriptorpb.FieldDescriptorProto{a}, expected: `[{"name":"a"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	var decoded map[string]*descriptorpb.FieldDescriptorProto
+	if err := jsonify.Decode([]byte(`{"x":{"name":"a"}}`), &decoded); err != nil || decoded["x"].GetName() != "a" {
+		t.Errorf("Decode() = %v, %v", decoded, err)
+	}
+}
+
+func TestWithProtoResolver(t *testing.T) {
+	// example.Private is known to the private registry only.
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("private.proto"),
+		Package: proto.String("example"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Private"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     proto.String("name"),
+					JsonName: proto.String("name"),
+					Number:   proto.Int32(1),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				},
+				{
+					Name:     proto.String("detail"),
+					JsonName: proto.String("detail"),
+					Number:   proto.Int32(2),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					TypeName: proto.String(".google.protobuf.Any"),
+				},
+			},
+		}},
+		Dependency: []string{"google/protobuf/any.proto"},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private := dynamicpb.NewMessageType(fd.Messages().Get(0))
+	var types protoregistry.Types
+	if err := types.RegisterMessage(private); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := &anypb.Any{TypeUrl: "type.googleapis.com/example.Unknown", Value: []byte("\n\x03foo")}
+	msg := private.New()
+	msg.Set(fd.Messages().Get(0).Fields().ByName("name"), protoreflect.ValueOfString("x"))
+	msg.Set(fd.Messages().Get(0).Fields().ByName("detail"), protoreflect.ValueOfMessage(unknown.ProtoReflect()))
+	known, err := anypb.New(msg.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "unknown",
+			value:    unknown,
+			expected: `{"@type":"type.googleapis.com/example.Unknown","value":"CgNmb28="}`,
+		},
+		{
+			name:     "private",
+			value:    known,
+			expected: `{"@type":"type.googleapis.com/example.Private","name":"x","detail":{"@type":"type.googleapis.com/example.Unknown","value":"CgNmb28="}}`,
+		},
+		{
+			name:     "nested",
+			value:    map[string]any{"list": []*anypb.Any{unknown}},
+			expected: `{"list":[{"@type":"type.googleapis.com/example.Unknown","value":"CgNmb28="}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jsonify.String(tt.value); err == nil {
+				t.Errorf("String() without WithProtoResolver error = nil, want error")
+			}
+			got, err := jsonify.String(tt.value, jsonify.WithProtoResolver(&types))
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	if got := unknown.GetValue(); string(got) != "\n\x03foo" {
+		t.Errorf("String() changed the value of the Any to %q", got)
+	}
+}
+
+func TestProtoreflectMessage(t *testing.T) {
+	msg := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(1)}
+	want := `{"name":"id","number":1}`
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "top level", value: msg.ProtoReflect(), expected: want},
+		{name: "in a map", value: map[string]any{"field": msg.ProtoReflect()}, expected: `{"field":` + want + `}`},
+		{name: "in a slice", value: []protoreflect.Message{msg.ProtoReflect(), nil}, expected: `[` + want + `,null]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.value)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	var m descriptorpb.FieldDescriptorProto
+	if err := jsonify.Decode([]byte(want), m.ProtoReflect()); err != nil || m.GetName() != "id" {
+		t.Errorf("Decode() into a protoreflect.Message = %v, %v", &m, err)
+	}
+}
+
+func TestWithProtoAsGo(t *testing.T) {
+	msg := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(1), JsonName: proto.String("ID")}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "top level", value: msg, expected: `{"name":"id","number":1,"json_name":"ID"}`},
+		{name: "nested", value: map[string]any{"field": msg}, expected: `{"field":{"name":"id","number":1,"json_name":"ID"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.value, jsonify.WithProtoAsGo())
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	if got, _ := jsonify.String(msg); got != `{"name":"id","number":1,"jsonName":"ID"}` {
+		t.Errorf("String() without WithProtoAsGo = %v", got)
+	}
+}
+
+func TestTypedNilProtobufMessage(t *testing.T) {
+	var m *descriptorpb.FieldDescriptorProto
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "pointer", value: m, expected: `null`},
+		{name: "interface", value: proto.Message(m), expected: `null`},
+		{name: "protoreflect", value: m.ProtoReflect(), expected: `null`},
+		{name: "nested", value: map[string]any{"a": proto.Message(m), "b": []proto.Message{m}}, expected: `{"a":null,"b":[null]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.value)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	if err := jsonify.Decode([]byte(`{}`), m); err == nil {
+		t.Errorf("Decode() into a nil message error = nil, want error")
+	}
+}
+
+func TestProtobufEnum(t *testing.T) {
+	type field struct {
+		Type  descriptorpb.FieldDescriptorProto_Type   `json:"type"`
+		Label *descriptorpb.FieldDescriptorProto_Label `json:"label,omitempty"`
+	}
+	msg := &descriptorpb.FieldDescriptorProto{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()}
+
+	tests := []struct {
+		name     string
+		value    any
+		opts     []jsonify.Option
+		expected string
+	}{
+		{name: "go value", value: field{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING}, expected: `{"type":9}`},
+		{name: "go value by name", value: field{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING}, opts: []jsonify.Option{jsonify.WithProtoEnumNames()}, expected: `{"type":"TYPE_STRING"}`},
+		{name: "unknown value by name", value: descriptorpb.FieldDescriptorProto_Type(99), opts: []jsonify.Option{jsonify.WithProtoEnumNames()}, expected: `99`},
+		{name: "message", value: msg, expected: `{"type":"TYPE_STRING"}`},
+		{name: "go value as number", value: field{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING}, opts: []jsonify.Option{jsonify.WithProtoEnumNames(), jsonify.WithEnumNumbers()}, expected: `{"type":9}`},
+		{name: "message as number", value: msg, opts: []jsonify.Option{jsonify.WithEnumNumbers()}, expected: `{"type":9}`},
+		{name: "nested message as number", value: []any{msg}, opts: []jsonify.Option{jsonify.WithEnumNumbers()}, expected: `[{"type":9}]`},
+		{name: "as go", value: field{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING}, opts: []jsonify.Option{jsonify.WithProtoAsGo()}, expected: `{"type":9}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.value, tt.opts...)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, data := range []string{`{"type":"TYPE_STRING","label":"LABEL_REPEATED"}`, `{"type":9,"label":3}`} {
+		var got field
+		if err := jsonify.Decode([]byte(data), &got); err != nil || got.Type != descriptorpb.FieldDescriptorProto_TYPE_STRING || got.Label.Number() != 3 {
+			t.Errorf("Decode(%s) = %+v, %v", data, got, err)
+		}
+	}
+	var got field
+	if err := jsonify.Decode([]byte(`{"type":"TYPE_BOGUS"}`), &got); err == nil {
+		t.Errorf("Decode() of an unknown name error = nil, want error")
+	}
+}
+
+func TestToCSV_proto(t *testing.T) {
+	fields := []*descriptorpb.FieldDescriptorProto{
+		{Name: proto.String("user_id"), Number: proto.Int32(1), JsonName: proto.String("userId")},
+		{Name: proto.String("tags"), Number: proto.Int32(2)},
+	}
+	var sb strings.Builder
+	if err := jsonify.ToCSV(&sb, fields); err != nil {
+		t.Fatal(err)
+	}
+	if want := "name,number,jsonName\nuser_id,1,userId\ntags,2,\n"; sb.String() != want {
+		t.Errorf("ToCSV() wrote %q, want %q", sb.String(), want)
+	}
+}
+
+func TestYAMLBytes_proto(t *testing.T) {
+	field := &descriptorpb.FieldDescriptorProto{Name: proto.String("user_id"), JsonName: proto.String("userId")}
+	got, err := jsonify.YAMLBytes(map[string]any{"field": field})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "field:\n  name: user_id\n  jsonName: userId\n"; string(got) != want {
+		t.Errorf("YAMLBytes() = %q, want %q", got, want)
+	}
+}
+
+func TestMsgpackBytes_proto(t *testing.T) {
+	field := &descriptorpb.FieldDescriptorProto{JsonName: proto.String("id")}
+	got, err := jsonify.MsgpackBytes(field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"jsonName":"id"}
+	if want := "\x81\xa8jsonName\xa2id"; string(got) != want {
+		t.Errorf("MsgpackBytes() = %q, want %q", got, want)
+	}
+}
+
+func TestCBOR_proto(t *testing.T) {
+	want := &descriptorpb.FieldDescriptorProto{Name: proto.String("user_id"), Number: proto.Int32(1)}
+	b, err := jsonify.CBORBytes(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &descriptorpb.FieldDescriptorProto{}
+	if err := jsonify.DecodeCBOR(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("DecodeCBOR() = %v, want %v", got, want)
+	}
+}
+
+func TestWithIndent_proto(t *testing.T) {
+	m := &descriptorpb.FieldDescriptorProto{Name: proto.String("a"), Options: &descriptorpb.FieldOptions{Packed: proto.Bool(true)}}
+	tests := []struct {
+		name           string
+		prefix, indent string
+		want           string
+	}{
+		{name: "tab", indent: "\t", want: "{\n\t\"name\": \"a\",\n\t\"options\": {\n\t\t\"packed\": true\n\t}\n}"},
+		{name: "four spaces", indent: "    ", want: "{\n    \"name\": \"a\",\n    \"options\": {\n        \"packed\": true\n    }\n}"},
+		{name: "prefix", prefix: "# ", indent: "-", want: "{\n# -\"name\": \"a\",\n# -\"options\": {\n# --\"packed\": true\n# -}\n# }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(m, jsonify.WithIndent(tt.prefix, tt.indent))
+			if err != nil || got != tt.want {
+				t.Errorf("String() = %q, %v, want %q", got, err, tt.want)
+			}
+			nested, err := jsonify.String([]any{m}, jsonify.WithIndent(tt.prefix, tt.indent))
+			if err != nil || !strings.Contains(nested, tt.indent+tt.indent+`"packed"`) {
+				t.Errorf("String() of a nested message = %q, %v", nested, err)
+			}
+		})
+	}
+}
+
+func TestWriteJSON_proto(t *testing.T) {
+	rec := httptest.NewRecorder()
+	m := &descriptorpb.FieldDescriptorProto{JsonName: proto.String("id")}
+	if err := jsonify.WriteJSON(rec, http.StatusOK, m); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"jsonName":"id"}`; rec.Body.String() != want {
+		t.Errorf("WriteJSON() wrote %s, want %s", rec.Body.String(), want)
+	}
+}
+
+func TestReadJSON_proto(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"jsonName":"id"}`))
+	r.Header.Set("Content-Type", "application/json")
+	var m descriptorpb.FieldDescriptorProto
+	if err := jsonify.ReadJSON(r, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.GetJsonName() != "id" {
+		t.Errorf("ReadJSON() decoded %v", &m)
+	}
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"jsonNmae":"id"}`))
+	r.Header.Set("Content-Type", "application/json")
+	var re *jsonify.RequestError
+	if err := jsonify.ReadJSON(r, &m); !errors.As(err, &re) {
+		t.Fatalf("ReadJSON() error = %v, want a *RequestError", err)
+	}
+	if want := `request body has unknown field "jsonNmae"`; re.Status != http.StatusBadRequest || re.Message != want {
+		t.Errorf("ReadJSON() error = %d %q, want 400 %q", re.Status, re.Message, want)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// editMessages calls edit with m and each message in it, innermost first,
+// and with its encoding in b, the protojson encoding of m, and returns b
+// with the encodings edit returns.
+//
+// Messages that are not encoded as an object of their fields, e.g. the
+// well-known types other than Empty and FieldMask, are left as is, along
+// with the messages in them.
+func editMessages(b []byte, m protoreflect.Message, edit func(b []byte, m protoreflect.Message) ([]byte, error)) ([]byte, error) {
+	switch m.Descriptor().FullName() {
+	case "google.protobuf.Any", "google.protobuf.Struct":
+		return b, nil
+	}
+	if i := skipSpace(b, 0); i == len(b) || b[i] != '{' {
+		return b, nil
+	}
+	keys, values, err := objectMembers(b)
+	if err != nil {
+		return nil, err
+	}
+	fields := m.Descriptor().Fields()
+	for i, k := range keys {
+		fd := fields.ByJSONName(k)
+		if fd == nil {
+			fd = fields.ByTextName(k)
+		}
+		if fd == nil || !m.Has(fd) || string(values[i]) == "null" {
+			continue
+		}
+		if values[i], err = editField(values[i], m.Get(fd), fd, edit); err != nil {
+			return nil, err
+		}
+	}
+	return edit(appendObject(nil, keys, values), m)
+}
+
+// editField calls [editMessages] with the messages in v, the value of the
+// field fd, and b, its encoding.
+func editField(b []byte, v protoreflect.Value, fd protoreflect.FieldDescriptor, edit func([]byte, protoreflect.Message) ([]byte, error)) ([]byte, error) {
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return b, nil
+		}
+		entries := map[string]protoreflect.Message{}
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries[k.String()] = v.Message()
+			return true
+		})
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		for i, k := range keys {
+			if m, ok := entries[k]; ok {
+				if values[i], err = editMessages(values[i], m, edit); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendObject(nil, keys, values), nil
+	case fd.Message() == nil:
+		return b, nil
+	case fd.IsList():
+		var elems []json.RawMessage
+		if err := json.Unmarshal(b, &elems); err != nil {
+			return nil, err
+		}
+		list := v.List()
+		if len(elems) != list.Len() {
+			return b, nil
+		}
+		out := []byte{'['}
+		for i, elem := range elems {
+			elem, err := editMessages(elem, list.Get(i).Message(), edit)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				out = append(out, ',')
+			}
+			out = append(out, elem...)
+		}
+		return append(out, ']'), nil
+	}
+	return editMessages(b, v.Message(), edit)
+}
@@ -0,0 +1,83 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var protoEnumType = reflect2.TypeOfPtr((*protoreflect.Enum)(nil)).Elem()
+
+// WithProtoEnumNames returns an [Option] that encodes the protobuf enums
+// outside of messages, e.g. in the fields of Go structs, by name, as
+// protojson does inside messages:
+//
+//	jsonify.Bytes(column{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING}, jsonify.WithProtoEnumNames())
+//	// {"type":"TYPE_STRING"}
+//
+// By default, they are encoded as numbers, like other Go integers. Values
+// without a name are encoded as numbers anyway, and [WithEnumNumbers] takes
+// precedence. Decoding accepts both names and numbers regardless.
+// WithProtoEnumNames is not available with the jsonify_noproto build tag.
+func WithProtoEnumNames() Option {
+	return func(o *options) {
+		o.proto.enumNames = true
+	}
+}
+
+// isEnum reports whether typ is a generated protobuf enum type, which
+// protojson encodes by name, while jsoniter would encode it as a number.
+func isEnum(typ reflect2.Type) bool {
+	return typ.Kind() == reflect.Int32 && typ.Implements(protoEnumType)
+}
+
+// protoEnumCodec encodes and decodes a protobuf enum outside of a message:
+// by number, or by name with [WithProtoEnumNames], as protojson does inside
+// one.
+type protoEnumCodec struct {
+	typ reflect2.Type
+}
+
+func (c *protoEnumCodec) enum(ptr unsafe.Pointer) protoreflect.Enum {
+	return c.typ.UnsafeIndirect(ptr).(protoreflect.Enum)
+}
+
+func (c *protoEnumCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*int32)(ptr) == 0
+}
+
+func (c *protoEnumCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	e := c.enum(ptr)
+	if o, _ := stream.Attachment.(*options); o != nil && o.proto.enumNames && !o.enumNumbers {
+		if v := e.Descriptor().Values().ByNumber(e.Number()); v != nil {
+			stream.WriteString(string(v.Name()))
+			return
+		}
+	}
+	stream.WriteInt32(int32(e.Number()))
+}
+
+func (c *protoEnumCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		name := iter.ReadString()
+		v := c.enum(ptr).Descriptor().Values().ByName(protoreflect.Name(name))
+		if v == nil {
+			iter.ReportError("protobuf enum", "unknown name "+strconv.Quote(name))
+			return
+		}
+		*(*int32)(ptr) = int32(v.Number())
+	case jsoniter.NumberValue:
+		*(*int32)(ptr) = iter.ReadInt32()
+	case jsoniter.NilValue:
+		iter.Skip()
+	default:
+		iter.ReportError("protobuf enum", "expect string or number")
+	}
+}
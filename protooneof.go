@@ -0,0 +1,90 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WithProtoOneofDiscriminators returns an [Option] that adds to the objects
+// of protobuf messages a member for each oneof that is set, named after the
+// oneof, holding the name of its field that is set, before that field:
+//
+//	// message Contact { oneof kind { Email email = 1; Phone phone = 2; } }
+//	jsonify.Bytes(contact, jsonify.WithProtoOneofDiscriminators())
+//	// {"kind":"email","email":{"address":"ann@example.com"}}
+//
+// protojson encodes the field that is set as any other, so consumers
+// otherwise need to know the schema to tell which branch was set. The names
+// are the JSON names of the oneof and its field, or their names in the
+// .proto file with UseProtoNames in [WithProtoOptions].
+//
+// Oneofs of proto3 optional fields are not discriminated.
+// WithProtoOneofDiscriminators is not available with the jsonify_noproto
+// build tag.
+func WithProtoOneofDiscriminators() Option {
+	return func(o *options) {
+		o.proto.formats.oneofs = true
+	}
+}
+
+// addOneofDiscriminators adds the discriminators of the oneofs of m and of
+// the messages in it to b, the protojson encoding of m.
+func addOneofDiscriminators(b []byte, m protoreflect.Message, protoNames bool) ([]byte, error) {
+	return editMessages(b, m, func(b []byte, m protoreflect.Message) ([]byte, error) {
+		oneofs := m.Descriptor().Oneofs()
+		if oneofs.Len() == 0 {
+			return b, nil
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		for i := range oneofs.Len() {
+			od := oneofs.Get(i)
+			fd := m.WhichOneof(od)
+			if od.IsSynthetic() || fd == nil {
+				continue
+			}
+			key, name := jsonCamelCase(string(od.Name())), fd.JSONName()
+			if protoNames {
+				key, name = string(od.Name()), string(fd.Name())
+			}
+			at := len(keys)
+			for j, k := range keys {
+				if k == fd.JSONName() || k == string(fd.Name()) {
+					at = j
+					break
+				}
+			}
+			keys = slices.Insert(keys, at, key)
+			values = slices.Insert(values, at, json.RawMessage(appendString(nil, name)))
+		}
+		return appendObject(nil, keys, values), nil
+	})
+}
+
+// jsonCamelCase returns the JSON name protoc derives from the name of a
+// field: its name with the underscores removed and the letters following
+// them in upper case.
+func jsonCamelCase(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			sb.WriteRune(c - 'a' + 'A')
+			upper = false
+		default:
+			sb.WriteRune(c)
+			upper = false
+		}
+	}
+	return sb.String()
+}
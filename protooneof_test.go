@@ -0,0 +1,148 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// contactDescriptors returns the descriptors of example.Book, which holds
+// example.Contact messages with a oneof and a proto3 optional field.
+func contactDescriptors(t *testing.T) *jsonify.Descriptors {
+	t.Helper()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	field := func(name, jsonName string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, oneof *int32) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:       proto.String(name),
+			JsonName:   proto.String(jsonName),
+			Number:     proto.Int32(number),
+			Label:      optional,
+			Type:       typ.Enum(),
+			OneofIndex: oneof,
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	contacts := field("contacts", "contacts", 1, msg, ".example.Contact", nil)
+	contacts.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	nickname := field("nickname", "nickname", 4, str, "", proto.Int32(1))
+	nickname.Proto3Optional = proto.Bool(true)
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("example/contact.proto"),
+		Package: proto.String("example"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Contact"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("email", "email", 1, str, "", proto.Int32(0)),
+					field("phone", "phone", 2, msg, ".example.Phone", proto.Int32(0)),
+					field("name", "name", 3, str, "", nil),
+					nickname,
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: proto.String("contact_kind")},
+					{Name: proto.String("_nickname")},
+				},
+			},
+			{
+				Name:  proto.String("Phone"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("number", "number", 1, str, "", nil)},
+			},
+			{
+				Name:  proto.String("Book"),
+				Field: []*descriptorpb.FieldDescriptorProto{contacts},
+			},
+		},
+	}}}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := jsonify.LoadDescriptorSet(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestWithProtoOneofDiscriminators(t *testing.T) {
+	d := contactDescriptors(t)
+	tests := []struct {
+		name string
+		typ  string
+		json string
+		opts []jsonify.Option
+		want string
+	}{
+		{
+			name: "scalar",
+			typ:  "example.Contact",
+			json: `{"name":"Ann","email":"ann@example.com"}`,
+			want: `{"contactKind":"email","email":"ann@example.com","name":"Ann"}`,
+		},
+		{
+			name: "message",
+			typ:  "example.Contact",
+			json: `{"phone":{"number":"123"},"nickname":"A"}`,
+			want: `{"contactKind":"phone","phone":{"number":"123"},"nickname":"A"}`,
+		},
+		{
+			name: "empty value",
+			typ:  "example.Contact",
+			json: `{"email":""}`,
+			want: `{"contactKind":"email","email":""}`,
+		},
+		{
+			name: "unset",
+			typ:  "example.Contact",
+			json: `{"name":"Ann"}`,
+			want: `{"name":"Ann"}`,
+		},
+		{
+			name: "nested",
+			typ:  "example.Book",
+			json: `{"contacts":[{"email":"a"},{"name":"b"}]}`,
+			want: `{"contacts":[{"contactKind":"email","email":"a"},{"name":"b"}]}`,
+		},
+		{
+			name: "proto names",
+			typ:  "example.Contact",
+			json: `{"email":"a"}`,
+			opts: []jsonify.Option{jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true})},
+			want: `{"contact_kind":"email","email":"a"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := d.Decode(tt.typ, []byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := append(tt.opts, jsonify.WithProtoOneofDiscriminators())
+			got, err := jsonify.String(m, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			nested, err := jsonify.String([]any{m}, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "[" + tt.want + "]"; nested != want {
+				t.Errorf("String() nested = %s, want %s", nested, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// streamFlushInterval is how long [EncodeStream] buffers lines at most.
+const streamFlushInterval = time.Second
+
+// EncodeStream writes msgs to w as newline-delimited JSON, one message per
+// line encoded with [Bytes] and opts, e.g. to export the responses of a gRPC
+// stream to a file:
+//
+//	msgs := func(yield func(*pb.Event) bool) {
+//		for {
+//			ev, err := stream.Recv()
+//			if err != nil || !yield(ev) {
+//				return
+//			}
+//		}
+//	}
+//	err := jsonify.EncodeStream(f, msgs)
+//
+// Lines are buffered, and flushed at least once a second, even while msgs
+// waits for the next message, and when msgs ends, so that a consumer reading
+// w sees them while the stream is slow. w is then written from another
+// goroutine, but never after EncodeStream returns. Flushing
+// also flushes w if it has a Flush method, e.g. an [http.ResponseWriter] or a
+// [bufio.Writer]. Indentation options are ignored, as each message must fit
+// on one line.
+//
+// EncodeStream stops at the first error, after writing the lines before it.
+// It is not available with the jsonify_noproto build tag.
+func EncodeStream[M proto.Message](w io.Writer, msgs iter.Seq[M], opts ...Option) error {
+	sw := newStreamWriter(w)
+	defer sw.stop()
+	for m := range msgs {
+		b, err := Bytes(m, opts...)
+		if err == nil && bytes.IndexByte(b, '\n') >= 0 {
+			b, err = Compact(b)
+		}
+		if err != nil {
+			sw.flush()
+			return err
+		}
+		if err := sw.writeLine(b); err != nil {
+			return err
+		}
+	}
+	return sw.flush()
+}
+
+// streamWriter buffers the lines of [EncodeStream], and flushes them from a
+// ticker, so that they are written while the stream waits for a message.
+type streamWriter struct {
+	w      io.Writer
+	ticker *time.Ticker
+	done   chan struct{}
+	exited chan struct{}
+
+	mu      sync.Mutex
+	bw      *bufio.Writer
+	pending bool  // lines are buffered since the last flush
+	err     error // the first error of flushing or writing
+}
+
+func newStreamWriter(w io.Writer) *streamWriter {
+	sw := &streamWriter{
+		w:      w,
+		ticker: time.NewTicker(streamFlushInterval),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		bw:     bufio.NewWriter(w),
+	}
+	go func() {
+		defer close(sw.exited)
+		for {
+			select {
+			case <-sw.ticker.C:
+				sw.mu.Lock()
+				if sw.pending && sw.err == nil {
+					sw.err = sw.flushLocked()
+				}
+				sw.mu.Unlock()
+			case <-sw.done:
+				return
+			}
+		}
+	}()
+	return sw
+}
+
+// stop stops the ticker, and waits for a flush in progress, so that w is
+// not written after EncodeStream returns.
+func (sw *streamWriter) stop() {
+	sw.ticker.Stop()
+	close(sw.done)
+	<-sw.exited
+}
+
+func (sw *streamWriter) writeLine(b []byte) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.err != nil {
+		return sw.err
+	}
+	sw.bw.Write(b)
+	sw.err = sw.bw.WriteByte('\n')
+	sw.pending = true
+	return sw.err
+}
+
+func (sw *streamWriter) flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.err != nil {
+		return sw.err
+	}
+	sw.err = sw.flushLocked()
+	return sw.err
+}
+
+func (sw *streamWriter) flushLocked() error {
+	sw.pending = false
+	if err := sw.bw.Flush(); err != nil {
+		return err
+	}
+	return flushWriter(sw.w)
+}
@@ -0,0 +1,163 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func ExampleEncodeStream() {
+	msgs := []*wrapperspb.StringValue{wrapperspb.String("a"), wrapperspb.String("b")}
+	jsonify.EncodeStream(os.Stdout, slices.Values(msgs))
+	// Output:
+	// "a"
+	// "b"
+}
+
+// flushRecorder records the lines written to it when it is flushed.
+type flushRecorder struct {
+	strings.Builder
+	flushed []string
+}
+
+func (r *flushRecorder) Flush() error {
+	r.flushed = append(r.flushed, r.String())
+	return nil
+}
+
+func TestEncodeStream(t *testing.T) {
+	s, _ := structpb.NewStruct(map[string]any{"a": []any{1, 2}})
+	bad := structpb.NewNumberValue(math.NaN())
+
+	tests := []struct {
+		name    string
+		msgs    iter.Seq[*structpb.Value]
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", msgs: slices.Values([]*structpb.Value{}), want: ""},
+		{
+			name: "messages",
+			msgs: slices.Values([]*structpb.Value{structpb.NewStructValue(s), structpb.NewStringValue("x")}),
+			want: "{\"a\":[1,2]}\n\"x\"\n",
+		},
+		{
+			name: "indent",
+			msgs: slices.Values([]*structpb.Value{structpb.NewStructValue(s)}),
+			opts: []jsonify.Option{jsonify.WithIndent("", "  ")},
+			want: "{\"a\":[1,2]}\n",
+		},
+		{
+			name:    "error",
+			msgs:    slices.Values([]*structpb.Value{structpb.NewBoolValue(true), bad, structpb.NewBoolValue(false)}),
+			want:    "true\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w flushRecorder
+			err := jsonify.EncodeStream(&w, tt.msgs, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EncodeStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("EncodeStream() wrote %q, want %q", got, tt.want)
+			}
+			if !tt.wantErr && (len(w.flushed) == 0 || w.flushed[len(w.flushed)-1] != tt.want) {
+				t.Errorf("EncodeStream() flushed %q, want %q last", w.flushed, tt.want)
+			}
+		})
+	}
+}
+
+// syncRecorder is a [flushRecorder] safe for concurrent use.
+type syncRecorder struct {
+	mu sync.Mutex
+	flushRecorder
+}
+
+func (r *syncRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushRecorder.Write(p)
+}
+
+func (r *syncRecorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushRecorder.Flush()
+}
+
+func (r *syncRecorder) lastFlushed() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.flushed) == 0 {
+		return ""
+	}
+	return r.flushed[len(r.flushed)-1]
+}
+
+func TestEncodeStream_stalled(t *testing.T) {
+	var w syncRecorder
+	flushed := make(chan string, 1)
+	msgs := func(yield func(*wrapperspb.StringValue) bool) {
+		if !yield(wrapperspb.String("a")) {
+			return
+		}
+		// The stream stalls until the line is flushed.
+		deadline := time.Now().Add(5 * time.Second)
+		for w.lastFlushed() == "" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		flushed <- w.lastFlushed()
+		yield(wrapperspb.String("b"))
+	}
+	if err := jsonify.EncodeStream(&w, msgs); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := <-flushed, "\"a\"\n"; got != want {
+		t.Errorf("EncodeStream() flushed %q while the stream stalled, want %q", got, want)
+	}
+	if got, want := w.lastFlushed(), "\"a\"\n\"b\"\n"; got != want {
+		t.Errorf("EncodeStream() flushed %q last, want %q", got, want)
+	}
+}
+
+func TestEncodeStream_writeError(t *testing.T) {
+	msgs := slices.Values([]*wrapperspb.BoolValue{wrapperspb.Bool(true)})
+	if err := jsonify.EncodeStream(errorWriter{}, msgs); !errors.Is(err, errWrite) {
+		t.Errorf("EncodeStream() error = %v, want %v", err, errWrite)
+	}
+}
+
+func TestDecodeLines_proto(t *testing.T) {
+	var sb strings.Builder
+	msgs := []*wrapperspb.StringValue{wrapperspb.String("a"), wrapperspb.String("b")}
+	if err := jsonify.EncodeStream(&sb, slices.Values(msgs)); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for m, err := range jsonify.DecodeLines[*wrapperspb.StringValue](strings.NewReader(sb.String())) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m.GetValue())
+	}
+	if want := []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("DecodeLines() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TimestampFormat is how google.protobuf.Timestamp messages are encoded.
+type TimestampFormat int
+
+const (
+	// TimestampRFC3339 encodes a Timestamp as protojson does, as an RFC 3339
+	// string: "2024-01-02T03:04:05.500Z".
+	TimestampRFC3339 TimestampFormat = iota
+
+	// TimestampUnixMillis encodes a Timestamp as the number of milliseconds
+	// since the Unix epoch, with a fraction if needed: 1704164645500.
+	TimestampUnixMillis
+
+	// TimestampUnixSeconds encodes a Timestamp as the number of seconds
+	// since the Unix epoch, with a fraction if needed: 1704164645.5.
+	TimestampUnixSeconds
+)
+
+// DurationFormat is how google.protobuf.Duration messages are encoded.
+type DurationFormat int
+
+const (
+	// DurationSeconds encodes a Duration as protojson does, as a string of
+	// seconds: "5400s".
+	DurationSeconds DurationFormat = iota
+
+	// DurationGo encodes a Duration as a string in the format of
+	// [time.Duration.String]: "1h30m0s".
+	DurationGo
+)
+
+// WithTimestampFormat returns an [Option] that encodes the
+// google.protobuf.Timestamp messages in the value with f, whether they are
+// the value itself or nested in other messages or values.
+//
+// Timestamps in the value of a google.protobuf.Any are encoded as protojson
+// does. WithTimestampFormat is not available with the jsonify_noproto build
+// tag.
+func WithTimestampFormat(f TimestampFormat) Option {
+	return func(o *options) {
+		o.proto.formats.times.timestamp = f
+	}
+}
+
+// WithDurationFormat returns an [Option] that encodes the
+// google.protobuf.Duration messages in the value with f, like
+// [WithTimestampFormat] does for timestamps.
+//
+// WithDurationFormat is not available with the jsonify_noproto build tag.
+func WithDurationFormat(f DurationFormat) Option {
+	return func(o *options) {
+		o.proto.formats.times.duration = f
+	}
+}
+
+// timeFormats holds the formats of the Timestamp and Duration messages.
+type timeFormats struct {
+	timestamp TimestampFormat
+	duration  DurationFormat
+}
+
+const (
+	timestampFullName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationFullName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// format reformats the Timestamp and Duration messages in b, the protojson
+// encoding of a message described by md.
+func (f timeFormats) format(b []byte, md protoreflect.MessageDescriptor) ([]byte, error) {
+	switch md.FullName() {
+	case timestampFullName:
+		return f.formatTimestamp(b)
+	case durationFullName:
+		return f.formatDuration(b)
+	}
+	if md.FullName().Parent() == "google.protobuf" || !hasTimeField(md, map[protoreflect.FullName]bool{}) {
+		// Other well-known types have their own encoding, and the rest of
+		// the messages need no change.
+		return b, nil
+	}
+	keys, values, err := objectMembers(b)
+	if err != nil {
+		return nil, err
+	}
+	fields := md.Fields()
+	for i, key := range keys {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByTextName(key)
+		}
+		if fd == nil || string(values[i]) == "null" {
+			continue
+		}
+		if values[i], err = f.formatField(values[i], fd); err != nil {
+			return nil, err
+		}
+	}
+	return appendObject(nil, keys, values), nil
+}
+
+// formatField reformats the Timestamp and Duration messages in b, the
+// encoding of the field fd.
+func (f timeFormats) formatField(b []byte, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return b, nil
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			if values[i], err = f.format(values[i], fd.MapValue().Message()); err != nil {
+				return nil, err
+			}
+		}
+		return appendObject(nil, keys, values), nil
+	case fd.Message() == nil:
+		return b, nil
+	case fd.IsList():
+		var elems []json.RawMessage
+		if err := json.Unmarshal(b, &elems); err != nil {
+			return nil, err
+		}
+		out := []byte{'['}
+		for i, elem := range elems {
+			elem, err := f.format(elem, fd.Message())
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				out = append(out, ',')
+			}
+			out = append(out, elem...)
+		}
+		return append(out, ']'), nil
+	}
+	return f.format(b, fd.Message())
+}
+
+// hasTimeField reports whether a message described by md may contain a
+// Timestamp or Duration message.
+func hasTimeField(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool {
+	if seen[md.FullName()] {
+		return false
+	}
+	seen[md.FullName()] = true
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if m := fd.Message(); m != nil {
+			if name := m.FullName(); name == timestampFullName || name == durationFullName || hasTimeField(m, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f timeFormats) formatTimestamp(b []byte) ([]byte, error) {
+	if f.timestamp == TimestampRFC3339 {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: invalid timestamp %q: %w", s, err)
+	}
+	nanos := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(1e9))
+	nanos.Add(nanos, big.NewInt(int64(t.Nanosecond())))
+	if f.timestamp == TimestampUnixMillis {
+		return formatRatio(nanos, 1e6, 6), nil
+	}
+	return formatRatio(nanos, 1e9, 9), nil
+}
+
+// formatRatio formats n/d as a decimal number with up to digits fractional
+// digits.
+func formatRatio(n *big.Int, d int64, digits int) []byte {
+	s := new(big.Rat).SetFrac(n, big.NewInt(d)).FloatString(digits)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return []byte(s)
+}
+
+func (f timeFormats) formatDuration(b []byte) ([]byte, error) {
+	if f.duration == DurationSeconds {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		return appendString(nil, d.String()), nil
+	}
+	// A Duration of up to 10000 years may not fit in a time.Duration,
+	// whose range is about 292 years.
+	if long, ok := formatLongDuration(s); ok {
+		return appendString(nil, long), nil
+	}
+	return nil, fmt.Errorf("jsonify: invalid duration %q: %w", s, err)
+}
+
+// formatLongDuration formats the Duration s, in the protojson form, e.g.
+// "-1.5s", as [time.Duration.String] would if it had the range, e.g.
+// "-87659999h59m59.5s" for "-315575999999.5s".
+func formatLongDuration(s string) (string, bool) {
+	sign := ""
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, s = "-", rest
+	}
+	s, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return "", false
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	secs, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil || len(frac) > 9 || strings.Trim(frac, "0123456789") != "" {
+		return "", false
+	}
+	out := fmt.Sprintf("%s%dh%dm%d", sign, secs/3600, secs/60%60, secs%60)
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		out += "." + frac
+	}
+	return out + "s", true
+}
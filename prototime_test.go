@@ -0,0 +1,156 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ExampleWithTimestampFormat() {
+	ts := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 500e6, time.UTC))
+	s, err := jsonify.String(map[string]any{"created": ts}, jsonify.WithTimestampFormat(jsonify.TimestampUnixMillis))
+	fmt.Println(s, err)
+	// Output:
+	// {"created":1704164645500} <nil>
+}
+
+// eventDescriptors returns the descriptors of example.Event, which holds
+// timestamps and durations in every kind of field.
+func eventDescriptors(t *testing.T) *jsonify.Descriptors {
+	t.Helper()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	message := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field := func(name, jsonName string, number int32, label *descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(jsonName),
+			Number:   proto.Int32(number),
+			Label:    label,
+			Type:     message,
+			TypeName: proto.String(typeName),
+		}
+	}
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       proto.String("example/event.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto", "google/protobuf/duration.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("created_at", "createdAt", 1, optional, ".google.protobuf.Timestamp"),
+				field("history", "history", 2, repeated, ".google.protobuf.Timestamp"),
+				field("timeouts", "timeouts", 3, repeated, ".example.Event.TimeoutsEntry"),
+				field("parent", "parent", 4, optional, ".example.Event"),
+				{
+					Name:     proto.String("note"),
+					JsonName: proto.String("note"),
+					Number:   proto.Int32(5),
+					Label:    optional,
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("TimeoutsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("key"),
+						JsonName: proto.String("key"),
+						Number:   proto.Int32(1),
+						Label:    optional,
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					},
+					field("value", "value", 2, optional, ".google.protobuf.Duration"),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}}}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := jsonify.LoadDescriptorSet(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestTimeFormats(t *testing.T) {
+	d := eventDescriptors(t)
+	event, err := d.Decode("example.Event", []byte(`{
+		"createdAt": "2024-01-02T03:04:05.5Z",
+		"history": ["1969-12-31T23:59:59.5Z", "1970-01-01T00:00:01Z"],
+		"timeouts": {"read": "5400s", "write": "0.25s"},
+		"parent": {"createdAt": "2024-01-02T03:04:05Z"},
+		"note": "<b>"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		opts     []jsonify.Option
+		expected string
+	}{
+		{
+			name:     "default",
+			value:    event,
+			expected: `{"createdAt":"2024-01-02T03:04:05.500Z","history":["1969-12-31T23:59:59.500Z","1970-01-01T00:00:01Z"],"timeouts":{"read":"5400s","write":"0.250s"},"parent":{"createdAt":"2024-01-02T03:04:05Z"},"note":"<b>"}`,
+		},
+		{
+			name:     "unix millis and go durations",
+			value:    event,
+			opts:     []jsonify.Option{jsonify.WithTimestampFormat(jsonify.TimestampUnixMillis), jsonify.WithDurationFormat(jsonify.DurationGo)},
+			expected: `{"createdAt":1704164645500,"history":[-500,1000],"timeouts":{"read":"1h30m0s","write":"250ms"},"parent":{"createdAt":1704164645000},"note":"<b>"}`,
+		},
+		{
+			name:     "unix seconds with proto names",
+			value:    event,
+			opts:     []jsonify.Option{jsonify.WithTimestampFormat(jsonify.TimestampUnixSeconds), jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true})},
+			expected: `{"created_at":1704164645.5,"history":[-0.5,1],"timeouts":{"read":"5400s","write":"0.250s"},"parent":{"created_at":1704164645},"note":"<b>"}`,
+		},
+		{
+			name:     "top level",
+			value:    timestamppb.New(time.Unix(1, 5)),
+			opts:     []jsonify.Option{jsonify.WithTimestampFormat(jsonify.TimestampUnixSeconds)},
+			expected: `1.000000005`,
+		},
+		{
+			name:     "nested in a Go value",
+			value:    []any{durationpb.New(90 * time.Second), struct{ At *timestamppb.Timestamp }{}},
+			opts:     []jsonify.Option{jsonify.WithDurationFormat(jsonify.DurationGo), jsonify.WithTimestampFormat(jsonify.TimestampUnixMillis)},
+			expected: `["1m30s",{"At":null}]`,
+		},
+		{
+			name:     "go durations beyond time.Duration",
+			value:    []any{&durationpb.Duration{Seconds: 315576000000}, &durationpb.Duration{Seconds: -315575999999, Nanos: -500000000}, &durationpb.Duration{Seconds: 9223372037}},
+			opts:     []jsonify.Option{jsonify.WithDurationFormat(jsonify.DurationGo)},
+			expected: `["87660000h0m0s","-87659999h59m59.5s","2562047h47m17s"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.value, tt.opts...)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WithProtoUnknownFields returns an [Option] that encodes the unknown fields
+// of protobuf messages, which protojson drops, as a member of their object
+// under key, so that debugging tools can see the data a newer schema added:
+//
+//	jsonify.Bytes(msg, jsonify.WithProtoUnknownFields("$unknown"))
+//	// {"name":"Ann","$unknown":{"15":[42],"16":["aGk="]}}
+//
+// The member maps each field number to the values it occurs with, in order:
+// varint and fixed-size values as unsigned numbers, length-delimited values
+// as base64 strings, as their type is unknown, and groups as objects of
+// their own fields.
+//
+// Messages encoded as JSON values other than objects, e.g. the well-known
+// types, and the value of a google.protobuf.Any are encoded without their
+// unknown fields. WithProtoUnknownFields is not available with the
+// jsonify_noproto build tag.
+func WithProtoUnknownFields(key string) Option {
+	return func(o *options) {
+		o.proto.formats.unknownKey = key
+	}
+}
+
+// addUnknownFields adds the unknown fields of m and of the messages in it to
+// b, the protojson encoding of m, under key.
+func addUnknownFields(b []byte, m protoreflect.Message, key string) ([]byte, error) {
+	return editMessages(b, m, func(b []byte, m protoreflect.Message) ([]byte, error) {
+		unknown := m.GetUnknown()
+		if len(unknown) == 0 {
+			return b, nil
+		}
+		value, err := appendUnknownFields(nil, unknown)
+		if err != nil {
+			return nil, fmt.Errorf("jsonify: unknown fields of %s: %w", m.Descriptor().FullName(), err)
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		return appendObject(nil, append(keys, key), append(values, value)), nil
+	})
+}
+
+// hasUnknownFields reports whether m or a message in it has unknown fields.
+func hasUnknownFields(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+	found := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					found = hasUnknownFields(v.Message())
+					return !found
+				})
+			}
+		case fd.Message() == nil:
+		case fd.IsList():
+			for i := range v.List().Len() {
+				if found = hasUnknownFields(v.List().Get(i).Message()); found {
+					break
+				}
+			}
+		default:
+			found = hasUnknownFields(v.Message())
+		}
+		return !found
+	})
+	return found
+}
+
+// appendUnknownFields appends the fields encoded in raw, in the protobuf
+// binary format, to b as a JSON object from field numbers to their values.
+func appendUnknownFields(b []byte, raw []byte) ([]byte, error) {
+	byNumber := map[protowire.Number][]json.RawMessage{}
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		raw = raw[n:]
+		var value json.RawMessage
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(raw)
+			value = strconv.AppendUint(nil, v, 10)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(raw)
+			value = strconv.AppendUint(nil, uint64(v), 10)
+		case protowire.Fixed64Type:
+			var v uint64
+			v, n = protowire.ConsumeFixed64(raw)
+			value = strconv.AppendUint(nil, v, 10)
+		case protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(raw)
+			value = appendString(nil, base64.StdEncoding.EncodeToString(v))
+		case protowire.StartGroupType:
+			var v []byte
+			v, n = protowire.ConsumeGroup(num, raw)
+			if n >= 0 {
+				var err error
+				if value, err = appendUnknownFields(nil, v); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unexpected wire type %d of field %d", typ, num)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		raw = raw[n:]
+		byNumber[num] = append(byNumber[num], value)
+	}
+	keys := make([]string, 0, len(byNumber))
+	values := make([]json.RawMessage, 0, len(byNumber))
+	for _, num := range slices.Sorted(maps.Keys(byNumber)) {
+		list := []byte{'['}
+		for i, v := range byNumber[num] {
+			if i > 0 {
+				list = append(list, ',')
+			}
+			list = append(list, v...)
+		}
+		keys = append(keys, strconv.Itoa(int(num)))
+		values = append(values, append(list, ']'))
+	}
+	return appendObject(b, keys, values), nil
+}
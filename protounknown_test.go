@@ -0,0 +1,139 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// withUnknown returns m with the fields of raw as unknown fields.
+func withUnknown[M proto.Message](m M, raw []byte) M {
+	m.ProtoReflect().SetUnknown(raw)
+	return m
+}
+
+func ExampleWithProtoUnknownFields() {
+	var raw []byte
+	raw = protowire.AppendTag(raw, 15, protowire.VarintType)
+	raw = protowire.AppendVarint(raw, 42)
+	raw = protowire.AppendTag(raw, 16, protowire.BytesType)
+	raw = protowire.AppendString(raw, "hi")
+	m := withUnknown(&descriptorpb.EnumValueDescriptorProto{Name: proto.String("RED")}, raw)
+
+	s, err := jsonify.String(m, jsonify.WithProtoUnknownFields("$unknown"))
+	fmt.Println(s, err)
+	// Output:
+	// {"name":"RED","$unknown":{"15":[42],"16":["aGk="]}} <nil>
+}
+
+func TestWithProtoUnknownFields(t *testing.T) {
+	var varints, fixed, group, invalid []byte
+	varints = protowire.AppendTag(varints, 20, protowire.VarintType)
+	varints = protowire.AppendVarint(varints, 1)
+	varints = protowire.AppendTag(varints, 19, protowire.VarintType)
+	varints = protowire.AppendVarint(varints, 1<<63)
+	varints = protowire.AppendTag(varints, 20, protowire.VarintType)
+	varints = protowire.AppendVarint(varints, 2)
+	fixed = protowire.AppendTag(fixed, 30, protowire.Fixed32Type)
+	fixed = protowire.AppendFixed32(fixed, 7)
+	fixed = protowire.AppendTag(fixed, 31, protowire.Fixed64Type)
+	fixed = protowire.AppendFixed64(fixed, 8)
+	group = protowire.AppendTag(group, 40, protowire.StartGroupType)
+	group = protowire.AppendTag(group, 1, protowire.VarintType)
+	group = protowire.AppendVarint(group, 3)
+	group = protowire.AppendTag(group, 40, protowire.EndGroupType)
+	invalid = protowire.AppendTag(invalid, 50, protowire.BytesType)
+	invalid = protowire.AppendVarint(invalid, 10)
+
+	tests := []struct {
+		name    string
+		v       any
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "repeated",
+			v:    withUnknown(&descriptorpb.EnumValueDescriptorProto{}, varints),
+			want: `{"$unknown":{"19":[9223372036854775808],"20":[1,2]}}`,
+		},
+		{
+			name: "fixed",
+			v:    withUnknown(&descriptorpb.EnumValueDescriptorProto{}, fixed),
+			want: `{"$unknown":{"30":[7],"31":[8]}}`,
+		},
+		{
+			name: "group",
+			v:    withUnknown(&descriptorpb.EnumValueDescriptorProto{}, group),
+			want: `{"$unknown":{"40":[{"1":[3]}]}}`,
+		},
+		{
+			name: "nested",
+			v: &descriptorpb.EnumDescriptorProto{
+				Name: proto.String("Color"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("RED")},
+					withUnknown(&descriptorpb.EnumValueDescriptorProto{Name: proto.String("GREEN")}, fixed),
+				},
+				Options: withUnknown(&descriptorpb.EnumOptions{}, group),
+			},
+			want: `{"name":"Color","value":[{"name":"RED"},{"name":"GREEN","$unknown":{"30":[7],"31":[8]}}],"options":{"$unknown":{"40":[{"1":[3]}]}}}`,
+		},
+		{
+			name: "in a Go value",
+			v:    map[string]any{"m": withUnknown(&descriptorpb.EnumValueDescriptorProto{}, fixed)},
+			want: `{"m":{"$unknown":{"30":[7],"31":[8]}}}`,
+		},
+		{
+			name: "well-known type",
+			v:    withUnknown(&timestamppb.Timestamp{Seconds: 1}, fixed),
+			want: ``,
+		},
+		{
+			name: "proto names",
+			v:    withUnknown(&descriptorpb.FieldDescriptorProto{JsonName: proto.String("a")}, fixed),
+			opts: []jsonify.Option{jsonify.WithProtoOptions(protojson.MarshalOptions{UseProtoNames: true})},
+			want: `{"json_name":"a","$unknown":{"30":[7],"31":[8]}}`,
+		},
+		{
+			name: "no unknown fields",
+			v:    &descriptorpb.EnumValueDescriptorProto{Name: proto.String("RED")},
+			want: `{"name":"RED"}`,
+		},
+		{
+			name:    "invalid",
+			v:       withUnknown(&descriptorpb.EnumValueDescriptorProto{}, invalid),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append(tt.opts, jsonify.WithProtoUnknownFields("$unknown"))
+			got, err := jsonify.String(tt.v, opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("String() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				// The message is encoded as without the option.
+				tt.want, _ = jsonify.String(tt.v, tt.opts...)
+			}
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
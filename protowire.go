@@ -0,0 +1,50 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtoWireToJSON decodes data, a message of the type desc in the protobuf
+// binary format, and encodes it with [Bytes] and opts, for tools that receive
+// raw messages, e.g. from a queue or a packet capture:
+//
+//	d, err := jsonify.LoadDescriptorSet(set)
+//	desc, err := d.Types().FindMessageByName("example.User")
+//	b, err := jsonify.ProtoWireToJSON(data, desc.Descriptor(), jsonify.WithProtoResolver(d.Types()))
+//
+// Fields unknown to desc are dropped, as protojson does, and
+// google.protobuf.Any messages are expanded with the resolver set by
+// [WithProtoResolver], if any.
+func ProtoWireToJSON(data []byte, desc protoreflect.MessageDescriptor, opts ...Option) ([]byte, error) {
+	m := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("jsonify: decoding %s: %w", desc.FullName(), err)
+	}
+	return Bytes(m, opts...)
+}
+
+// JSONToProtoWire decodes data, a message of the type desc in JSON, with
+// [Decode] and opts, and encodes it in the protobuf binary format, e.g. to
+// turn fixtures written as JSON into the messages a test replays. The output
+// is deterministic: map entries are sorted by key.
+//
+// google.protobuf.Any messages are resolved with the types linked into the
+// program; use [Descriptors.Decode] and [proto.Marshal] for those of loaded
+// types.
+func JSONToProtoWire(data []byte, desc protoreflect.MessageDescriptor, opts ...DecodeOption) ([]byte, error) {
+	m := dynamicpb.NewMessage(desc)
+	if err := Decode(data, m, opts...); err != nil {
+		return nil, err
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("jsonify: encoding %s: %w", desc.FullName(), err)
+	}
+	return b, nil
+}
@@ -0,0 +1,133 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ExampleProtoWireToJSON() {
+	data, _ := proto.Marshal(timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	b, err := jsonify.ProtoWireToJSON(data, (&timestamppb.Timestamp{}).ProtoReflect().Descriptor())
+	fmt.Println(string(b), err)
+	// Output:
+	// "2024-01-02T03:04:05Z" <nil>
+}
+
+func TestProtoWireToJSON(t *testing.T) {
+	d, err := jsonify.LoadDescriptorSet(userDescriptorSet("google/protobuf/timestamp.proto", "google/protobuf/any.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := d.Decode("example.User", []byte(`{
+		"name": "Ann",
+		"created": "2024-01-02T03:04:05Z",
+		"manager": {"@type": "type.googleapis.com/example.User", "name": "Bob"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameOnly, err := jsonify.JSONToProtoWire([]byte(`{"name":"Ann"}`), m.Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := m.Descriptor()
+
+	tests := []struct {
+		name    string
+		data    []byte
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "message",
+			data: data,
+			opts: []jsonify.Option{jsonify.WithProtoResolver(d.Types())},
+			want: `{"name":"Ann","created":"2024-01-02T03:04:05Z","manager":{"@type":"type.googleapis.com/example.User","name":"Bob"}}`,
+		},
+		{
+			name: "indent",
+			data: nameOnly,
+			opts: []jsonify.Option{jsonify.WithIndent("", " ")},
+			want: "{\n \"name\": \"Ann\"\n}",
+		},
+		{name: "empty", data: nil, want: `{}`},
+		{name: "unknown field", data: []byte{0x78, 0x01}, want: `{}`},
+		{name: "truncated", data: nameOnly[:3], wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.ProtoWireToJSON(tt.data, desc, tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ProtoWireToJSON() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ProtoWireToJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONToProtoWire(t *testing.T) {
+	d, err := jsonify.LoadDescriptorSet(userDescriptorSet("google/protobuf/timestamp.proto", "google/protobuf/any.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := d.New("example.User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := m.Descriptor()
+
+	tests := []struct {
+		name    string
+		json    string
+		opts    []jsonify.DecodeOption
+		want    string
+		wantErr bool
+	}{
+		{name: "message", json: `{"name":"Ann","created":"2024-01-02T03:04:05Z"}`, want: `{"name":"Ann","created":"2024-01-02T03:04:05Z"}`},
+		{name: "empty", json: `{}`, want: `{}`},
+		{name: "unknown field", json: `{"name":"Ann","age":3}`, want: `{"name":"Ann"}`},
+		{name: "disallowed unknown field", json: `{"age":3}`, opts: []jsonify.DecodeOption{jsonify.WithDisallowUnknownFields()}, wantErr: true},
+		{name: "invalid", json: `{"name":1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := jsonify.JSONToProtoWire([]byte(tt.json), desc, tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("JSONToProtoWire() = %x, want error", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := jsonify.ProtoWireToJSON(data, desc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ProtoWireToJSON(JSONToProtoWire()) = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Range is an interval between two values of T, such as times or numbers.
+//
+// It encodes as an object with the bounds notation of the interval:
+//
+//	{"start":"2024-01-01T00:00:00Z","end":"2024-02-01T00:00:00Z","bounds":"[)"}
+//
+// The bounds are "[" or "(" for an inclusive or exclusive start, followed by
+// "]" or ")" for an inclusive or exclusive end.
+//
+// Encoding and decoding fail if Start is after End. Values are compared with
+// their Compare method if T has one (e.g. [time.Time]), or with the natural
+// order of integers, floats and strings. Other types are not compared.
+// Decoding null leaves a Range unchanged.
+type Range[T any] struct {
+	Start          T
+	End            T
+	StartInclusive bool
+	EndInclusive   bool
+}
+
+type rangeJSON struct {
+	Start  json.RawMessage `json:"start"`
+	End    json.RawMessage `json:"end"`
+	Bounds string          `json:"bounds"`
+}
+
+// Validate reports whether r.Start is not after r.End.
+func (r Range[T]) Validate() error {
+	if c, ok := compareValues(r.Start, r.End); ok && c > 0 {
+		return fmt.Errorf("jsonify: range start %v is after end %v", r.Start, r.End)
+	}
+	return nil
+}
+
+// Bounds returns the bounds notation of r, e.g. "[)".
+func (r Range[T]) Bounds() string {
+	b := []byte("()")
+	if r.StartInclusive {
+		b[0] = '['
+	}
+	if r.EndInclusive {
+		b[1] = ']'
+	}
+	return string(b)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (r Range[T]) MarshalJSON() ([]byte, error) {
+	return r.marshalJSON(newOptions(nil))
+}
+
+func (r Range[T]) marshalJSON(o *options) ([]byte, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	start, err := o.marshal(r.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := o.marshal(r.End)
+	if err != nil {
+		return nil, err
+	}
+	b := append([]byte(`{"start":`), start...)
+	b = append(b, `,"end":`...)
+	b = append(b, end...)
+	b = append(b, `,"bounds":"`...)
+	b = append(b, r.Bounds()...)
+	return append(b, '"', '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (r *Range[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v rangeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Start) == 0 || len(v.End) == 0 {
+		return fmt.Errorf("jsonify: range requires both start and end")
+	}
+	if len(v.Bounds) != 2 || (v.Bounds[0] != '[' && v.Bounds[0] != '(') || (v.Bounds[1] != ']' && v.Bounds[1] != ')') {
+		return fmt.Errorf("jsonify: invalid range bounds %q", v.Bounds)
+	}
+	var out Range[T]
+	if err := json.Unmarshal(v.Start, &out.Start); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(v.End, &out.End); err != nil {
+		return err
+	}
+	out.StartInclusive = v.Bounds[0] == '['
+	out.EndInclusive = v.Bounds[1] == ']'
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*r = out
+	return nil
+}
+
+// compareValues compares a and b, reporting false if T has no known order.
+func compareValues[T any](a, b T) (int, bool) {
+	if c, ok := any(a).(interface{ Compare(T) int }); ok {
+		return c.Compare(b), true
+	}
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(va.Int(), vb.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(va.Uint(), vb.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(va.Float(), vb.Float()), true
+	case reflect.String:
+		return compareOrdered(va.String(), vb.String()), true
+	}
+	return 0, false
+}
+
+func compareOrdered[T int64 | uint64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
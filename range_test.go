@@ -0,0 +1,81 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleRange() {
+	r := jsonify.Range[time.Time]{
+		Start:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:            time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		StartInclusive: true,
+	}
+	fmt.Println(jsonify.MustString(r))
+	// Output:
+	// {"start":"2024-01-01T00:00:00Z","end":"2024-02-01T00:00:00Z","bounds":"[)"}
+}
+
+func TestRange(t *testing.T) {
+	r := jsonify.Range[int]{Start: 1, End: 10, StartInclusive: true, EndInclusive: true}
+	got, err := jsonify.String(r)
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+	if expected := `{"start":1,"end":10,"bounds":"[]"}`; got != expected {
+		t.Errorf("String() = %v, want %v", got, expected)
+	}
+	var decoded jsonify.Range[int]
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded != r {
+		t.Errorf("round trip = %v, want %v", decoded, r)
+	}
+
+	if _, err := jsonify.Bytes(jsonify.Range[string]{Start: "b", End: "a"}); err == nil {
+		t.Errorf("Bytes() accepted a reversed range")
+	}
+}
+
+func TestRangeUnmarshalNull(t *testing.T) {
+	r := jsonify.Range[int]{Start: 1, End: 2}
+	if err := r.UnmarshalJSON([]byte("null")); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	var v struct {
+		R jsonify.Range[int] `json:"r"`
+	}
+	v.R = r
+	if err := jsonify.Decode([]byte(`{"r":null}`), &v); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if v.R != r {
+		t.Errorf("Decode() of null changed the range to %v", v.R)
+	}
+}
+
+func TestRangeUnmarshalInvalid(t *testing.T) {
+	inputs := []string{
+		`{"start":5,"end":1,"bounds":"[)"}`,
+		`{"start":1,"end":5,"bounds":"[["}`,
+		`{"start":1,"end":5}`,
+		`{"start":1,"bounds":"[)"}`,
+	}
+	for _, input := range inputs {
+		var r jsonify.Range[float64]
+		if err := json.Unmarshal([]byte(input), &r); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want error", input)
+		}
+	}
+
+	var r jsonify.Range[time.Time]
+	input := `{"start":"2024-02-01T00:00:00Z","end":"2024-01-01T00:00:00Z","bounds":"()"}`
+	if err := json.Unmarshal([]byte(input), &r); err == nil {
+		t.Errorf("Unmarshal(%s) error = nil, want error", input)
+	}
+}
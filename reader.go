@@ -0,0 +1,72 @@
+package jsonify
+
+import (
+	"io"
+	"sync"
+)
+
+// Reader returns a reader of the encoding of v with opts, which is produced
+// as it is read, e.g. to send a value as the body of a request without
+// encoding it up front:
+//
+//	req, err := http.NewRequest("POST", url, jsonify.Reader(payload))
+//
+// The value is encoded with [Encode], in chunks, by a goroutine started on
+// the first Read, so that large values need not fit in memory, and
+// compressed by [WithCompression]. Options that reformat the output, e.g.
+// [WithIndent], and the hook of [SetPostMarshalHook] make it encode the
+// value whole with [Bytes] instead. An error of encoding is returned by
+// Read, after the part of the output encoded before it.
+//
+// The reader also implements [io.Closer], which stops the encoding; it must
+// be read to the end or closed, as [http.Client] does with request bodies.
+func Reader(v any, opts ...Option) io.Reader {
+	return &reader{v: v, opts: opts}
+}
+
+type reader struct {
+	v    any
+	opts []Option
+
+	// mu guards r, which is set by the first Read or Close, as an
+	// [http.Client] may close a request body while reading it.
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	if r.r == nil {
+		r.start()
+	}
+	rd := r.r
+	r.mu.Unlock()
+	return rd.Read(p)
+}
+
+// start starts encoding the value.
+func (r *reader) start() {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encodeTo(pw, r.v, r.opts))
+	}()
+	r.r = pr
+}
+
+func (r *reader) Close() error {
+	r.mu.Lock()
+	if r.r == nil {
+		r.r = &errorReader{io.ErrClosedPipe}
+	}
+	c := r.r.(io.Closer)
+	r.mu.Unlock()
+	return c.Close()
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) { return 0, r.err }
+
+func (r *errorReader) Close() error { return nil }
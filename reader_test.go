@@ -0,0 +1,123 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleReader() {
+	body, err := io.ReadAll(jsonify.Reader(map[string]any{"name": "Ann", "tags": []string{"a"}}))
+	fmt.Println(string(body), err)
+	// Output:
+	// {"name":"Ann","tags":["a"]} <nil>
+}
+
+func TestReader(t *testing.T) {
+	big := make([]string, 50_000)
+	for i := range big {
+		big[i] = fmt.Sprint("item", i)
+	}
+	tests := []struct {
+		name string
+		v    any
+		opts []jsonify.Option
+	}{
+		{name: "scalar", v: 1},
+		{name: "large", v: map[string][]string{"items": big}},
+		{name: "indent", v: map[string]int{"a": 1}, opts: []jsonify.Option{jsonify.WithIndent("", " ")}},
+		{name: "fingerprint", v: struct{ A int }{1}, opts: []jsonify.Option{jsonify.WithFingerprint("$shape")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(jsonify.Reader(tt.v, tt.opts...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := jsonify.MustString(tt.v, tt.opts...); string(got) != want {
+				t.Errorf("Reader() read %d bytes, want %d", len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestReader_errors(t *testing.T) {
+	for _, opts := range [][]jsonify.Option{nil, {jsonify.WithIndent("", " ")}} {
+		if _, err := io.ReadAll(jsonify.Reader([]float64{1, math.NaN()}, opts...)); err == nil {
+			t.Errorf("Reader(NaN, %d options) error = nil, want error", len(opts))
+		}
+	}
+
+	r := jsonify.Reader(make([]int, 100_000))
+	if _, err := r.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(make([]byte, 10)); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Read() after Close error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestReader_closeTwice(t *testing.T) {
+	for _, read := range []bool{false, true} {
+		r := jsonify.Reader([]int{1, 2}).(io.ReadCloser)
+		if read {
+			if _, err := r.Read(make([]byte, 1)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := range 2 {
+			if err := r.Close(); err != nil {
+				t.Errorf("Close() #%d after read %v error = %v", i+1, read, err)
+			}
+		}
+		if _, err := r.Read(make([]byte, 1)); !errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("Read() after Close error = %v, want %v", err, io.ErrClosedPipe)
+		}
+	}
+}
+
+func TestReader_closeWhileReading(t *testing.T) {
+	r := jsonify.Reader(make([]int, 1<<16)).(io.ReadCloser)
+	done := make(chan error)
+	go func() {
+		_, err := io.Copy(io.Discard, r)
+		done <- err
+	}()
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := <-done; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Read() error = %v, want nil or %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestReader_request(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+	}))
+	defer server.Close()
+	req, err := http.NewRequest("POST", server.URL, jsonify.Reader(map[string]string{"q": strings.Repeat("x", 100_000)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := `{"q":"` + strings.Repeat("x", 100_000) + `"}`; got != want {
+		t.Errorf("server received %d bytes, want %d", len(got), len(want))
+	}
+}
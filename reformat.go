@@ -0,0 +1,273 @@
+package jsonify
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reformat copies the JSON values read from src to dst, minified, or
+// indented with [WithIndent], token by token, so that arbitrarily large
+// documents are reformatted in memory bounded by the nesting depth and the
+// longest number:
+//
+//	err := jsonify.Reformat(os.Stdout, os.Stdin, jsonify.WithIndent("", "  "))
+//
+// Values are indented as [json.Indent] does, and src may hold several of
+// them, e.g. JSON Lines, which are written one per line. Strings are copied
+// as they are, with their escapes. [WithTrailingNewline] ends the output
+// with a newline; other options that reformat the output, e.g.
+// [WithSmartIndent], need whole values and fail.
+//
+// Reformat fails for invalid JSON, with the offset of the error in src,
+// after writing the output before it.
+func Reformat(dst io.Writer, src io.Reader, opts ...Option) error {
+	o := newOptions(opts)
+	if o.format != nil && o.indent == nil || o.fingerprint != "" {
+		return errors.New("jsonify: Reformat supports WithIndent only")
+	}
+	rf := reformatter{r: bufio.NewReader(src), w: bufio.NewWriter(dst), indent: o.indent}
+	err := rf.run()
+	if err == nil && o.trailingNewline {
+		err = rf.w.WriteByte('\n')
+	}
+	if ferr := rf.w.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+type reformatter struct {
+	r      *bufio.Reader
+	w      *bufio.Writer
+	indent *indentOptions
+	off    int // The offset in src of the next byte.
+	num    []byte
+}
+
+func (rf *reformatter) run() error {
+	for n := 0; ; n++ {
+		c, err := rf.next()
+		if err == io.EOF {
+			if n == 0 {
+				return rf.errorf("no value")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			rf.w.WriteByte('\n')
+		}
+		if err := rf.value(c, 0); err != nil {
+			return err
+		}
+	}
+}
+
+func (rf *reformatter) errorf(format string, args ...any) error {
+	return fmt.Errorf("jsonify: Reformat: "+format+" at offset %d", append(args, rf.off)...)
+}
+
+func (rf *reformatter) readByte() (byte, error) {
+	c, err := rf.r.ReadByte()
+	if err == nil {
+		rf.off++
+	}
+	return c, err
+}
+
+// next returns the next byte that is not a space.
+func (rf *reformatter) next() (byte, error) {
+	for {
+		c, err := rf.readByte()
+		if err != nil || !isJSONSpace(c) {
+			return c, err
+		}
+	}
+}
+
+// must returns the next byte that is not a space, which must exist.
+func (rf *reformatter) must() (byte, error) {
+	c, err := rf.next()
+	if err == io.EOF {
+		return 0, rf.errorf("unexpected end of input")
+	}
+	return c, err
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// newline starts a line of the given nesting depth, if indenting.
+func (rf *reformatter) newline(depth int) {
+	if rf.indent == nil {
+		return
+	}
+	rf.w.WriteByte('\n')
+	rf.w.WriteString(rf.indent.prefix)
+	for range depth {
+		rf.w.WriteString(rf.indent.indent)
+	}
+}
+
+// value copies the value starting with c, at the given nesting depth.
+func (rf *reformatter) value(c byte, depth int) error {
+	switch {
+	case c == '{' || c == '[':
+		if depth >= DefaultMaxDepth {
+			return rf.errorf("exceeded max depth %d", DefaultMaxDepth)
+		}
+		return rf.container(c, depth)
+	case c == '"':
+		return rf.str()
+	case c == '-' || '0' <= c && c <= '9':
+		return rf.number(c)
+	case c == 't' || c == 'f' || c == 'n':
+		return rf.literal(c)
+	}
+	return rf.errorf("unexpected %q", c)
+}
+
+func (rf *reformatter) container(open byte, depth int) error {
+	end := byte(']')
+	if open == '{' {
+		end = '}'
+	}
+	rf.w.WriteByte(open)
+	c, err := rf.must()
+	if err != nil {
+		return err
+	}
+	if c == end {
+		return rf.w.WriteByte(end)
+	}
+	for {
+		rf.newline(depth + 1)
+		if open == '{' {
+			if c != '"' {
+				return rf.errorf("unexpected %q, want a key", c)
+			}
+			if err := rf.str(); err != nil {
+				return err
+			}
+			if c, err = rf.must(); err != nil {
+				return err
+			}
+			if c != ':' {
+				return rf.errorf("unexpected %q, want ':'", c)
+			}
+			rf.w.WriteByte(':')
+			if rf.indent != nil {
+				rf.w.WriteByte(' ')
+			}
+			if c, err = rf.must(); err != nil {
+				return err
+			}
+		}
+		if err := rf.value(c, depth+1); err != nil {
+			return err
+		}
+		if c, err = rf.must(); err != nil {
+			return err
+		}
+		switch c {
+		case ',':
+			rf.w.WriteByte(',')
+			if c, err = rf.must(); err != nil {
+				return err
+			}
+		case end:
+			rf.newline(depth)
+			return rf.w.WriteByte(end)
+		default:
+			return rf.errorf("unexpected %q, want ',' or %q", c, end)
+		}
+	}
+}
+
+// str copies a string, whose opening quote was read.
+func (rf *reformatter) str() error {
+	rf.w.WriteByte('"')
+	for {
+		c, err := rf.readByte()
+		if err == io.EOF {
+			return rf.errorf("unexpected end of input in string")
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case c == '"':
+			return rf.w.WriteByte(c)
+		case c < 0x20:
+			return rf.errorf("invalid character %q in string", c)
+		case c == '\\':
+			rf.w.WriteByte(c)
+			if c, err = rf.readByte(); err != nil {
+				return rf.errorf("unexpected end of input in string")
+			}
+			n := 0
+			switch c {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				n = 4
+			default:
+				return rf.errorf("invalid escape %q in string", c)
+			}
+			rf.w.WriteByte(c)
+			for range n {
+				if c, err = rf.readByte(); err != nil {
+					return rf.errorf("unexpected end of input in string")
+				}
+				if !strings.ContainsRune("0123456789abcdefABCDEF", rune(c)) {
+					return rf.errorf("invalid escape in string")
+				}
+				rf.w.WriteByte(c)
+			}
+		default:
+			rf.w.WriteByte(c)
+		}
+	}
+}
+
+// number copies a number starting with c.
+func (rf *reformatter) number(c byte) error {
+	rf.num = append(rf.num[:0], c)
+	for {
+		b, err := rf.r.Peek(1)
+		if err != nil || !isNumberByte(b[0]) {
+			break
+		}
+		c, _ := rf.readByte()
+		rf.num = append(rf.num, c)
+	}
+	if !json.Valid(rf.num) {
+		return rf.errorf("invalid number %q", rf.num)
+	}
+	_, err := rf.w.Write(rf.num)
+	return err
+}
+
+// literal copies true, false or null, starting with c.
+func (rf *reformatter) literal(c byte) error {
+	want := "null"
+	switch c {
+	case 't':
+		want = "true"
+	case 'f':
+		want = "false"
+	}
+	for i := 1; i < len(want); i++ {
+		if c, err := rf.readByte(); err != nil || c != want[i] {
+			return rf.errorf("invalid literal, want %s", want)
+		}
+	}
+	_, err := rf.w.WriteString(want)
+	return err
+}
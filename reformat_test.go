@@ -0,0 +1,118 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleReformat() {
+	src := strings.NewReader(`{"name": "api", "ports": [80, 443], "tags": {}}`)
+	jsonify.Reformat(os.Stdout, src, jsonify.WithIndent("", "  "), jsonify.WithTrailingNewline())
+	// Output:
+	// {
+	//   "name": "api",
+	//   "ports": [
+	//     80,
+	//     443
+	//   ],
+	//   "tags": {}
+	// }
+}
+
+func TestReformat(t *testing.T) {
+	docs := []string{
+		`{"a": [1, -2.5e+3, true, false, null, "x\"\\é\n"], "b": {}, "c": [], "d": [{}, [[]]]}`,
+		` [ ] `,
+		`"s"`,
+		`0`,
+		"{\"k\":\n\t{\"x\" :1 } }",
+	}
+	for _, doc := range docs {
+		for _, opts := range [][]jsonify.Option{nil, {jsonify.WithIndent("", "\t")}, {jsonify.WithIndent("> ", "--")}} {
+			var want bytes.Buffer
+			var err error
+			if len(opts) == 0 {
+				err = json.Compact(&want, []byte(doc))
+			} else {
+				b, _ := jsonify.Bytes(json.RawMessage(strings.TrimSpace(doc)), opts...)
+				want.Write(b)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			if err := jsonify.Reformat(&got, strings.NewReader(doc), opts...); err != nil {
+				t.Errorf("Reformat(%s) error = %v", doc, err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("Reformat(%s, %d options) = %q, want %q", doc, len(opts), got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestReformat_values(t *testing.T) {
+	var got bytes.Buffer
+	err := jsonify.Reformat(&got, strings.NewReader("{\"a\": 1}\n{\"a\": 2}\n\n3"), jsonify.WithTrailingNewline())
+	if want := "{\"a\":1}\n{\"a\":2}\n3\n"; err != nil || got.String() != want {
+		t.Errorf("Reformat() = %q, %v, want %q", got.String(), err, want)
+	}
+}
+
+func TestReformat_large(t *testing.T) {
+	// A reader of an array of n objects, generated as it is read.
+	const n = 100_000
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte("["))
+		for i := range n {
+			if i > 0 {
+				pw.Write([]byte(","))
+			}
+			pw.Write([]byte(`{"id": 1, "name": "item"}`))
+		}
+		pw.Write([]byte("]"))
+		pw.Close()
+	}()
+	var lines int
+	w := writerFunc(func(p []byte) (int, error) {
+		lines += bytes.Count(p, []byte("\n"))
+		return len(p), nil
+	})
+	if err := jsonify.Reformat(w, pr, jsonify.WithIndent("", " ")); err != nil {
+		t.Fatal(err)
+	}
+	if want := 1 + 4*n; lines != want {
+		t.Errorf("Reformat() wrote %d lines, want %d", lines, want)
+	}
+}
+
+// writerFunc adapts a function to an io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestReformat_errors(t *testing.T) {
+	inputs := []string{
+		``, `  `, `{`, `[1,]`, `{"a" 1}`, `{1: 2}`, `[1 2]`, `01`, `-`, `tru`, `nul1`, `"abc`, "\"a\tb\"",
+		`"\x"`, `"\u12g4"`, `{"a":1}}`, strings.Repeat("[", jsonify.DefaultMaxDepth+1),
+	}
+	for _, in := range inputs {
+		if err := jsonify.Reformat(io.Discard, strings.NewReader(in)); err == nil {
+			t.Errorf("Reformat(%q) error = nil, want error", in)
+		}
+	}
+	if err := jsonify.Reformat(io.Discard, strings.NewReader("1"), jsonify.WithSmartIndent(80)); err == nil {
+		t.Error("Reformat() with WithSmartIndent error = nil, want error")
+	}
+	if err := jsonify.Reformat(errorWriter{}, strings.NewReader("1")); !errors.Is(err, errWrite) {
+		t.Errorf("Reformat() error = %v, want %v", err, errWrite)
+	}
+}
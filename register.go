@@ -0,0 +1,61 @@
+package jsonify
+
+import (
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// RegisterTypeEncoder registers fn to encode values of type T, e.g. a decimal
+// or a custom ID type, everywhere jsonify encodes them.
+//
+// fn must return a single valid JSON value. It is used for values of exactly
+// type T, including values reached through pointers, slices, maps and struct
+// fields; it replaces any MarshalJSON method of T.
+//
+// Registering again for the same type replaces the previous encoder.
+// Registrations apply to the [Jsoniter] backend. They are meant to be made
+// during program initialization.
+func RegisterTypeEncoder[T any](fn func(v T) ([]byte, error)) {
+	register(reflect.TypeOf((*T)(nil)).Elem(), typeEncoder[T](fn), nil)
+}
+
+// RegisterTypeDecoder registers fn to decode values of type T in [Decode].
+//
+// fn is called with the raw JSON value, including null.
+//
+// Registering again for the same type replaces the previous decoder.
+// Registrations are meant to be made during program initialization.
+func RegisterTypeDecoder[T any](fn func(data []byte, v *T) error) {
+	register(reflect.TypeOf((*T)(nil)).Elem(), nil, typeDecoder[T](fn))
+}
+
+type typeEncoder[T any] func(v T) ([]byte, error)
+
+func (fn typeEncoder[T]) IsEmpty(ptr unsafe.Pointer) bool {
+	return reflect.NewAt(reflect.TypeOf((*T)(nil)).Elem(), ptr).Elem().IsZero()
+}
+
+func (fn typeEncoder[T]) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	b, err := fn(*(*T)(ptr))
+	if err != nil {
+		if stream.Error == nil {
+			stream.Error = err
+		}
+		return
+	}
+	stream.Write(b)
+}
+
+type typeDecoder[T any] func(data []byte, v *T) error
+
+func (fn typeDecoder[T]) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	data := iter.SkipAndReturnBytes()
+	if iter.Error != nil {
+		return
+	}
+	if err := fn(data, (*T)(ptr)); err != nil {
+		iter.Error = err
+	}
+}
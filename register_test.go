@@ -0,0 +1,92 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type userID uint64
+
+type cents int64
+
+func init() {
+	jsonify.RegisterTypeEncoder(func(id userID) ([]byte, error) {
+		return []byte(strconv.Quote("u-" + strconv.FormatUint(uint64(id), 10))), nil
+	})
+	jsonify.RegisterTypeDecoder(func(data []byte, id *userID) error {
+		s, err := strconv.Unquote(string(data))
+		if err != nil || !strings.HasPrefix(s, "u-") {
+			return fmt.Errorf("invalid user id %s", data)
+		}
+		n, err := strconv.ParseUint(s[2:], 10, 64)
+		*id = userID(n)
+		return err
+	})
+	jsonify.RegisterTypeEncoder(func(c cents) ([]byte, error) {
+		if c < 0 {
+			return nil, errors.New("negative cents")
+		}
+		return []byte(fmt.Sprintf(`"%d.%02d"`, c/100, c%100)), nil
+	})
+}
+
+func ExampleRegisterTypeEncoder() {
+	type SKU string
+	jsonify.RegisterTypeEncoder(func(s SKU) ([]byte, error) {
+		return []byte(strconv.Quote(strings.ToUpper(string(s)))), nil
+	})
+
+	fmt.Println(jsonify.MustString(map[string]SKU{"item": "ab-12"}))
+	// Output:
+	// {"item":"AB-12"}
+}
+
+func TestRegisterTypeEncoder(t *testing.T) {
+	type order struct {
+		User  userID  `json:"user"`
+		Owner *userID `json:"owner,omitempty"`
+		Total cents   `json:"total"`
+	}
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "value", input: userID(7), expected: `"u-7"`},
+		{name: "struct", input: order{User: 1, Total: 1234}, expected: `{"user":"u-1","total":"12.34"}`},
+		{name: "slice", input: []userID{1, 2}, expected: `["u-1","u-2"]`},
+		{name: "error", input: order{Total: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterTypeDecoder(t *testing.T) {
+	var v struct {
+		IDs []userID
+	}
+	if err := jsonify.Decode([]byte(`{"IDs":["u-1","u-42"]}`), &v); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(v.IDs) != 2 || v.IDs[0] != 1 || v.IDs[1] != 42 {
+		t.Errorf("Decode() = %v", v.IDs)
+	}
+	if err := jsonify.Decode([]byte(`{"IDs":[42]}`), &v); err == nil {
+		t.Errorf("Decode() error = nil, want error")
+	}
+}
@@ -0,0 +1,323 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RepairFix describes a change made by [RepairReport].
+type RepairFix struct {
+	Offset  int    // byte offset in the input
+	Message string // e.g. "quoted key"
+}
+
+func (f RepairFix) String() string {
+	return fmt.Sprintf("offset %d: %s", f.Offset, f.Message)
+}
+
+// Repair returns data, JSON-ish input such as third-party logs, repaired to
+// valid JSON on a best-effort basis, as [RepairReport] does, without the
+// report.
+func Repair(data []byte) ([]byte, error) {
+	b, _, err := RepairReport(data)
+	return b, err
+}
+
+// RepairReport returns data repaired to valid JSON, and the fixes it made,
+// in order. It fixes these common breakages:
+//
+//   - unquoted keys, e.g. {id: 1}, are quoted
+//   - single-quoted strings are double-quoted
+//   - trailing commas are removed, and missing ones between values added
+//   - comments are removed
+//   - control characters and invalid escapes in strings are escaped
+//   - True, False and None, as printed by Python, become JSON literals
+//   - truncated documents are closed at the last valid point: a value cut
+//     short is dropped, with its key, and open objects and arrays are
+//     closed
+//
+// Valid JSON is returned unchanged, without fixes. It fails for input it
+// cannot make sense of, e.g. a truncated scalar, or text after the value.
+func RepairReport(data []byte) ([]byte, []RepairFix, error) {
+	if json.Valid(data) {
+		return data, nil, nil
+	}
+	r := repairer{data: data}
+	if err := r.value(0); err != nil {
+		if errors.Is(err, errIncomplete) {
+			err = fmt.Errorf("jsonify: Repair: truncated value at offset %d", r.pos)
+		}
+		return nil, r.fixes, err
+	}
+	if r.skip(); r.pos < len(data) {
+		return nil, r.fixes, r.errorf("unexpected %q after the value", data[r.pos])
+	}
+	if !json.Valid(r.out) {
+		return nil, r.fixes, errors.New("jsonify: Repair: failed to repair the input")
+	}
+	return r.out, r.fixes, nil
+}
+
+// errIncomplete is returned for a value that the end of the input cut
+// short.
+var errIncomplete = errors.New("incomplete value")
+
+type repairer struct {
+	data  []byte
+	pos   int
+	out   []byte
+	fixes []RepairFix
+}
+
+func (r *repairer) fix(offset int, message string) {
+	r.fixes = append(r.fixes, RepairFix{Offset: offset, Message: message})
+}
+
+func (r *repairer) errorf(format string, args ...any) error {
+	return fmt.Errorf("jsonify: Repair: "+format+" at offset %d", append(args, r.pos)...)
+}
+
+// skip skips spaces and comments.
+func (r *repairer) skip() {
+	for r.pos < len(r.data) {
+		switch c := r.data[r.pos]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			r.pos++
+		case bytes.HasPrefix(r.data[r.pos:], []byte("//")):
+			r.fix(r.pos, "removed comment")
+			for r.pos < len(r.data) && r.data[r.pos] != '\n' {
+				r.pos++
+			}
+		case bytes.HasPrefix(r.data[r.pos:], []byte("/*")):
+			r.fix(r.pos, "removed comment")
+			end := bytes.Index(r.data[r.pos+2:], []byte("*/"))
+			if end < 0 {
+				r.pos = len(r.data)
+			} else {
+				r.pos += end + 4
+			}
+		default:
+			return
+		}
+	}
+}
+
+// value appends the next value, at the given nesting depth, to r.out.
+func (r *repairer) value(depth int) error {
+	if depth >= DefaultMaxDepth {
+		return r.errorf("exceeded max depth %d", DefaultMaxDepth)
+	}
+	r.skip()
+	if r.pos == len(r.data) {
+		return errIncomplete
+	}
+	switch c := r.data[r.pos]; {
+	case c == '{' || c == '[':
+		return r.container(depth)
+	case c == '"' || c == '\'':
+		return r.str()
+	case c == '-' || '0' <= c && c <= '9':
+		return r.number()
+	case isRepairIdent(c):
+		return r.literal()
+	default:
+		return r.errorf("unexpected %q", c)
+	}
+}
+
+// container appends the object or array at r.pos to r.out.
+func (r *repairer) container(depth int) error {
+	open := r.data[r.pos]
+	end, kind := byte(']'), "array"
+	if open == '{' {
+		end, kind = '}', "object"
+	}
+	r.out = append(r.out, open)
+	r.pos++
+	for n := 0; ; n++ {
+		r.skip()
+		if r.pos == len(r.data) {
+			r.fix(r.pos, "closed "+kind)
+			r.out = append(r.out, end)
+			return nil
+		}
+		switch c := r.data[r.pos]; {
+		case c == end:
+			r.pos++
+			r.out = append(r.out, end)
+			return nil
+		case n > 0 && c == ',':
+			comma := r.pos
+			r.pos++
+			if r.skip(); r.pos == len(r.data) || r.data[r.pos] == end {
+				r.fix(comma, "removed trailing comma")
+				continue
+			}
+		case n > 0:
+			r.fix(r.pos, "added missing comma")
+		}
+		mark := len(r.out)
+		if n > 0 {
+			r.out = append(r.out, ',')
+		}
+		err := r.member(open, depth)
+		if errors.Is(err, errIncomplete) {
+			r.pos = len(r.data)
+			r.fix(r.pos, "dropped truncated value")
+			r.out = r.out[:mark]
+			continue // To close the container.
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// member appends the next member of an object, or element of an array.
+func (r *repairer) member(open byte, depth int) error {
+	if open == '[' {
+		return r.value(depth + 1)
+	}
+	r.skip()
+	if r.pos == len(r.data) {
+		return errIncomplete
+	}
+	switch c := r.data[r.pos]; {
+	case c == '"' || c == '\'':
+		if err := r.str(); err != nil {
+			return err
+		}
+	case isRepairIdent(c):
+		r.fix(r.pos, "quoted key")
+		start := r.pos
+		for r.pos < len(r.data) && isRepairIdent(r.data[r.pos]) {
+			r.pos++
+		}
+		r.out = appendString(r.out, string(r.data[start:r.pos]))
+	default:
+		return r.errorf("unexpected %q, want a key", c)
+	}
+	r.skip()
+	if r.pos == len(r.data) {
+		return errIncomplete
+	}
+	if r.data[r.pos] != ':' {
+		return r.errorf("unexpected %q, want ':'", r.data[r.pos])
+	}
+	r.pos++
+	r.out = append(r.out, ':')
+	return r.value(depth + 1)
+}
+
+// str appends the double- or single-quoted string at r.pos to r.out.
+func (r *repairer) str() error {
+	quote := r.data[r.pos]
+	if quote == '\'' {
+		r.fix(r.pos, "double-quoted string")
+	}
+	r.out = append(r.out, '"')
+	r.pos++
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		switch {
+		case c == quote:
+			r.pos++
+			r.out = append(r.out, '"')
+			return nil
+		case c == '"':
+			r.out = append(r.out, `\"`...)
+		case c < 0x20:
+			r.fix(r.pos, "escaped control character")
+			r.out = fmt.Appendf(r.out, `\u%04x`, c)
+		case c == '\\':
+			if r.pos+1 == len(r.data) {
+				return errIncomplete
+			}
+			switch e := r.data[r.pos+1]; e {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				r.out = append(r.out, c, e)
+				r.pos++
+			case 'u':
+				// The input may end within the four hex digits.
+				hex := r.data[r.pos+2 : min(r.pos+6, len(r.data))]
+				switch {
+				case strings.Trim(string(hex), "0123456789abcdefABCDEF") != "":
+					r.fix(r.pos, "escaped backslash")
+					r.out = append(r.out, `\\`...)
+				case len(hex) < 4:
+					return errIncomplete
+				default:
+					r.out = append(r.out, r.data[r.pos:r.pos+6]...)
+					r.pos += 5
+				}
+			case '\'':
+				r.out = append(r.out, '\'')
+				r.pos++
+			default:
+				r.fix(r.pos, "escaped backslash")
+				r.out = append(r.out, `\\`...)
+			}
+		default:
+			r.out = append(r.out, c)
+		}
+		r.pos++
+	}
+	return errIncomplete
+}
+
+// number appends the number at r.pos to r.out.
+func (r *repairer) number() error {
+	start := r.pos
+	for r.pos < len(r.data) && strings.IndexByte("+-0123456789.eE", r.data[r.pos]) >= 0 {
+		r.pos++
+	}
+	num := r.data[start:r.pos]
+	if !json.Valid(num) {
+		if r.pos == len(r.data) {
+			return errIncomplete
+		}
+		r.pos = start
+		return r.errorf("invalid number %q", num)
+	}
+	r.out = append(r.out, num...)
+	return nil
+}
+
+// literal appends the literal at r.pos to r.out, with those of Python
+// replaced.
+func (r *repairer) literal() error {
+	start := r.pos
+	for r.pos < len(r.data) && isRepairIdent(r.data[r.pos]) {
+		r.pos++
+	}
+	word := string(r.data[start:r.pos])
+	switch word {
+	case "true", "false", "null":
+	case "True", "False":
+		r.fix(start, "replaced "+word)
+		word = strings.ToLower(word)
+	case "None":
+		r.fix(start, "replaced None")
+		word = "null"
+	default:
+		if r.pos == len(r.data) {
+			for _, lit := range []string{"true", "false", "null", "True", "False", "None"} {
+				if strings.HasPrefix(lit, word) {
+					return errIncomplete
+				}
+			}
+		}
+		r.pos = start
+		return r.errorf("unexpected %q", word)
+	}
+	r.out = append(r.out, word...)
+	return nil
+}
+
+// isRepairIdent reports whether c can be part of an unquoted key.
+func isRepairIdent(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '$'
+}
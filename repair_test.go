@@ -0,0 +1,92 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleRepairReport() {
+	b, fixes, err := jsonify.RepairReport([]byte(`{id: 1, 'name': 'Ann', "tags": ["a",], "next": {"x": tr`))
+	fmt.Println(string(b), err)
+	for _, fix := range fixes {
+		fmt.Println(fix)
+	}
+	// Output:
+	// {"id":1,"name":"Ann","tags":["a"],"next":{}} <nil>
+	// offset 1: quoted key
+	// offset 8: double-quoted string
+	// offset 16: double-quoted string
+	// offset 35: removed trailing comma
+	// offset 55: dropped truncated value
+	// offset 55: closed object
+	// offset 55: closed object
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      string
+		wantFixes []string
+		wantErr   bool
+	}{
+		{name: "valid", data: ` {"a": [1, 2]} `, want: ` {"a": [1, 2]} `},
+		{name: "unquoted keys", data: `{a_1: 1, $b: 2}`, want: `{"a_1":1,"$b":2}`, wantFixes: []string{"quoted key", "quoted key"}},
+		{
+			name:      "single quotes",
+			data:      `['it\'s', 'say "hi"', "\u00e9"]`,
+			want:      `["it's","say \"hi\"","\u00e9"]`,
+			wantFixes: []string{"double-quoted string", "double-quoted string"},
+		},
+		{name: "trailing commas", data: `{"a": [1, 2, ], }`, want: `{"a":[1,2]}`, wantFixes: []string{"removed trailing comma", "removed trailing comma"}},
+		{name: "missing commas", data: "[1 2\n\"x\"]", want: `[1,2,"x"]`, wantFixes: []string{"added missing comma", "added missing comma"}},
+		{name: "comments", data: "[1, // one\n /* two */ 2]", want: `[1,2]`, wantFixes: []string{"removed comment", "removed comment"}},
+		{name: "control characters", data: "[\"a\tb\"]", want: `["a\u0009b"]`, wantFixes: []string{"escaped control character"}},
+		{name: "invalid escapes", data: `["C:\dir", "\u12x"]`, want: `["C:\\dir","\\u12x"]`, wantFixes: []string{"escaped backslash", "escaped backslash"}},
+		{name: "Python literals", data: `[True, False, None]`, want: `[true,false,null]`, wantFixes: []string{"replaced True", "replaced False", "replaced None"}},
+		{name: "truncated array", data: `[1, 2`, want: `[1,2]`, wantFixes: []string{"closed array"}},
+		{name: "truncated after comma", data: `[1,`, want: `[1]`, wantFixes: []string{"removed trailing comma", "closed array"}},
+		{name: "truncated number", data: `[1, 2.`, want: `[1]`, wantFixes: []string{"dropped truncated value", "closed array"}},
+		{name: "truncated string", data: `{"a": 1, "b": "hel`, want: `{"a":1}`, wantFixes: []string{"dropped truncated value", "closed object"}},
+		{name: "truncated escape", data: `["a\u00`, want: `[]`, wantFixes: []string{"dropped truncated value", "closed array"}},
+		{name: "truncated key", data: `{"a": 1, "b`, want: `{"a":1}`, wantFixes: []string{"dropped truncated value", "closed object"}},
+		{name: "truncated colon", data: `{"a": 1, "b"`, want: `{"a":1}`, wantFixes: []string{"dropped truncated value", "closed object"}},
+		{name: "truncated literal", data: `[nu`, want: `[]`, wantFixes: []string{"dropped truncated value", "closed array"}},
+		{name: "truncated comment", data: `[1 /* x`, want: `[1]`, wantFixes: []string{"removed comment", "closed array"}},
+		{name: "nested", data: `{"a": [{"b": [1`, want: `{"a":[{"b":[1]}]}`, wantFixes: []string{"closed array", "closed object", "closed array", "closed object"}},
+		{name: "truncated scalar", data: `"abc`, wantErr: true},
+		{name: "empty", data: ``, wantErr: true},
+		{name: "text after", data: `{} x`, wantErr: true},
+		{name: "unknown word", data: `[undefined]`, wantErr: true},
+		{name: "invalid number", data: `[1.2.3]`, wantErr: true},
+		{name: "missing colon", data: `{"a" 1}`, wantErr: true},
+		{name: "invalid key", data: `{[1]: 1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := jsonify.RepairReport([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RepairReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("RepairReport() = %s, want %s", got, tt.want)
+			}
+			var messages []string
+			for _, fix := range fixes {
+				messages = append(messages, fix.Message)
+			}
+			if !slices.Equal(messages, tt.wantFixes) {
+				t.Errorf("RepairReport() fixes = %q, want %q", messages, tt.wantFixes)
+			}
+			if b, err := jsonify.Repair([]byte(tt.data)); err != nil || string(b) != tt.want {
+				t.Errorf("Repair() = %s, %v, want %s", b, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package jsonify
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// responseFormat is a media type [Respond] can encode values as.
+type responseFormat struct {
+	mediaType string
+	encode    func(v any, opts []Option) ([]byte, error)
+
+	// sequences reports whether only sequences are encoded as mediaType.
+	sequences bool
+}
+
+// responseFormats are the media types of [Respond], in order of preference
+// when the Accept header allows several equally.
+var responseFormats = []responseFormat{
+	{mediaType: "application/json", encode: func(v any, opts []Option) ([]byte, error) { return Bytes(v, opts...) }},
+	{mediaType: "application/x-ndjson", encode: ndjsonBytes, sequences: true},
+	{mediaType: "application/msgpack", encode: func(v any, opts []Option) ([]byte, error) { return MsgpackBytes(v, opts...) }},
+	{mediaType: "application/x-msgpack", encode: func(v any, opts []Option) ([]byte, error) { return MsgpackBytes(v, opts...) }},
+	{mediaType: "application/cbor", encode: func(v any, opts []Option) ([]byte, error) { return CBORBytes(v, opts...) }},
+}
+
+func ndjsonBytes(v any, opts []Option) ([]byte, error) {
+	var buf bytes.Buffer
+	err := Lines(&buf, v, opts...)
+	return buf.Bytes(), err
+}
+
+// Respond writes v as the body of an HTTP response to r with status, like
+// [WriteJSON], in the format the Accept header of r prefers, so that one
+// handler serves clients wanting different formats:
+//
+//	users, err := s.users.List(r.Context())
+//	...
+//	if err := jsonify.Respond(w, r, http.StatusOK, users); err != nil {
+//		s.logger.Error("writing response", "error", err)
+//	}
+//
+// The formats are:
+//
+//   - application/json, with [Bytes] and opts, which is the default when r
+//     has no Accept header or allows any type;
+//   - application/x-ndjson, with [Lines] and opts, if v is a slice, an
+//     array or an [iter.Seq];
+//   - application/msgpack, or application/x-msgpack, with [MsgpackBytes]
+//     and opts;
+//   - application/cbor, with [CBORBytes] and opts.
+//
+// The quality values of the Accept header are honored, and types it allows
+// equally are preferred in the order above. The response has a Vary: Accept
+// header. If the Accept header allows none of the formats, the response has
+// status 406 and a structured JSON error body, and Respond returns a
+// [*RequestError]. Otherwise, the errors are those of [WriteJSON], and a v
+// that cannot be encoded is answered with status 500 as with WriteJSON.
+func Respond(w http.ResponseWriter, r *http.Request, status int, v any, opts ...Option) error {
+	w.Header().Add("Vary", "Accept")
+	format, ok := negotiate(r.Header.Values("Accept"), isSequence(v))
+	if !ok {
+		err := &RequestError{
+			Status:  http.StatusNotAcceptable,
+			Message: "Accept must allow application/json",
+		}
+		writeError(w, err.Status, err.Message)
+		return err
+	}
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return nil
+	}
+	b, err := format.encode(v, opts)
+	if err != nil {
+		writeJSONBody(w, http.StatusInternalServerError, []byte(encodeErrorBody))
+		return err
+	}
+	return writeBody(w, status, format.mediaType, b)
+}
+
+// negotiate returns the response format the Accept header values accept
+// prefer, among those for sequences too if sequence is true. It reports
+// false if accept allows none. Invalid media ranges are ignored, and accept
+// without valid ones allows JSON.
+func negotiate(accept []string, sequence bool) (responseFormat, bool) {
+	var ranges []mediaRange
+	for _, value := range accept {
+		for _, s := range strings.Split(value, ",") {
+			if rg, ok := parseMediaRange(s); ok {
+				ranges = append(ranges, rg)
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return responseFormats[0], true
+	}
+	best, bestQ := -1, 0.0
+	for i, f := range responseFormats {
+		if f.sequences && !sequence {
+			continue
+		}
+		if q := quality(ranges, f.mediaType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return responseFormat{}, false
+	}
+	return responseFormats[best], true
+}
+
+// mediaRange is a media range of an Accept header, e.g. application/*;q=0.5.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func parseMediaRange(s string) (mediaRange, bool) {
+	if strings.TrimSpace(s) == "" {
+		return mediaRange{}, false
+	}
+	mediaType, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return mediaRange{}, false
+	}
+	typ, subtype, ok := strings.Cut(mediaType, "/")
+	if !ok {
+		return mediaRange{}, false
+	}
+	rg := mediaRange{typ: typ, subtype: subtype, q: 1}
+	if q, ok := params["q"]; ok {
+		if rg.q, err = strconv.ParseFloat(q, 64); err != nil || rg.q < 0 || rg.q > 1 {
+			return mediaRange{}, false
+		}
+	}
+	return rg, true
+}
+
+// quality returns the quality value of mediaType given by the most specific
+// of ranges that matches it, or 0 if none does.
+func quality(ranges []mediaRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, rg := range ranges {
+		var s int
+		switch {
+		case rg.typ == typ && rg.subtype == subtype:
+			s = 2
+		case rg.typ == typ && rg.subtype == "*":
+			s = 1
+		case rg.typ == "*" && rg.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = rg.q, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,108 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleRespond() {
+	users := []map[string]any{{"id": 1, "name": "Ann"}, {"id": 2, "name": "Bob"}}
+	for _, accept := range []string{"", "application/x-ndjson", "application/cbor, application/json;q=0.5"} {
+		r := httptest.NewRequest("GET", "/users", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		jsonify.Respond(rec, r, http.StatusOK, users)
+		fmt.Printf("%s %q\n", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	// Output:
+	// application/json "[{\"id\":1,\"name\":\"Ann\"},{\"id\":2,\"name\":\"Bob\"}]"
+	// application/x-ndjson "{\"id\":1,\"name\":\"Ann\"}\n{\"id\":2,\"name\":\"Bob\"}\n"
+	// application/cbor "\x82\xa2bid\x01dnamecAnn\xa2bid\x02dnamecBob"
+}
+
+func TestRespond(t *testing.T) {
+	list := []int{1, 2}
+	object := map[string]int{"a": 1}
+	msgpack, err := jsonify.MsgpackBytes(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cbor, err := jsonify.CBORBytes(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name            string
+		accept          []string
+		v               any
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "no accept", v: object, wantStatus: 200, wantContentType: "application/json", wantBody: `{"a":1}`},
+		{name: "any", accept: []string{"*/*"}, v: object, wantStatus: 200, wantContentType: "application/json", wantBody: `{"a":1}`},
+		{name: "application any", accept: []string{"application/*"}, v: list, wantStatus: 200, wantContentType: "application/json", wantBody: `[1,2]`},
+		{name: "invalid", accept: []string{"nonsense"}, v: object, wantStatus: 200, wantContentType: "application/json", wantBody: `{"a":1}`},
+		{name: "browser", accept: []string{"text/html,application/xhtml+xml,*/*;q=0.8"}, v: object, wantStatus: 200, wantContentType: "application/json", wantBody: `{"a":1}`},
+		{name: "ndjson", accept: []string{"application/x-ndjson"}, v: list, wantStatus: 200, wantContentType: "application/x-ndjson", wantBody: "1\n2\n"},
+		{name: "ndjson of object", accept: []string{"application/x-ndjson"}, v: object, wantStatus: 406, wantContentType: "application/json", wantBody: `{"error":{"code":406,"message":"Accept must allow application/json"}}`},
+		{name: "ndjson or json", accept: []string{"application/x-ndjson, application/json;q=0.9"}, v: object, wantStatus: 200, wantContentType: "application/json", wantBody: `{"a":1}`},
+		{name: "msgpack", accept: []string{"application/msgpack"}, v: object, wantStatus: 200, wantContentType: "application/msgpack", wantBody: string(msgpack)},
+		{name: "x-msgpack", accept: []string{"application/x-msgpack"}, v: object, wantStatus: 200, wantContentType: "application/x-msgpack", wantBody: string(msgpack)},
+		{name: "cbor", accept: []string{"application/cbor"}, v: object, wantStatus: 200, wantContentType: "application/cbor", wantBody: string(cbor)},
+		{name: "quality", accept: []string{"application/json;q=0.2, application/cbor;q=0.7"}, v: object, wantStatus: 200, wantContentType: "application/cbor", wantBody: string(cbor)},
+		{name: "several headers", accept: []string{"text/plain", "application/cbor"}, v: object, wantStatus: 200, wantContentType: "application/cbor", wantBody: string(cbor)},
+		{name: "excluded", accept: []string{"*/*, application/json;q=0"}, v: object, wantStatus: 200, wantContentType: "application/msgpack", wantBody: string(msgpack)},
+		{name: "not acceptable", accept: []string{"text/html"}, v: object, wantStatus: 406, wantContentType: "application/json", wantBody: `{"error":{"code":406,"message":"Accept must allow application/json"}}`},
+		{name: "encode error", accept: []string{"application/cbor"}, v: make(chan int), wantStatus: 500, wantContentType: "application/json", wantBody: `{"error":{"code":500,"message":"failed to encode the response"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for _, accept := range tt.accept {
+				r.Header.Add("Accept", accept)
+			}
+			rec := httptest.NewRecorder()
+			err := jsonify.Respond(rec, r, http.StatusOK, tt.v)
+			if (err != nil) != (tt.wantStatus != 200) {
+				t.Errorf("Respond() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus || rec.Header().Get("Content-Type") != tt.wantContentType || !bytes.Equal(rec.Body.Bytes(), []byte(tt.wantBody)) {
+				t.Errorf("Respond() wrote %d %s %q, want %d %s %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String(), tt.wantStatus, tt.wantContentType, tt.wantBody)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+		})
+	}
+}
+
+func TestRespond_notAcceptable(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "image/png")
+	err := jsonify.Respond(httptest.NewRecorder(), r, http.StatusOK, 1)
+	var re *jsonify.RequestError
+	if !errors.As(err, &re) || re.Status != http.StatusNotAcceptable {
+		t.Errorf("Respond() error = %v, want a 406 RequestError", err)
+	}
+}
+
+func TestRespond_noContent(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "application/cbor")
+	rec := httptest.NewRecorder()
+	if err := jsonify.Respond(rec, r, http.StatusNoContent, nil); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+		t.Errorf("Respond() wrote %d %q with Content-Type %q", rec.Code, rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+}
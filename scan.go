@@ -0,0 +1,338 @@
+package jsonify
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// DefaultMaxDepth is the nesting depth of objects and arrays [Scan] accepts
+// unless [WithMaxDepth] is given, the same as the limit of encoding/json.
+const DefaultMaxDepth = 10000
+
+// ScanOption configures [Scan].
+type ScanOption func(*scanOptions)
+
+type scanOptions struct {
+	maxDepth        int
+	allowDuplicates bool
+}
+
+// WithMaxDepth returns a [ScanOption] that limits the nesting depth of
+// objects and arrays to n.
+func WithMaxDepth(n int) ScanOption {
+	return func(o *scanOptions) {
+		o.maxDepth = n
+	}
+}
+
+// WithAllowDuplicateKeys returns a [ScanOption] that accepts objects with
+// duplicate keys.
+func WithAllowDuplicateKeys() ScanOption {
+	return func(o *scanOptions) {
+		o.allowDuplicates = true
+	}
+}
+
+// Scan validates that data is a single JSON value, without decoding it, for
+// screening untrusted input before it reaches code that acts on it.
+//
+// In a single pass, it checks the syntax, that strings are valid UTF-8, that
+// objects and arrays nest at most [DefaultMaxDepth] levels, and that no
+// object has a duplicate key, which it reports as a [*DuplicateKeyError].
+// Keys are compared after unescaping, as by [CheckDuplicateKeys].
+//
+// Scan does not allocate unless it fails; the memory used to find duplicate
+// keys is reused across calls.
+func Scan(data []byte, opts ...ScanOption) error {
+	s := scannerPool.Get().(*scanner)
+	defer scannerPool.Put(s)
+	s.data, s.keys = data, s.keys[:0]
+	defer func() { s.data = nil }()
+	// The options live in the pooled scanner, as a local variable would
+	// escape to the heap through opts.
+	s.opts = scanOptions{maxDepth: DefaultMaxDepth}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+
+	i := skipSpace(data, 0)
+	i, err := s.value(i, 0)
+	if err != nil {
+		return err
+	}
+	if i = skipSpace(data, i); i != len(data) {
+		return s.syntaxError(i)
+	}
+	return nil
+}
+
+var scannerPool = sync.Pool{New: func() any { return new(scanner) }}
+
+type scanner struct {
+	data []byte
+	opts scanOptions
+
+	// keys holds the keys of the objects being scanned, innermost last.
+	keys []scanKey
+}
+
+type scanKey struct {
+	hash   uint64 // FNV-1a of the unescaped runes
+	offset int
+}
+
+func (s *scanner) syntaxError(i int) error {
+	if i >= len(s.data) {
+		return fmt.Errorf("jsonify: unexpected end of JSON input")
+	}
+	return fmt.Errorf("jsonify: invalid character %q at offset %d", s.data[i], i)
+}
+
+// value scans the value at data[i], at the given depth, and returns the
+// index following it.
+func (s *scanner) value(i, depth int) (int, error) {
+	data := s.data
+	if i >= len(data) {
+		return 0, s.syntaxError(i)
+	}
+	switch c := data[i]; c {
+	case '{', '[':
+		if depth >= s.opts.maxDepth {
+			return 0, fmt.Errorf("jsonify: exceeded max depth %d at offset %d", s.opts.maxDepth, i)
+		}
+		if c == '{' {
+			return s.object(i, depth+1)
+		}
+		return s.array(i, depth+1)
+	case '"':
+		end, _, err := s.str(i)
+		return end, err
+	case 't', 'f', 'n':
+		for _, lit := range []string{"true", "false", "null"} {
+			if len(data)-i >= len(lit) && string(data[i:i+len(lit)]) == lit {
+				if end := i + len(lit); end == len(data) || isDelimiter(data[end]) {
+					return end, nil
+				}
+			}
+		}
+		return 0, s.syntaxError(i)
+	}
+	end := i
+	for end < len(data) && !isDelimiter(data[end]) {
+		end++
+	}
+	if !isNumber(data[i:end]) {
+		return 0, s.syntaxError(i)
+	}
+	return end, nil
+}
+
+func (s *scanner) array(i, depth int) (int, error) {
+	data := s.data
+	if i = skipSpace(data, i+1); i < len(data) && data[i] == ']' {
+		return i + 1, nil
+	}
+	for {
+		var err error
+		if i, err = s.value(i, depth); err != nil {
+			return 0, err
+		}
+		if i = skipSpace(data, i); i == len(data) {
+			return 0, s.syntaxError(i)
+		}
+		switch data[i] {
+		case ']':
+			return i + 1, nil
+		case ',':
+			i = skipSpace(data, i+1)
+		default:
+			return 0, s.syntaxError(i)
+		}
+	}
+}
+
+func (s *scanner) object(i, depth int) (int, error) {
+	data := s.data
+	base := len(s.keys)
+	defer func() { s.keys = s.keys[:base] }()
+	if i = skipSpace(data, i+1); i < len(data) && data[i] == '}' {
+		return i + 1, nil
+	}
+	for {
+		end, hash, err := s.str(i)
+		if err != nil {
+			return 0, err
+		}
+		if !s.opts.allowDuplicates {
+			s.keys = append(s.keys, scanKey{hash: hash, offset: i})
+		}
+		if i = skipSpace(data, end); i == len(data) || data[i] != ':' {
+			return 0, s.syntaxError(i)
+		}
+		if i, err = s.value(skipSpace(data, i+1), depth); err != nil {
+			return 0, err
+		}
+		if i = skipSpace(data, i); i == len(data) {
+			return 0, s.syntaxError(i)
+		}
+		switch data[i] {
+		case '}':
+			return i + 1, s.checkKeys(s.keys[base:])
+		case ',':
+			i = skipSpace(data, i+1)
+		default:
+			return 0, s.syntaxError(i)
+		}
+	}
+}
+
+// checkKeys reports the first duplicate among the keys of an object.
+func (s *scanner) checkKeys(keys []scanKey) error {
+	if len(keys) < 2 {
+		return nil
+	}
+	slices.SortFunc(keys, func(a, b scanKey) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.offset, b.offset))
+	})
+	dup := -1
+	for i := 1; i < len(keys); i++ {
+		for j := i - 1; j >= 0 && keys[j].hash == keys[i].hash; j-- {
+			if s.sameKey(keys[j].offset, keys[i].offset) && (dup < 0 || keys[i].offset < dup) {
+				dup = keys[i].offset
+			}
+		}
+	}
+	if dup < 0 {
+		return nil
+	}
+	end, _, _ := s.str(dup)
+	var key string
+	_ = Decode(s.data[dup:end], &key)
+	return &DuplicateKeyError{Key: key, Offset: dup}
+}
+
+// sameKey reports whether the valid strings at data[i] and data[j] are the
+// same after unescaping.
+func (s *scanner) sameKey(i, j int) bool {
+	i, j = i+1, j+1
+	for {
+		a, ni := s.char(i)
+		b, nj := s.char(j)
+		if a != b {
+			return false
+		}
+		if a < 0 {
+			return true
+		}
+		i, j = ni, nj
+	}
+}
+
+// str scans the string at data[i] and returns the index following it and
+// the hash of its unescaped runes.
+func (s *scanner) str(i int) (int, uint64, error) {
+	data := s.data
+	if i >= len(data) || data[i] != '"' {
+		return 0, 0, s.syntaxError(i)
+	}
+	hash := uint64(14695981039346656037)
+	for i++; ; {
+		if i >= len(data) {
+			return 0, 0, s.syntaxError(i)
+		}
+		switch c := data[i]; {
+		case c == '"':
+			return i + 1, hash, nil
+		case c < 0x20:
+			return 0, 0, s.syntaxError(i)
+		case c == '\\' && !validEscape(data, i):
+			return 0, 0, fmt.Errorf("jsonify: invalid escape at offset %d", i)
+		case c >= utf8.RuneSelf:
+			if r, _ := utf8.DecodeRune(data[i:]); r == utf8.RuneError {
+				return 0, 0, fmt.Errorf("jsonify: invalid UTF-8 at offset %d", i)
+			}
+		}
+		r, next := s.char(i)
+		hash = (hash ^ uint64(r)) * 1099511628211
+		i = next
+	}
+}
+
+// char returns the unescaped rune of the valid string character at data[i]
+// and the index following it, or -1 at the closing quote.
+func (s *scanner) char(i int) (rune, int) {
+	data := s.data
+	switch data[i] {
+	case '"':
+		return -1, i
+	case '\\':
+	default:
+		r, size := utf8.DecodeRune(data[i:])
+		return r, i + size
+	}
+	switch c := data[i+1]; c {
+	case 'b':
+		return '\b', i + 2
+	case 'f':
+		return '\f', i + 2
+	case 'n':
+		return '\n', i + 2
+	case 'r':
+		return '\r', i + 2
+	case 't':
+		return '\t', i + 2
+	case 'u':
+		r := hex4(data[i+2:])
+		if utf16.IsSurrogate(r) && i+12 <= len(data) && data[i+6] == '\\' && data[i+7] == 'u' {
+			if pair := utf16.DecodeRune(r, hex4(data[i+8:])); pair != utf8.RuneError {
+				return pair, i + 12
+			}
+		}
+		if utf16.IsSurrogate(r) {
+			r = utf8.RuneError
+		}
+		return r, i + 6
+	default:
+		return rune(c), i + 2
+	}
+}
+
+// validEscape reports whether the backslash at data[i] starts a valid escape.
+func validEscape(data []byte, i int) bool {
+	if i+1 >= len(data) {
+		return false
+	}
+	switch data[i+1] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		return true
+	case 'u':
+		return i+6 <= len(data) && hex4(data[i+2:]) >= 0
+	}
+	return false
+}
+
+// hex4 returns the value of the four hex digits at the start of b, or -1.
+func hex4(b []byte) rune {
+	if len(b) < 4 {
+		return -1
+	}
+	var r rune
+	for _, c := range b[:4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c -= 'a' - 10
+		case 'A' <= c && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return -1
+		}
+		r = r<<4 | rune(c)
+	}
+	return r
+}
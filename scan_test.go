@@ -0,0 +1,71 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleScan() {
+	fmt.Println(jsonify.Scan([]byte(`{"user":"ann","roles":["admin"]}`)))
+	fmt.Println(jsonify.Scan([]byte(`{"role":"user","role":"admin"}`)))
+	fmt.Println(jsonify.Scan([]byte(`[[[1]]]`), jsonify.WithMaxDepth(2)))
+	// Output:
+	// <nil>
+	// jsonify: duplicate key "role" at offset 15
+	// jsonify: exceeded max depth 2 at offset 2
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []jsonify.ScanOption
+		wantErr bool
+	}{
+		{name: "scalars", input: ` [1, -0.5e+3, "a\"é😀", true, false, null, {}, []] `},
+		{name: "nested", input: `{"a":{"a":{"b":1}},"b":{"a":2}}`},
+		{name: "escaped duplicate", input: `{"a":1,"\u0061":2}`, wantErr: true},
+		{name: "surrogate pair", input: `{"\ud83d\ude00":1,"😀":2}`, wantErr: true},
+		{name: "nested duplicate", input: `[{"x":{"k":1,"j":2,"k":3}}]`, wantErr: true},
+		{name: "allowed duplicate", input: `{"a":1,"a":2}`, opts: []jsonify.ScanOption{jsonify.WithAllowDuplicateKeys()}},
+		{name: "empty", input: ``, wantErr: true},
+		{name: "trailing", input: `{} {}`, wantErr: true},
+		{name: "trailing comma", input: `[1,]`, wantErr: true},
+		{name: "missing colon", input: `{"a" 1}`, wantErr: true},
+		{name: "bad number", input: `[01]`, wantErr: true},
+		{name: "bad literal", input: `[nul]`, wantErr: true},
+		{name: "literal suffix", input: `truex`, wantErr: true},
+		{name: "bad escape", input: `"\x"`, wantErr: true},
+		{name: "short unicode", input: `"\u12"`, wantErr: true},
+		{name: "control character", input: "\"a\tb\"", wantErr: true},
+		{name: "invalid UTF-8", input: "\"\xff\"", wantErr: true},
+		{name: "unterminated", input: `{"a":"b`, wantErr: true},
+		{name: "deep", input: strings.Repeat("[", 10001) + strings.Repeat("]", 10001), wantErr: true},
+		{name: "within depth", input: strings.Repeat("[", 10000) + strings.Repeat("]", 10000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jsonify.Scan([]byte(tt.input), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var dup *jsonify.DuplicateKeyError
+	if err := jsonify.Scan([]byte(`{"b":1,"a":2,"a":3,"b":4}`)); !errors.As(err, &dup) || dup.Key != "a" || dup.Offset != 13 {
+		t.Errorf("Scan() error = %v, want the first duplicate", err)
+	}
+}
+
+func TestScanAllocations(t *testing.T) {
+	data := []byte(`{"id":1,"tags":["a","b"],"meta":{"k":"v","n":null,"k2":[1.5,true]}}`)
+	jsonify.Scan(data)
+	if n := testing.AllocsPerRun(100, func() { jsonify.Scan(data) }); n != 0 {
+		t.Errorf("Scan() allocates %v times, want 0", n)
+	}
+}
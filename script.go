@@ -0,0 +1,29 @@
+package jsonify
+
+import "bytes"
+
+var scriptReplacer = [...]struct{ old, new []byte }{
+	{[]byte("</"), []byte(`<\/`)},
+	{[]byte("<!--"), []byte(`\u003c!--`)},
+	{[]byte("\u2028"), []byte(`\u2028`)},
+	{[]byte("\u2029"), []byte(`\u2029`)},
+}
+
+// ScriptSafe is similar to [Bytes] but the output can be inlined inside an
+// HTML <script> element.
+//
+// It escapes "</", "<!--", U+2028 and U+2029, which would otherwise end the
+// script element or break the JavaScript parser. These sequences can only
+// occur inside JSON strings, so the result is still equivalent JSON.
+func ScriptSafe(v any) ([]byte, error) {
+	b, err := Bytes(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range scriptReplacer {
+		if bytes.Contains(b, r.old) {
+			b = bytes.ReplaceAll(b, r.old, r.new)
+		}
+	}
+	return b, nil
+}
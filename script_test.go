@@ -0,0 +1,53 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleScriptSafe() {
+	b, _ := jsonify.ScriptSafe(map[string]any{"html": "</script><b>"})
+	fmt.Printf("%s\n", b)
+	// Output:
+	// {"html":"<\/script><b>"}
+}
+
+func TestScriptSafe(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{"plain", map[string]any{"A": "<b>"}, `{"A":"<b>"}`},
+		{"closing tag", "</script>", `"<\/script>"`},
+		{"comment", "<!-- x -->", `"\u003c!-- x -->"`},
+		{"line separators", "a\u2028b\u2029c", `"a\u2028b\u2029c"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.ScriptSafe(tt.input)
+			if err != nil {
+				t.Fatalf("ScriptSafe() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("ScriptSafe() = %s, want %s", got, tt.expected)
+			}
+			var want, decoded any
+			json.Unmarshal(jsonify.MustBytes(tt.input), &want)
+			if err := json.Unmarshal(got, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded, want) {
+				t.Errorf("ScriptSafe() decodes to %v, want %v", decoded, want)
+			}
+		})
+	}
+
+	if _, err := jsonify.ScriptSafe(make(chan int)); err == nil {
+		t.Errorf("ScriptSafe() error = nil, want error")
+	}
+}
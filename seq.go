@@ -0,0 +1,112 @@
+package jsonify
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// recordSeparator starts each record of a JSON text sequence.
+const recordSeparator = 0x1E
+
+// WriteSeq writes v encoded with [Bytes] and opts to w as a record of a JSON
+// text sequence (RFC 7464, application/json-seq): an ASCII record separator,
+// the JSON text and a line feed. Indentation options are kept, as records
+// are delimited by their separators rather than by lines.
+func WriteSeq(w io.Writer, v any, opts ...Option) error {
+	b, err := Bytes(v, noTrailingNewline(opts)...)
+	if err != nil {
+		return err
+	}
+	record := make([]byte, 0, len(b)+2)
+	record = append(record, recordSeparator)
+	record = append(record, b...)
+	_, err = w.Write(append(record, '\n'))
+	return err
+}
+
+// DecodeSeq returns a sequence of the values of the records of the JSON text
+// sequence (RFC 7464) read from r, each decoded into a T with [Decode] and
+// opts.
+//
+// As the RFC requires, a corrupt record, e.g. one truncated by a crashed
+// writer, yields an error, with its record number, and decoding resumes at
+// the next record separator if the loop continues. A number, true, false or
+// null that is not followed by whitespace is treated as truncated. Records
+// longer than [MaxFrameSize] fail with [ErrFrameTooLarge], and end the
+// sequence as read errors do.
+func DecodeSeq[T any](r io.Reader, opts ...DecodeOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, MaxFrameSize)
+		scanner.Split(splitRecords)
+		var zero T
+		for n := 0; scanner.Scan(); n++ {
+			record := scanner.Bytes()
+			text := bytes.TrimSpace(record)
+			if n == 0 {
+				if len(text) == 0 {
+					// The empty text before the first separator.
+					continue
+				}
+				if !yield(zero, errors.New("jsonify: JSON text sequence does not start with a record separator")) {
+					return
+				}
+				continue
+			}
+			if len(text) == 0 {
+				continue
+			}
+			if err := checkRecord(record, text); err != nil {
+				if !yield(zero, fmt.Errorf("jsonify: record %d: %w", n, err)) {
+					return
+				}
+				continue
+			}
+			var v T
+			if err := Decode(text, &v, opts...); err != nil {
+				if !yield(zero, fmt.Errorf("jsonify: record %d: %w", n, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				err = fmt.Errorf("%w: record longer than %d bytes", ErrFrameTooLarge, MaxFrameSize)
+			}
+			yield(zero, err)
+		}
+	}
+}
+
+// checkRecord reports a record whose text may have been truncated: a
+// number, true, false or null without whitespace after it.
+func checkRecord(record, text []byte) error {
+	switch text[0] {
+	case '{', '[', '"':
+		return nil
+	}
+	if last := record[len(record)-1]; last != ' ' && last != '\t' && last != '\n' && last != '\r' {
+		return fmt.Errorf("possibly truncated text %q", text)
+	}
+	return nil
+}
+
+// splitRecords is a [bufio.SplitFunc] returning the data between record
+// separators, including the data before the first one.
+func splitRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, recordSeparator); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,141 @@
+package jsonify
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// WithSortedSeq2 returns an [Option] that encodes the members of the objects
+// of iter.Seq2 values sorted by key, like those of maps, rather than in the
+// order of the sequence. It collects the members of each object before
+// writing them.
+func WithSortedSeq2() Option {
+	return func(o *options) {
+		o.sortSeq2 = true
+	}
+}
+
+func init() {
+	encoderFactories = append(encoderFactories, iterEncoderOf)
+}
+
+// iterEncoderOf returns an encoder of iter.Seq[T] values as JSON arrays, and
+// of iter.Seq2[K, V] values with keys of a string kind as JSON objects, which
+// writes their elements as they are yielded. It applies to the types of the
+// same shape, func(yield func(T) bool) and func(yield func(K, V) bool).
+func iterEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	t := typ.Type1()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 || t.IsVariadic() {
+		return nil
+	}
+	yield := t.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0) != reflect.TypeFor[bool]() || yield.IsVariadic() {
+		return nil
+	}
+	switch yield.NumIn() {
+	case 1:
+		return &iterEncoder{typ: typ, yield: yield}
+	case 2:
+		if yield.In(0).Kind() == reflect.String {
+			return &iterEncoder{typ: typ, yield: yield, pairs: true}
+		}
+	}
+	return nil
+}
+
+type iterEncoder struct {
+	typ   reflect2.Type
+	yield reflect.Type
+	pairs bool
+}
+
+func (e *iterEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*unsafe.Pointer)(ptr) == nil
+}
+
+func (e *iterEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if *(*unsafe.Pointer)(ptr) == nil {
+		stream.WriteNil()
+		return
+	}
+	seq := reflect.ValueOf(e.typ.UnsafeIndirect(ptr))
+	if !e.pairs {
+		stream.WriteArrayStart()
+		e.each(seq, func(i int, args []reflect.Value) {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteVal(args[0].Interface())
+		}, stream)
+		stream.WriteArrayEnd()
+		return
+	}
+	if o, _ := stream.Attachment.(*options); o != nil && o.sortSeq2 {
+		e.encodeSorted(seq, stream)
+		return
+	}
+	stream.WriteObjectStart()
+	e.each(seq, func(i int, args []reflect.Value) {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(args[0].String())
+		stream.WriteVal(args[1].Interface())
+	}, stream)
+	stream.WriteObjectEnd()
+}
+
+// encodeSorted encodes the members yielded by seq sorted by key. Their
+// values are encoded to a separate stream as they are yielded, as they may
+// be reused.
+func (e *iterEncoder) encodeSorted(seq reflect.Value, stream *jsoniter.Stream) {
+	type member struct {
+		key   string
+		value []byte
+	}
+	var members []member
+	sub := stream.Pool().BorrowStream(nil)
+	defer stream.Pool().ReturnStream(sub)
+	sub.Attachment = stream.Attachment
+	e.each(seq, func(_ int, args []reflect.Value) {
+		sub.Reset(nil)
+		sub.WriteVal(args[1].Interface())
+		if sub.Error != nil && stream.Error == nil {
+			stream.Error = sub.Error
+		}
+		members = append(members, member{args[0].String(), slices.Clone(sub.Buffer())})
+	}, stream)
+	slices.SortStableFunc(members, func(a, b member) int { return strings.Compare(a.key, b.key) })
+	stream.WriteObjectStart()
+	for i, m := range members {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(m.key)
+		stream.Write(m.value)
+	}
+	stream.WriteObjectEnd()
+}
+
+// each calls fn with the index and the arguments of each call of yield by
+// seq, until the stream fails.
+func (e *iterEncoder) each(seq reflect.Value, fn func(i int, args []reflect.Value), stream *jsoniter.Stream) {
+	i := 0
+	more := reflect.ValueOf(true)
+	seq.Call([]reflect.Value{reflect.MakeFunc(e.yield, func(args []reflect.Value) []reflect.Value {
+		if stream.Error != nil {
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		fn(i, args)
+		i++
+		if stream.Error != nil {
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		return []reflect.Value{more}
+	})})
+}
@@ -0,0 +1,109 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWithSortedSeq2() {
+	scores := func(yield func(string, int) bool) {
+		_ = yield("bob", 3) && yield("ann", 5)
+	}
+	a, _ := jsonify.String(scores)
+	b, _ := jsonify.String(scores, jsonify.WithSortedSeq2())
+	fmt.Println(a)
+	fmt.Println(b)
+	// Output:
+	// {"bob":3,"ann":5}
+	// {"ann":5,"bob":3}
+}
+
+type pair struct {
+	key   string
+	value any
+}
+
+func pairs(ps ...pair) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, p := range ps {
+			if !yield(p.key, p.value) {
+				return
+			}
+		}
+	}
+}
+
+func TestEncode_iterators(t *testing.T) {
+	type name string
+	type withSeq struct {
+		Items iter.Seq[int]           `json:"items,omitempty"`
+		Attrs iter.Seq2[name, string] `json:"attrs"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{name: "seq", v: slices.Values([]string{"a", "<b>"}), want: `["a","<b>"]`},
+		{name: "empty seq", v: slices.Values([]int(nil)), want: `[]`},
+		{name: "nil seq", v: iter.Seq[int](nil), want: `null`},
+		{name: "seq2", v: pairs(pair{"b", 1}, pair{"a", []int{2}}), want: `{"b":1,"a":[2]}`},
+		{name: "sorted seq2", v: pairs(pair{"b", 1}, pair{"a", []int{2}}), opts: []jsonify.Option{jsonify.WithSortedSeq2()}, want: `{"a":[2],"b":1}`},
+		{name: "nested sorted seq2", v: pairs(pair{"b", pairs(pair{"y", 1}, pair{"x", 2})}, pair{"a", nil}), opts: []jsonify.Option{jsonify.WithSortedSeq2()}, want: `{"a":null,"b":{"x":2,"y":1}}`},
+		{name: "maps.All", v: maps.All(map[string]int{"a": 1}), want: `{"a":1}`},
+		{name: "seq2 with int keys", v: slices.All([]string{"a"}), wantErr: true},
+		{
+			name: "fields",
+			v: withSeq{Attrs: func(yield func(name, string) bool) {
+				yield("k", "v")
+			}},
+			want: `{"attrs":{"k":"v"}}`,
+		},
+		{name: "indent", v: slices.Values([]int{1, 2}), opts: []jsonify.Option{jsonify.WithIndent("", " ")}, want: "[\n 1,\n 2\n]"},
+		{name: "element error", v: slices.Values([]any{1, math.NaN(), 3}), wantErr: true},
+		{name: "other func", v: func(int) {}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.v, tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("String() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode_iteratorStops(t *testing.T) {
+	yielded := 0
+	seq := func(yield func(any) bool) {
+		for _, v := range []any{1, math.NaN(), 3, 4} {
+			yielded++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	if _, err := jsonify.String(iter.Seq[any](seq)); err == nil {
+		t.Fatal("String() error = nil, want error")
+	}
+	if yielded != 2 {
+		t.Errorf("the sequence yielded %d values, want 2", yielded)
+	}
+}
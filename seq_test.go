@@ -0,0 +1,77 @@
+package jsonify_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleDecodeSeq() {
+	var sb strings.Builder
+	jsonify.WriteSeq(&sb, map[string]int{"a": 1})
+	sb.WriteString("\x1e{\"a\":") // A record truncated by a crashed writer.
+	jsonify.WriteSeq(&sb, map[string]int{"a": 3})
+
+	for v, err := range jsonify.DecodeSeq[map[string]int](strings.NewReader(sb.String())) {
+		fmt.Println(v, err != nil)
+	}
+	// Output:
+	// map[a:1] false
+	// map[] true
+	// map[a:3] false
+}
+
+func TestWriteSeq(t *testing.T) {
+	var sb strings.Builder
+	if err := jsonify.WriteSeq(&sb, []int{1, 2}, jsonify.WithIndent("", " ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := jsonify.WriteSeq(&sb, func() {}); err == nil {
+		t.Error("WriteSeq() error = nil, want error")
+	}
+	if got, want := sb.String(), "\x1e[\n 1,\n 2\n]\n"; got != want {
+		t.Errorf("WriteSeq() wrote %q, want %q", got, want)
+	}
+}
+
+func TestDecodeSeq(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "records", input: "\x1e1\n\x1e\"a\"\n\x1e{\"b\":[true]}\n", want: []string{"1", "a", "map[b:[true]]"}},
+		{name: "multiline record", input: "\x1e[\n 1,\n 2\n]\n", want: []string{"[1 2]"}},
+		{name: "empty records", input: "\x1e\x1e \n\x1e1\n", want: []string{"1"}},
+		{name: "no final line feed", input: "\x1e{}", want: []string{"map[]"}},
+		{name: "truncated number", input: "\x1e12\x1e3\n", want: []string{"error", "3"}},
+		{name: "truncated at end", input: "\x1e1\n\x1e12", want: []string{"1", "error"}},
+		{name: "truncated literal", input: "\x1etr\x1etrue\n", want: []string{"error", "true"}},
+		{name: "corrupt record", input: "\x1e{\"a\":\x1e{\"a\":2}\n", want: []string{"error", "map[a:2]"}},
+		{name: "missing separator", input: "1\n\x1e2\n", want: []string{"error", "2"}},
+		{name: "too long", input: "\x1e1\n\x1e" + strings.Repeat(" ", jsonify.MaxFrameSize+1) + "\x1e2\n", want: []string{"1", "frame too large"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for v, err := range jsonify.DecodeSeq[any](strings.NewReader(tt.input)) {
+				switch {
+				case errors.Is(err, jsonify.ErrFrameTooLarge):
+					got = append(got, "frame too large")
+				case err != nil:
+					got = append(got, "error")
+				default:
+					got = append(got, fmt.Sprint(v))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DecodeSeq() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package jsonify
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Set is a set of values that encodes as a sorted JSON array, instead of an
+// object with empty values as map[T]struct{} would.
+//
+// Elements are sorted by their natural order if T is an integer, float or
+// string type or has a Compare method, and by their JSON encoding otherwise,
+// so the output is deterministic. A nil Set encodes as null.
+//
+// Decoding accepts duplicate elements; use [StrictSet] to reject them.
+type Set[T comparable] map[T]struct{}
+
+// StrictSet is a [Set] that fails to decode if the input contains duplicate
+// elements.
+type StrictSet[T comparable] map[T]struct{}
+
+// Multiset is a set that counts the occurrences of each element. It encodes
+// as a sorted JSON array in which each element is repeated by its count.
+// Elements with a count of zero or less are omitted.
+type Multiset[T comparable] map[T]int
+
+// NewSet returns a Set holding values.
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+// Add adds v to s.
+func (s Set[T]) Add(v T) {
+	s[v] = struct{}{}
+}
+
+// Has reports whether s contains v.
+func (s Set[T]) Has(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return s.marshalJSON(newOptions(nil))
+}
+
+func (s Set[T]) marshalJSON(o *options) ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	values := make([]T, 0, len(s))
+	for v := range s {
+		values = append(values, v)
+	}
+	return marshalSorted(values, o)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	set, err := unmarshalSet[T](data, false)
+	if err != nil {
+		return err
+	}
+	*s = set
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s StrictSet[T]) MarshalJSON() ([]byte, error) {
+	return Set[T](s).MarshalJSON()
+}
+
+func (s StrictSet[T]) marshalJSON(o *options) ([]byte, error) {
+	return Set[T](s).marshalJSON(o)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *StrictSet[T]) UnmarshalJSON(data []byte) error {
+	set, err := unmarshalSet[T](data, true)
+	if err != nil {
+		return err
+	}
+	*s = StrictSet[T](set)
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (m Multiset[T]) MarshalJSON() ([]byte, error) {
+	return m.marshalJSON(newOptions(nil))
+}
+
+func (m Multiset[T]) marshalJSON(o *options) ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var values []T
+	for v, n := range m {
+		for i := 0; i < n; i++ {
+			values = append(values, v)
+		}
+	}
+	return marshalSorted(values, o)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (m *Multiset[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := Decode(data, &values); err != nil {
+		return err
+	}
+	if values == nil {
+		*m = nil
+		return nil
+	}
+	ms := make(Multiset[T], len(values))
+	for _, v := range values {
+		ms[v]++
+	}
+	*m = ms
+	return nil
+}
+
+func unmarshalSet[T comparable](data []byte, strict bool) (Set[T], error) {
+	var values []T
+	if err := Decode(data, &values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return nil, nil
+	}
+	set := make(Set[T], len(values))
+	for _, v := range values {
+		if strict && set.Has(v) {
+			return nil, fmt.Errorf("jsonify: duplicate set element %v", v)
+		}
+		set.Add(v)
+	}
+	return set, nil
+}
+
+// marshalSorted encodes values with o as a JSON array in a deterministic
+// order.
+func marshalSorted[T any](values []T, o *options) ([]byte, error) {
+	encoded := make([][]byte, len(values))
+	for i, v := range values {
+		b, err := o.marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	index := make([]int, len(values))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		a, b := index[i], index[j]
+		if c, ok := compareValues(values[a], values[b]); ok && c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(encoded[a], encoded[b]) < 0
+	})
+	out := []byte{'['}
+	for i, k := range index {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, encoded[k]...)
+	}
+	return append(out, ']'), nil
+}
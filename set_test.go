@@ -0,0 +1,69 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleSet() {
+	tags := jsonify.NewSet("go", "json", "api")
+	fmt.Println(jsonify.MustString(map[string]any{"tags": tags}))
+	// Output:
+	// {"tags":["api","go","json"]}
+}
+
+func TestSet(t *testing.T) {
+	type point struct{ X, Y int }
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "nil", input: jsonify.Set[int](nil), expected: `null`},
+		{name: "empty", input: jsonify.NewSet[int](), expected: `[]`},
+		{name: "numbers", input: jsonify.NewSet(10, 9, -1), expected: `[-1,9,10]`},
+		{name: "structs", input: jsonify.NewSet(point{2, 1}, point{1, 2}), expected: `[{"X":1,"Y":2},{"X":2,"Y":1}]`},
+		{name: "strict", input: jsonify.StrictSet[string]{"b": {}, "a": {}}, expected: `["a","b"]`},
+		{name: "multiset", input: jsonify.Multiset[string]{"b": 1, "a": 2, "c": 0}, expected: `["a","a","b"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetDecode(t *testing.T) {
+	var s jsonify.Set[int]
+	if err := jsonify.Decode([]byte(`[3,1,3]`), &s); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(s, jsonify.NewSet(1, 3)) {
+		t.Errorf("Decode() = %v", s)
+	}
+
+	var strict jsonify.StrictSet[int]
+	if err := jsonify.Decode([]byte(`[3,1,3]`), &strict); err == nil {
+		t.Errorf("Decode() into StrictSet accepted duplicates")
+	}
+	if err := jsonify.Decode([]byte(`[3,1]`), &strict); err != nil || len(strict) != 2 {
+		t.Errorf("Decode() = %v, %v", strict, err)
+	}
+
+	var m jsonify.Multiset[string]
+	if err := jsonify.Decode([]byte(`["a","b","a"]`), &m); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(m, jsonify.Multiset[string]{"a": 2, "b": 1}) {
+		t.Errorf("Decode() = %v", m)
+	}
+}
@@ -0,0 +1,89 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Sparse is a slice that encodes only its non-zero elements, keyed by index,
+// together with its length:
+//
+//	{"len":1000,"entries":{"17":1.5,"940":2}}
+//
+// It is meant for large slices that are mostly zero, e.g. time-series
+// buffers, which would otherwise encode as thousands of zeros or nulls.
+// Entries are in ascending order of index. A nil Sparse encodes as null.
+//
+// Decoding restores a slice of length len, with zero values for missing
+// entries. It fails if an index is not a non-negative integer less than len,
+// or if len is greater than [MaxSparseLen].
+type Sparse[T any] []T
+
+// MaxSparseLen is the largest length of a [Sparse] that decoding accepts, to
+// bound the memory a short hostile input can make it allocate.
+const MaxSparseLen = 1 << 20
+
+type sparseJSON struct {
+	Len     int                        `json:"len"`
+	Entries map[string]json.RawMessage `json:"entries"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s Sparse[T]) MarshalJSON() ([]byte, error) {
+	return s.marshalJSON(newOptions(nil))
+}
+
+func (s Sparse[T]) marshalJSON(o *options) ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	var keys []string
+	var values []json.RawMessage
+	for i := range s {
+		if reflect.ValueOf(&s[i]).Elem().IsZero() {
+			continue
+		}
+		b, err := o.marshal(s[i])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, strconv.Itoa(i))
+		values = append(values, b)
+	}
+	b := append([]byte(`{"len":`), strconv.Itoa(len(s))...)
+	b = append(b, `,"entries":`...)
+	b = appendObject(b, keys, values)
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Sparse[T]) UnmarshalJSON(data []byte) error {
+	var v *sparseJSON
+	if err := Decode(data, &v); err != nil {
+		return err
+	}
+	if v == nil {
+		*s = nil
+		return nil
+	}
+	if v.Len < 0 {
+		return fmt.Errorf("jsonify: invalid sparse length %d", v.Len)
+	}
+	if v.Len > MaxSparseLen {
+		return fmt.Errorf("jsonify: sparse length %d is larger than %d", v.Len, MaxSparseLen)
+	}
+	out := make(Sparse[T], v.Len)
+	for key, value := range v.Entries {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= v.Len {
+			return fmt.Errorf("jsonify: invalid sparse index %q for length %d", key, v.Len)
+		}
+		if err := Decode(value, &out[i]); err != nil {
+			return err
+		}
+	}
+	*s = out
+	return nil
+}
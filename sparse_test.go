@@ -0,0 +1,72 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleSparse() {
+	buf := make(jsonify.Sparse[float64], 1000)
+	buf[17] = 1.5
+	buf[940] = 2
+	fmt.Println(jsonify.MustString(buf))
+	// Output:
+	// {"len":1000,"entries":{"17":1.5,"940":2}}
+}
+
+func TestSparse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{name: "nil", input: jsonify.Sparse[int](nil), expected: `null`},
+		{name: "empty", input: jsonify.Sparse[int]{}, expected: `{"len":0,"entries":{}}`},
+		{name: "index order", input: jsonify.Sparse[int]{0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 10}, expected: `{"len":11,"entries":{"2":3,"10":10}}`},
+		{name: "pointers", input: jsonify.Sparse[*string]{nil, new(string)}, expected: `{"len":2,"entries":{"1":""}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSparseDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected jsonify.Sparse[int]
+		wantErr  bool
+	}{
+		{name: "null", input: `null`, expected: nil},
+		{name: "entries", input: `{"len":4,"entries":{"3":7,"1":2}}`, expected: jsonify.Sparse[int]{0, 2, 0, 7}},
+		{name: "no entries", input: `{"len":2}`, expected: jsonify.Sparse[int]{0, 0}},
+		{name: "out of range", input: `{"len":2,"entries":{"2":1}}`, wantErr: true},
+		{name: "bad index", input: `{"len":2,"entries":{"x":1}}`, wantErr: true},
+		{name: "negative length", input: `{"len":-1}`, wantErr: true},
+		{name: "max length", input: fmt.Sprintf(`{"len":%d,"entries":{"%d":1}}`, jsonify.MaxSparseLen, jsonify.MaxSparseLen-1), expected: append(make(jsonify.Sparse[int], jsonify.MaxSparseLen-1), 1)},
+		{name: "too long", input: `{"len":4000000000,"entries":{}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got jsonify.Sparse[int]
+			err := jsonify.Decode([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Decode() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
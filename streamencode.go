@@ -0,0 +1,193 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// EncodeChunkSize is the size of the chunks in which [Encode] writes its
+// output.
+const EncodeChunkSize = 32 << 10
+
+// Encode encodes v to w like [Bytes] does, but writes the output in chunks
+// of about [EncodeChunkSize] bytes as it is encoded, instead of holding all
+// of it in memory, so that an unexpectedly large value costs time rather
+// than an out-of-memory crash.
+//
+// The memory Encode uses beyond the value itself is bounded by
+// EncodeChunkSize plus the encoding of the largest single leaf value, e.g.
+// a string, a protobuf message or the output of a [json.Marshaler], plus
+// the keys of the map being encoded, which are sorted before its entries
+// are written. Maps with keys other than strings and integers, and the
+// objects of [WithSortedSeq2], are buffered whole.
+//
+// Encode always uses the [Jsoniter] backend. As the output is never held
+// whole, options that reformat it, e.g. [WithIndent] and [WithFingerprint],
+// fail, and the hook of [SetPostMarshalHook] is not applied. If encoding
+// fails, e.g. with [ErrOutputTooLarge], w has received part of the output.
+// [WithCompression] compresses the output written to w.
+func Encode(w io.Writer, v any, opts ...Option) error {
+	o := newOptions(opts)
+	if o.reformats() {
+		return errors.New("jsonify: Encode does not support options reformatting the output")
+	}
+	return o.compress(w, func(w io.Writer) error {
+		if err := encode(w, v, o); err != nil || !o.trailingNewline {
+			return err
+		}
+		_, err := w.Write([]byte{'\n'})
+		return err
+	})
+}
+
+// encode encodes v to w for [Encode].
+func encode(w io.Writer, v any, o *options) error {
+	if raw, ok := v.(json.RawMessage); ok {
+		_, err := w.Write(raw)
+		return err
+	}
+	if b, ok, err := marshalProto(v, o); ok {
+		if err == nil {
+			err = o.checkOutput(b)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	o.mode.streaming = true
+	api := configFor(o.mode)
+	out := &outputCounter{w: w, o: o}
+	stream := jsoniter.NewStream(api, out, EncodeChunkSize)
+	stream.Attachment = o
+	stream.WriteVal(v)
+	if out.err != nil {
+		// jsoniter wraps the error of w as text with the encoded types.
+		return out.err
+	}
+	if o.err != nil {
+		return o.err
+	}
+	if stream.Error != nil {
+		return stream.Error
+	}
+	return stream.Flush()
+}
+
+// reformats reports whether o reformats the whole output once it is
+// encoded, which [Encode] cannot do.
+func (o *options) reformats() bool {
+	return o.format != nil || o.fingerprint != ""
+}
+
+// outputCounter counts the bytes written to w as part of the output
+// preceding the stream, for [WithMaxOutputBytes], and keeps the first
+// error of w.
+type outputCounter struct {
+	w   io.Writer
+	o   *options
+	err error
+}
+
+func (cw *outputCounter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.o.outputBase += n
+	if err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return n, err
+}
+
+// flushEncoder writes the output of the stream of [Encode] once a chunk of
+// it is buffered.
+type flushEncoder struct {
+	next jsoniter.ValEncoder
+}
+
+func (e *flushEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	e.next.Encode(ptr, stream)
+	if stream.Buffered() >= EncodeChunkSize {
+		stream.Flush()
+	}
+}
+
+func (e *flushEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.next.IsEmpty(ptr)
+}
+
+// streamMapEncoderOf returns an encoder of maps with string or integer keys
+// that writes their entries sorted by key directly to the stream, rather
+// than to a separate one, as jsoniter does to sort them.
+func streamMapEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	t := typ.Type1()
+	if t.Kind() != reflect.Map || hasMarshaler(typ) || hasMarshaler(reflect2.Type2(t.Key())) {
+		return nil
+	}
+	switch t.Key().Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &streamMapEncoder{typ: typ}
+	}
+	return nil
+}
+
+type streamMapEncoder struct {
+	typ reflect2.Type
+}
+
+func (e *streamMapEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	m := *(*unsafe.Pointer)(ptr)
+	return m == nil || reflect.ValueOf(e.typ.UnsafeIndirect(ptr)).Len() == 0
+}
+
+func (e *streamMapEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if *(*unsafe.Pointer)(ptr) == nil {
+		stream.WriteNil()
+		return
+	}
+	m := reflect.ValueOf(e.typ.UnsafeIndirect(ptr))
+	type entry struct {
+		key string
+		k   reflect.Value
+	}
+	entries := make([]entry, 0, m.Len())
+	for iter := m.MapRange(); iter.Next(); {
+		entries = append(entries, entry{mapKeyString(iter.Key()), iter.Key()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+	stream.WriteObjectStart()
+	for i, en := range entries {
+		if stream.Error != nil {
+			break
+		}
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(en.key)
+		stream.WriteVal(m.MapIndex(en.k).Interface())
+	}
+	stream.WriteObjectEnd()
+}
+
+// mapKeyString returns k, a map key of a string or integer kind, as the
+// key of a JSON object.
+func mapKeyString(k reflect.Value) string {
+	switch {
+	case k.CanInt():
+		return strconv.FormatInt(k.Int(), 10)
+	case k.CanUint():
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return k.String()
+}
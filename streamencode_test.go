@@ -0,0 +1,135 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleEncode() {
+	rows := map[string][]int{"b": {2, 3}, "a": {1}}
+	err := jsonify.Encode(os.Stdout, rows)
+	fmt.Println()
+	fmt.Println(err)
+	// Output:
+	// {"a":[1],"b":[2,3]}
+	// <nil>
+}
+
+// chunkWriter records the size of the largest write.
+type chunkWriter struct {
+	strings.Builder
+	writes  int
+	largest int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.largest = max(w.largest, len(p))
+	return w.Builder.Write(p)
+}
+
+func TestEncode(t *testing.T) {
+	type item struct {
+		ID   int               `json:"id"`
+		Tags map[string]string `json:"tags,omitempty"`
+		Any  any               `json:"any,omitempty"`
+	}
+	tests := []struct {
+		name string
+		v    any
+		opts []jsonify.Option
+	}{
+		{name: "nil", v: nil},
+		{name: "scalar", v: "<a>"},
+		{name: "raw", v: json.RawMessage(`{"a":1}`)},
+		{name: "struct", v: item{ID: 1, Tags: map[string]string{"b": "2", "a": "1"}, Any: []any{1, "x"}}},
+		{name: "int keys", v: map[int]string{10: "a", 9: "b", -1: "c"}},
+		{name: "uint keys", v: map[uint8]bool{2: true, 10: false}},
+		{name: "float keys", v: map[float64]int{1.5: 1, 0.5: 2}},
+		{name: "empty map", v: map[string]int{}},
+		{name: "nil map", v: map[string]int(nil)},
+		{name: "omitempty map", v: struct {
+			M map[string]int `json:"m,omitempty"`
+		}{M: map[string]int{}}},
+		{name: "nested maps", v: map[string]any{"z": map[string]any{"y": 1, "x": []any{map[string]int{"b": 1, "a": 2}}}}},
+		{name: "options", v: map[string]any{"n": int64(1) << 60, "f": 0.5}, opts: []jsonify.Option{jsonify.WithInt64(jsonify.Int64AsString)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := jsonify.String(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sb strings.Builder
+			if err := jsonify.Encode(&sb, tt.v, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != want {
+				t.Errorf("Encode() wrote %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestEncode_chunks(t *testing.T) {
+	big := make(map[string][]string, 20_000)
+	for i := range 20_000 {
+		big[fmt.Sprintf("key%05d", i)] = []string{strings.Repeat("v", 20)}
+	}
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "slice", v: make([]int, 200_000)},
+		{name: "map", v: big},
+		{name: "map in a slice", v: []any{big}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w chunkWriter
+			if err := jsonify.Encode(&w, tt.v); err != nil {
+				t.Fatal(err)
+			}
+			if w.largest > 2*jsonify.EncodeChunkSize {
+				t.Errorf("Encode() wrote %d bytes at once, want at most %d", w.largest, 2*jsonify.EncodeChunkSize)
+			}
+			if w.writes < w.Len()/(2*jsonify.EncodeChunkSize) {
+				t.Errorf("Encode() wrote %d bytes in %d writes", w.Len(), w.writes)
+			}
+			if want := jsonify.MustString(tt.v); w.String() != want {
+				t.Error("Encode() wrote other output than String()")
+			}
+		})
+	}
+}
+
+func TestEncode_errors(t *testing.T) {
+	if err := jsonify.Encode(&strings.Builder{}, 1, jsonify.WithIndent("", " ")); err == nil {
+		t.Error("Encode() with WithIndent error = nil, want error")
+	}
+	if err := jsonify.Encode(&strings.Builder{}, []float64{1, math.NaN()}); err == nil {
+		t.Error("Encode(NaN) error = nil, want error")
+	}
+	big := make([]string, 10_000)
+	for i := range big {
+		big[i] = strings.Repeat("x", 100)
+	}
+	var w chunkWriter
+	err := jsonify.Encode(&w, big, jsonify.WithMaxOutputBytes(100_000))
+	if !errors.Is(err, jsonify.ErrOutputTooLarge) {
+		t.Errorf("Encode() error = %v, want %v", err, jsonify.ErrOutputTooLarge)
+	}
+	if w.Len() > 100_000 {
+		t.Errorf("Encode() wrote %d bytes, more than the limit", w.Len())
+	}
+	if err := jsonify.Encode(errorWriter{}, big); !errors.Is(err, errWrite) {
+		t.Errorf("Encode() error = %v, want %v", err, errWrite)
+	}
+}
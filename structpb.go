@@ -0,0 +1,178 @@
+//go:build !jsonify_noproto
+
+package jsonify
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToValue converts v into a google.protobuf.Value by encoding it with
+// [Bytes], so that it holds the same JSON as the rest of the output of
+// jsonify: time.Time as RFC 3339 strings, [json.RawMessage] as is, and
+// protobuf messages in their protojson form, at any depth. This differs from
+// [structpb.NewValue], which only accepts maps, slices and scalars.
+//
+// Numbers are held as float64 values, as in any google.protobuf.Value, so
+// integers beyond 2^53 lose precision; use [WithInt64] with
+// [Int64AsStringIfUnsafe] to keep them as strings.
+func ToValue(v any, opts ...Option) (*structpb.Value, error) {
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	value := &structpb.Value{}
+	if err := protojson.Unmarshal(b, value); err != nil {
+		return nil, fmt.Errorf("jsonify: ToValue of %T: %w", v, err)
+	}
+	return value, nil
+}
+
+// ToStruct is [ToValue] for a value that encodes as a JSON object, e.g. a
+// struct or a map, converted into a google.protobuf.Struct.
+func ToStruct(v any, opts ...Option) (*structpb.Struct, error) {
+	value, err := ToValue(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := value.GetStructValue()
+	if s == nil {
+		return nil, fmt.Errorf("jsonify: ToStruct of %T, which does not encode as an object", v)
+	}
+	return s, nil
+}
+
+// FromStruct converts s into a map[string]any, the inverse of [ToStruct]:
+// nested Structs become maps, ListValues []any slices and numbers float64
+// values, as [encoding/json] decodes them. A nil s converts into a nil map.
+// The map encodes with its keys sorted, as s does.
+func FromStruct(s *structpb.Struct) map[string]any {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
+
+// FromValue converts v into a native Go value, the inverse of [ToValue]. A
+// nil v converts into nil. As protojson encodes it, a NaN or infinite
+// number converts into the string "NaN", "Infinity" or "-Infinity".
+func FromValue(v *structpb.Value) any {
+	if v == nil {
+		return nil
+	}
+	return v.AsInterface()
+}
+
+// marshalStructpb encodes m without protojson if it is a
+// google.protobuf.Struct, Value or ListValue, which are common as payloads
+// and costly to encode through protoreflect. The output is the compacted
+// output of protojson. It reports false if m is none of them.
+func marshalStructpb(m proto.Message) ([]byte, bool, error) {
+	var b []byte
+	var err error
+	switch m := m.(type) {
+	case *structpb.Struct:
+		b, err = appendStructpbStruct(nil, m)
+	case *structpb.Value:
+		b, err = appendStructpbValue(nil, m)
+	case *structpb.ListValue:
+		b, err = appendStructpbList(nil, m)
+	default:
+		return nil, false, nil
+	}
+	return b, true, err
+}
+
+func appendStructpbStruct(b []byte, s *structpb.Struct) ([]byte, error) {
+	keys := slices.Sorted(maps.Keys(s.GetFields()))
+	b = append(b, '{')
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = appendProtoString(b, k); err != nil {
+			return nil, err
+		}
+		b = append(b, ':')
+		if b, err = appendStructpbValue(b, s.Fields[k]); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+func appendStructpbList(b []byte, l *structpb.ListValue) ([]byte, error) {
+	b = append(b, '[')
+	for i, v := range l.GetValues() {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = appendStructpbValue(b, v); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, ']'), nil
+}
+
+func appendStructpbValue(b []byte, v *structpb.Value) ([]byte, error) {
+	switch k := v.GetKind().(type) {
+	case *structpb.Value_NullValue:
+		return append(b, "null"...), nil
+	case *structpb.Value_BoolValue:
+		return strconv.AppendBool(b, k.BoolValue), nil
+	case *structpb.Value_NumberValue:
+		f := k.NumberValue
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("jsonify: google.protobuf.Value.number_value: invalid %v value", f)
+		}
+		return appendProtoFloat(b, f, 64), nil
+	case *structpb.Value_StringValue:
+		return appendProtoString(b, k.StringValue)
+	case *structpb.Value_StructValue:
+		return appendStructpbStruct(b, k.StructValue)
+	case *structpb.Value_ListValue:
+		return appendStructpbList(b, k.ListValue)
+	default:
+		return nil, fmt.Errorf("jsonify: google.protobuf.Value: none of the oneof fields is set")
+	}
+}
+
+// appendProtoString appends s as protojson encodes strings: without
+// escaping HTML characters, and failing on invalid UTF-8.
+func appendProtoString(b []byte, s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("jsonify: invalid UTF-8 in string %q", s)
+	}
+	b = append(b, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b = append(b, '\\', byte(r))
+		case r == '\n':
+			b = append(b, `\n`...)
+		case r == '\r':
+			b = append(b, `\r`...)
+		case r == '\t':
+			b = append(b, `\t`...)
+		case r == '\b':
+			b = append(b, `\b`...)
+		case r == '\f':
+			b = append(b, `\f`...)
+		case r < ' ':
+			b = fmt.Appendf(b, `\u%04x`, r)
+		default:
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return append(b, '"'), nil
+}
@@ -0,0 +1,173 @@
+//go:build !jsonify_noproto
+
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleToStruct() {
+	type event struct {
+		Name    string          `json:"name"`
+		At      time.Time       `json:"at"`
+		Timeout any             `json:"timeout"`
+		Extra   json.RawMessage `json:"extra"`
+	}
+	s, err := jsonify.ToStruct(event{
+		Name:    "deploy",
+		At:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeout: durationpb.New(90 * time.Second),
+		Extra:   json.RawMessage(`{"retries":[1,2]}`),
+	})
+	fmt.Println(s.Fields["at"].GetStringValue(), s.Fields["timeout"].GetStringValue(), err)
+	fmt.Println(s.Fields["extra"].AsInterface())
+	// Output:
+	// 2024-01-02T03:04:05Z 90s <nil>
+	// map[retries:[1 2]]
+}
+
+func TestToValue(t *testing.T) {
+	nested, _ := structpb.NewStruct(map[string]any{"a": 1})
+	tests := []struct {
+		name string
+		v    any
+		opts []jsonify.Option
+		want string
+	}{
+		{name: "nil", v: nil, want: `null`},
+		{name: "string", v: "a", want: `"a"`},
+		{name: "slice", v: []int{1, 2}, want: `[1,2]`},
+		{name: "nested proto", v: map[string]any{"n": nested}, want: `{"n":{"a":1}}`},
+		{name: "large int64", v: int64(math.MaxInt64), want: `9223372036854775807`},
+		{name: "int64 as string", v: int64(math.MaxInt64), opts: []jsonify.Option{jsonify.WithInt64(jsonify.Int64AsStringIfUnsafe)}, want: `"9223372036854775807"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.ToValue(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := protojson.Marshal(got)
+			var gotAny, wantAny any
+			json.Unmarshal(b, &gotAny)
+			json.Unmarshal([]byte(tt.want), &wantAny)
+			if !reflect.DeepEqual(gotAny, wantAny) {
+				t.Errorf("ToValue() = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestToStruct_notObject(t *testing.T) {
+	if _, err := jsonify.ToStruct([]int{1}); err == nil {
+		t.Error("ToStruct() error = nil, want error")
+	}
+	if _, err := jsonify.ToStruct(nil); err == nil {
+		t.Error("ToStruct(nil) error = nil, want error")
+	}
+}
+
+func ExampleFromStruct() {
+	s, _ := structpb.NewStruct(map[string]any{"name": "Ann", "tags": []any{"a", "b"}})
+	m := jsonify.FromStruct(s)
+	fmt.Println(m["name"], m["tags"])
+	// Output:
+	// Ann [a b]
+}
+
+func TestFromValue(t *testing.T) {
+	if got := jsonify.FromStruct(nil); got != nil {
+		t.Errorf("FromStruct(nil) = %v, want nil", got)
+	}
+	if got := jsonify.FromValue(nil); got != nil {
+		t.Errorf("FromValue(nil) = %v, want nil", got)
+	}
+	v := map[string]any{"a": []any{1.5, true, nil, "x"}, "b": map[string]any{"c": 2.0}}
+	value, err := jsonify.ToValue(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := jsonify.FromValue(value); !reflect.DeepEqual(got, v) {
+		t.Errorf("FromValue(ToValue(v)) = %v, want %v", got, v)
+	}
+}
+
+// TestString_structpb checks that Struct, Value and ListValue messages,
+// which are encoded without protojson, are encoded as protojson does.
+func TestString_structpb(t *testing.T) {
+	list, _ := structpb.NewList([]any{1, "two", nil})
+	s, _ := structpb.NewStruct(map[string]any{
+		"z":       "last",
+		"a":       "<html> & \"quotes\" \\ \n\t\u0001\u2028 é",
+		"numbers": []any{0, -1, 1.5, 1e21, 1e-7, 123456789, math.MaxInt64},
+		"nested":  map[string]any{"b": true, "a": false},
+		"empty":   map[string]any{},
+	})
+	tests := []struct {
+		name string
+		m    proto.Message
+	}{
+		{name: "struct", m: s},
+		{name: "list", m: list},
+		{name: "value", m: structpb.NewStructValue(s)},
+		{name: "null", m: structpb.NewNullValue()},
+		{name: "empty struct", m: &structpb.Struct{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := protojson.Marshal(tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ = jsonify.Compact(want)
+			got, err := jsonify.String(tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("String() = %s, want %s", got, want)
+			}
+			nested, err := jsonify.String(map[string]any{"m": tt.m})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantNested := `{"m":` + string(want) + `}`; nested != wantNested {
+				t.Errorf("String() nested = %s, want %s", nested, wantNested)
+			}
+		})
+	}
+}
+
+func TestString_structpbErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		m    proto.Message
+	}{
+		{name: "NaN", m: structpb.NewNumberValue(math.NaN())},
+		{name: "infinity", m: structpb.NewNumberValue(math.Inf(1))},
+		{name: "unset", m: &structpb.Value{}},
+		{name: "nil field", m: &structpb.Struct{Fields: map[string]*structpb.Value{"a": nil}}},
+		{name: "invalid UTF-8", m: structpb.NewStringValue("\xff")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := protojson.Marshal(tt.m); err == nil {
+				t.Fatal("protojson.Marshal() error = nil, want error")
+			}
+			if _, err := jsonify.String(tt.m); err == nil {
+				t.Error("String() error = nil, want error")
+			}
+		})
+	}
+}
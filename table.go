@@ -0,0 +1,95 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Table returns a [json.Marshaler] that encodes rows, a slice or array of
+// structs or maps, in a compact tabular form:
+//
+//	{"columns":["id","name"],"rows":[[1,"Ann"],[2,"Bob"]]}
+//
+// Each row is encoded with jsonify first, so the columns are the JSON names
+// of the fields, in order of first appearance across the rows. A row missing
+// a column, e.g. because of omitempty, has null in that cell.
+//
+// Use [DecodeTable] to decode the tabular form back into a slice.
+func Table(rows any) json.Marshaler {
+	return optionsMarshalerFunc(func(o *options) ([]byte, error) { return marshalTable(rows, o) })
+}
+
+type tableJSON struct {
+	Columns []string            `json:"columns"`
+	Rows    [][]json.RawMessage `json:"rows"`
+}
+
+func marshalTable(rows any, o *options) ([]byte, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("jsonify: Table of %T, want a slice or array", rows)
+	}
+	t := tableJSON{Columns: []string{}, Rows: make([][]json.RawMessage, rv.Len())}
+	index := map[string]int{}
+	cells := make([]map[int]json.RawMessage, rv.Len())
+	for i := range cells {
+		b, err := o.marshal(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		keys, values, err := objectMembers(b)
+		if err != nil {
+			return nil, fmt.Errorf("jsonify: Table row %d: %w", i, err)
+		}
+		cells[i] = make(map[int]json.RawMessage, len(keys))
+		for k, key := range keys {
+			col, ok := index[key]
+			if !ok {
+				col = len(t.Columns)
+				index[key] = col
+				t.Columns = append(t.Columns, key)
+			}
+			cells[i][col] = values[k]
+		}
+	}
+	for i, row := range cells {
+		t.Rows[i] = make([]json.RawMessage, len(t.Columns))
+		for col := range t.Rows[i] {
+			if v, ok := row[col]; ok {
+				t.Rows[i][col] = v
+			} else {
+				t.Rows[i][col] = json.RawMessage("null")
+			}
+		}
+	}
+	return o.marshal(t)
+}
+
+// DecodeTable decodes data in the tabular form produced by [Table] into rows,
+// which must be a pointer to a slice.
+//
+// Each row is turned back into a JSON object and decoded with [Decode].
+func DecodeTable(data []byte, rows any) error {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("jsonify: DecodeTable into %T, want a pointer to a slice", rows)
+	}
+	var t tableJSON
+	if err := Decode(data, &t); err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(rv.Elem().Type(), len(t.Rows), len(t.Rows))
+	var obj []byte
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("jsonify: table row %d has %d cells, want %d", i, len(row), len(t.Columns))
+		}
+		obj = appendObject(obj[:0], t.Columns, row)
+		if err := Decode(obj, slice.Index(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("jsonify: table row %d: %w", i, err)
+		}
+	}
+	rv.Elem().Set(slice)
+	return nil
+}
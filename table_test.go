@@ -0,0 +1,86 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type tableRow struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Note string `json:"note,omitempty"`
+}
+
+func ExampleTable() {
+	rows := []tableRow{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	fmt.Println(jsonify.MustString(jsonify.Table(rows)))
+	// Output:
+	// {"columns":["id","name"],"rows":[[1,"Ann"],[2,"Bob"]]}
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			input:    []tableRow(nil),
+			expected: `{"columns":[],"rows":[]}`,
+		},
+		{
+			name:     "missing cells",
+			input:    []tableRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b", Note: "x"}},
+			expected: `{"columns":["id","name","note"],"rows":[[1,"a",null],[2,"b","x"]]}`,
+		},
+		{
+			name:     "maps",
+			input:    [2]map[string]any{{"b": 1, "a": true}, {"c": []int{1}}},
+			expected: `{"columns":["a","b","c"],"rows":[[true,1,null],[null,null,[1]]]}`,
+		},
+		{name: "not a slice", input: tableRow{}, wantErr: true},
+		{name: "not objects", input: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(jsonify.Table(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("String() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeTable(t *testing.T) {
+	rows := []tableRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b", Note: "x"}}
+	data := jsonify.MustBytes(jsonify.Table(rows))
+	var got []tableRow
+	if err := jsonify.DecodeTable(data, &got); err != nil {
+		t.Fatalf("DecodeTable() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("DecodeTable() = %v, want %v", got, rows)
+	}
+
+	invalid := []string{
+		`{"columns":["id"],"rows":[[1,2]]}`,
+		`{"columns":["id"],"rows":[["x"]]}`,
+		`[]`,
+	}
+	for _, input := range invalid {
+		if err := jsonify.DecodeTable([]byte(input), &got); err == nil {
+			t.Errorf("DecodeTable(%s) error = nil, want error", input)
+		}
+	}
+	if err := jsonify.DecodeTable(data, got); err == nil {
+		t.Errorf("DecodeTable() into a non-pointer error = nil, want error")
+	}
+}
@@ -0,0 +1,243 @@
+package jsonify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Schema is a subset of JSON Schema describing the types of the values
+// decoded by [DecodeTyped]. It understands the keywords type, format,
+// properties, additionalProperties and items; other keywords are ignored.
+type Schema struct {
+	types      []string
+	format     string
+	properties map[string]*Schema
+	additional *Schema
+	items      *Schema
+}
+
+type schemaJSON struct {
+	Type                 json.RawMessage    `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+}
+
+// ParseSchema parses a JSON Schema for [DecodeTyped].
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := Decode(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if string(data) == "true" || string(data) == "false" {
+		*s = Schema{}
+		return nil
+	}
+	var v schemaJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	out := Schema{format: v.Format, properties: v.Properties, items: v.Items}
+	if len(v.Type) > 0 {
+		if v.Type[0] == '[' {
+			if err := json.Unmarshal(v.Type, &out.types); err != nil {
+				return err
+			}
+		} else {
+			var t string
+			if err := json.Unmarshal(v.Type, &t); err != nil {
+				return err
+			}
+			out.types = []string{t}
+		}
+	}
+	if len(v.AdditionalProperties) > 0 && v.AdditionalProperties[0] == '{' {
+		out.additional = &Schema{}
+		if err := out.additional.UnmarshalJSON(v.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+	*s = out
+	return nil
+}
+
+// DecodeTyped decodes data into map[string]any, []any and leaf values whose
+// types follow schema, for generic pipelines that cannot declare Go structs:
+//
+//   - "integer" decodes as int64, and fails unless the number is integral.
+//   - "number" decodes as float64.
+//   - "string" with the format "date-time" decodes as [time.Time] from
+//     RFC 3339, and with the format "date" from the form "2006-01-02".
+//   - "boolean", "null", other strings, "object" and "array" decode as
+//     [Decode] would.
+//
+// A type given as a list, e.g. ["integer", "null"], allows a null value.
+// Values the schema does not describe decode as [Decode] would, with numbers
+// as float64. A value that does not match its type fails with an error naming
+// its JSON Pointer.
+func DecodeTyped(data []byte, schema *Schema) (any, error) {
+	var v any
+	if err := Decode(data, &v, UseNumber()); err != nil {
+		return nil, err
+	}
+	return schema.convert(v, nil)
+}
+
+func (s *Schema) convert(v any, path []string) (any, error) {
+	if s == nil {
+		return untype(v), nil
+	}
+	if v == nil && (len(s.types) == 0 || slices.Contains(s.types, "null")) {
+		return nil, nil
+	}
+	types := slices.DeleteFunc(slices.Clone(s.types), func(t string) bool { return t == "null" })
+	if len(types) == 0 {
+		return s.convertAny(v, path)
+	}
+	var err error
+	for _, t := range types {
+		var out any
+		if out, err = s.convertType(t, v, path); err == nil {
+			return out, nil
+		}
+	}
+	return nil, err
+}
+
+// convertAny converts v, whose type is not given, using the keywords that
+// apply to its JSON type.
+func (s *Schema) convertAny(v any, path []string) (any, error) {
+	switch v.(type) {
+	case map[string]any:
+		return s.convertType("object", v, path)
+	case []any:
+		return s.convertType("array", v, path)
+	case string:
+		return s.convertType("string", v, path)
+	}
+	return untype(v), nil
+}
+
+func (s *Schema) convertType(t string, v any, path []string) (any, error) {
+	mismatch := func() error {
+		return fmt.Errorf("jsonify: %s: expected %s, got %s", formatPointer(path), t, typeName(v))
+	}
+	switch t {
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, mismatch()
+		}
+		if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := n.Float64()
+		if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, fmt.Errorf("jsonify: %s: %s is not an int64", formatPointer(path), n)
+		}
+		return int64(f), nil
+	case "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, mismatch()
+		}
+		return n.Float64()
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return nil, mismatch()
+		}
+		layout := map[string]string{"date-time": time.RFC3339Nano, "date": time.DateOnly}[s.format]
+		if layout == "" {
+			return str, nil
+		}
+		tm, err := time.Parse(layout, str)
+		if err != nil {
+			return nil, fmt.Errorf("jsonify: %s: invalid %s %q", formatPointer(path), s.format, str)
+		}
+		return tm, nil
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return nil, mismatch()
+		}
+		return v, nil
+	case "object":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, mismatch()
+		}
+		out := make(map[string]any, len(m))
+		for key, elem := range m {
+			sub, ok := s.properties[key]
+			if !ok {
+				sub = s.additional
+			}
+			conv, err := sub.convert(elem, append(path, key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = conv
+		}
+		return out, nil
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			return nil, mismatch()
+		}
+		out := make([]any, len(a))
+		for i, elem := range a {
+			conv, err := s.items.convert(elem, append(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = conv
+		}
+		return out, nil
+	}
+	return untype(v), nil
+}
+
+// untype turns the numbers of v into float64, as [Decode] decodes them.
+func untype(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = untype(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = untype(elem)
+		}
+	}
+	return v
+}
+
+// typeName returns the JSON Schema type name of a decoded value.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,83 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleDecodeTyped() {
+	schema, _ := jsonify.ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"score": {"type": "number"},
+			"created": {"type": "string", "format": "date-time"}
+		}
+	}`))
+	v, err := jsonify.DecodeTyped([]byte(`{"id":9007199254740993,"score":1,"created":"2024-05-01T12:00:00Z"}`), schema)
+	m := v.(map[string]any)
+	fmt.Printf("%T %v\n", m["id"], m["id"])
+	fmt.Printf("%T %v\n", m["score"], m["score"])
+	fmt.Printf("%T %v\n", m["created"], m["created"])
+	fmt.Println(err)
+	// Output:
+	// int64 9007199254740993
+	// float64 1
+	// time.Time 2024-05-01 12:00:00 +0000 UTC
+	// <nil>
+}
+
+func TestDecodeTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		input    string
+		expected any
+		wantErr  bool
+	}{
+		{name: "untyped", schema: `{}`, input: `{"a":[1,"x"]}`, expected: map[string]any{"a": []any{1.0, "x"}}},
+		{name: "items", schema: `{"type":"array","items":{"type":"integer"}}`, input: `[1,2e3]`, expected: []any{int64(1), int64(2000)}},
+		{name: "nullable", schema: `{"type":["integer","null"]}`, input: `null`, expected: nil},
+		{name: "date", schema: `{"type":"string","format":"date"}`, input: `"2024-02-29"`, expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "other format", schema: `{"type":"string","format":"email"}`, input: `"a@b"`, expected: "a@b"},
+		{name: "union", schema: `{"type":["integer","string"]}`, input: `"7"`, expected: "7"},
+		{
+			name:     "additional properties",
+			schema:   `{"properties":{"a":{"type":"number"}},"additionalProperties":{"type":"integer"}}`,
+			input:    `{"a":1,"b":2}`,
+			expected: map[string]any{"a": 1.0, "b": int64(2)},
+		},
+		{name: "fraction", schema: `{"type":"integer"}`, input: `1.5`, wantErr: true},
+		{name: "mismatch", schema: `{"properties":{"a":{"items":{"type":"boolean"}}}}`, input: `{"a":[true,1]}`, wantErr: true},
+		{name: "null", schema: `{"type":"integer"}`, input: `null`, wantErr: true},
+		{name: "bad time", schema: `{"type":"string","format":"date-time"}`, input: `"yesterday"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := jsonify.ParseSchema([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("ParseSchema() error = %v", err)
+			}
+			got, err := jsonify.DecodeTyped([]byte(tt.input), schema)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("DecodeTyped() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DecodeTyped() = %#v, %v, want %#v", got, err, tt.expected)
+			}
+		})
+	}
+
+	schema, _ := jsonify.ParseSchema([]byte(`{"properties":{"a":{"items":{"type":"boolean"}}}}`))
+	_, err := jsonify.DecodeTyped([]byte(`{"a":[true,1]}`), schema)
+	if want := "jsonify: /a/1: expected boolean, got number"; err == nil || err.Error() != want {
+		t.Errorf("DecodeTyped() error = %v, want %v", err, want)
+	}
+}
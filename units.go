@@ -0,0 +1,139 @@
+package jsonify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Millis is a [time.Duration] that encodes as a JSON number of milliseconds.
+//
+// Fractional milliseconds are preserved, e.g. Millis(1500*time.Microsecond)
+// encodes as 1.5.
+type Millis time.Duration
+
+// MarshalJSON implements [json.Marshaler].
+func (d Millis) MarshalJSON() ([]byte, error) {
+	ms := float64(d) / float64(time.Millisecond)
+	return strconv.AppendFloat(nil, ms, 'f', -1, 64), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+//
+// It accepts a JSON number of milliseconds.
+func (d *Millis) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	ms, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("jsonify: invalid Millis %s", data)
+	}
+	*d = Millis(math.Round(ms * float64(time.Millisecond)))
+	return nil
+}
+
+// Bytes64 is a byte count that encodes as a JSON string using IEC binary
+// prefixes, e.g. Bytes64(1536) encodes as "1.5 KiB".
+//
+// Values are rounded to at most two decimal places.
+// Values below 1024 are encoded with the "B" unit, e.g. "512 B".
+type Bytes64 int64
+
+var iecUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// String returns the IEC representation of n.
+func (n Bytes64) String() string {
+	v := float64(n)
+	i := 0
+	for math.Abs(v) >= 1024 && i < len(iecUnits)-1 {
+		v /= 1024
+		i++
+	}
+	v = math.Round(v*100) / 100
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + iecUnits[i]
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (n Bytes64) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, n.String()), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+//
+// It accepts an IEC string such as "1.5 KiB" or a JSON number of bytes.
+func (n *Bytes64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		v, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("jsonify: invalid Bytes64 %s", data)
+		}
+		*n = Bytes64(v)
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("jsonify: invalid Bytes64 %s", data)
+	}
+	num, unit, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return fmt.Errorf("jsonify: invalid Bytes64 %q: missing unit", s)
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return fmt.Errorf("jsonify: invalid Bytes64 %q", s)
+	}
+	for i, u := range iecUnits {
+		if u == unit {
+			*n = Bytes64(math.Round(v * math.Pow(1024, float64(i))))
+			return nil
+		}
+	}
+	return fmt.Errorf("jsonify: invalid Bytes64 %q: unknown unit %q", s, unit)
+}
+
+// Percent is a percentage that encodes as a JSON string with a "%" suffix,
+// e.g. Percent(12.5) encodes as "12.5%".
+//
+// The value is the percentage itself, not a ratio; use Percent(ratio*100)
+// for ratios.
+type Percent float64
+
+// MarshalJSON implements [json.Marshaler].
+func (p Percent) MarshalJSON() ([]byte, error) {
+	f := float64(p)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, errors.New("jsonify: unsupported Percent value " + strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	b := append([]byte{'"'}, strconv.FormatFloat(f, 'f', -1, 64)...)
+	return append(b, '%', '"'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+//
+// It accepts a string such as "12.5%" or a JSON number of percent.
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return fmt.Errorf("jsonify: invalid Percent %s", data)
+		}
+		s = strings.TrimSuffix(s, "%")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("jsonify: invalid Percent %s", data)
+	}
+	*p = Percent(f)
+	return nil
+}
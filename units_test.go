@@ -0,0 +1,75 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleMillis() {
+	fmt.Println(jsonify.MustString(map[string]any{
+		"latency": jsonify.Millis(1500 * time.Microsecond),
+		"size":    jsonify.Bytes64(1536),
+		"usage":   jsonify.Percent(12.5),
+	}))
+	// Output:
+	// {"latency":1.5,"size":"1.5 KiB","usage":"12.5%"}
+}
+
+func TestUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{"millis", jsonify.Millis(2 * time.Second), `2000`},
+		{"millis fraction", jsonify.Millis(1500 * time.Microsecond), `1.5`},
+		{"bytes", jsonify.Bytes64(512), `"512 B"`},
+		{"kibibytes", jsonify.Bytes64(1536), `"1.5 KiB"`},
+		{"mebibytes rounded", jsonify.Bytes64(1234567), `"1.18 MiB"`},
+		{"negative", jsonify.Bytes64(-2048), `"-2 KiB"`},
+		{"percent", jsonify.Percent(99.9), `"99.9%"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnitsUnmarshal(t *testing.T) {
+	var v struct {
+		Latency jsonify.Millis
+		Size    jsonify.Bytes64
+		Raw     jsonify.Bytes64
+		Usage   jsonify.Percent
+		Ratio   jsonify.Percent
+	}
+	data := `{"Latency":1.5,"Size":"1.5 KiB","Raw":100,"Usage":"12.5%","Ratio":50}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if v.Latency != jsonify.Millis(1500*time.Microsecond) {
+		t.Errorf("Latency = %v", time.Duration(v.Latency))
+	}
+	if v.Size != 1536 || v.Raw != 100 {
+		t.Errorf("Size = %d, Raw = %d", v.Size, v.Raw)
+	}
+	if v.Usage != 12.5 || v.Ratio != 50 {
+		t.Errorf("Usage = %v, Ratio = %v", v.Usage, v.Ratio)
+	}
+
+	var n jsonify.Bytes64
+	if err := json.Unmarshal([]byte(`"1 XiB"`), &n); err == nil {
+		t.Errorf("Unmarshal() with unknown unit did not fail")
+	}
+}
@@ -0,0 +1,84 @@
+package jsonify
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// WithView returns an [Option] that encodes only the struct fields in the
+// named view, e.g. "summary" for list endpoints and "full" for detail
+// endpoints, without defining a struct per view.
+//
+// The fields of a view are declared with the view key of the jsonify tag
+// of each field:
+//
+//	type Order struct {
+//		ID    string `json:"id"`
+//		Items []Item `json:"items" jsonify:"view=standard,full"`
+//		Audit Audit  `json:"audit" jsonify:"view=full"`
+//	}
+//
+// Fields without the view key are in every view. A view registered with
+// [RegisterView] replaces the tags of the struct type for that view. The
+// view applies to structs at any depth, except inside values that marshal
+// themselves.
+//
+// Values are always encoded with the [Jsoniter] backend with a view,
+// whatever the backend set with [SetBackend], as the others cannot filter
+// fields.
+func WithView(name string) Option {
+	return func(o *options) {
+		o.mode.view = name
+	}
+}
+
+// RegisterView declares the view name of struct type S as the listed Go
+// fields, for types whose tags you don't control. It takes precedence over
+// the view keys of the jsonify tags of S.
+//
+// RegisterView panics if S is not a struct type or has no field named by
+// fields.
+//
+// Registering again for the same view replaces the previous fields.
+// Registrations are meant to be made during program initialization.
+func RegisterView[S any](name string, fields ...string) {
+	typ := reflect.TypeOf((*S)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonify: RegisterView of non-struct type %v", typ))
+	}
+	for _, field := range fields {
+		if _, ok := typ.FieldByName(field); !ok {
+			panic(fmt.Sprintf("jsonify: %v has no field %s", typ, field))
+		}
+	}
+	fields = slices.Clone(fields)
+	update(func(ext *extension) {
+		views := make(map[string][]string, len(ext.views[typ])+1)
+		for name, fields := range ext.views[typ] {
+			views[name] = fields
+		}
+		views[name] = fields
+		ext.views[typ] = views
+	})
+}
+
+// filterView hides the fields of desc that are not in the view name.
+// registered holds the views registered for the type of desc.
+func filterView(desc *jsoniter.StructDescriptor, name string, registered map[string][]string) {
+	fields, ok := registered[name]
+	for _, binding := range desc.Fields {
+		if ok {
+			if !slices.Contains(fields, binding.Field.Name()) {
+				binding.ToNames = []string{}
+			}
+			continue
+		}
+		views, tagged := jsonifyTag(binding.Field.Tag())["view"]
+		if tagged && !slices.Contains(views, name) {
+			binding.ToNames = []string{}
+		}
+	}
+}
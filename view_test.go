@@ -0,0 +1,93 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+type invoice struct {
+	ID    string   `json:"id"`
+	Lines []string `json:"lines" jsonify:"view=standard,full"`
+	Notes string   `json:"notes" jsonify:"view=full"`
+}
+
+type vendorInvoice struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+	Raw   string `json:"raw" jsonify:"view=summary"`
+}
+
+func init() {
+	jsonify.RegisterView[vendorInvoice]("summary", "ID", "Total")
+}
+
+func ExampleWithView() {
+	type Order struct {
+		ID    string   `json:"id"`
+		Items []string `json:"items" jsonify:"view=standard,full"`
+		Audit string   `json:"audit" jsonify:"view=full"`
+	}
+	orders := []Order{{ID: "o-1", Items: []string{"book"}, Audit: "created"}}
+	fmt.Println(jsonify.MustString(orders, jsonify.WithView("summary")))
+	fmt.Println(jsonify.MustString(orders, jsonify.WithView("full")))
+	// Output:
+	// [{"id":"o-1"}]
+	// [{"id":"o-1","items":["book"],"audit":"created"}]
+}
+
+func TestWithView(t *testing.T) {
+	inv := invoice{ID: "i", Lines: []string{"l"}, Notes: "n"}
+	vendor := vendorInvoice{ID: "v", Total: 3, Raw: "r"}
+	tests := []struct {
+		name     string
+		input    any
+		opts     []jsonify.Option
+		expected string
+	}{
+		{name: "no view", input: inv, expected: `{"id":"i","lines":["l"],"notes":"n"}`},
+		{name: "summary", input: inv, opts: []jsonify.Option{jsonify.WithView("summary")}, expected: `{"id":"i"}`},
+		{name: "standard", input: inv, opts: []jsonify.Option{jsonify.WithView("standard")}, expected: `{"id":"i","lines":["l"]}`},
+		{name: "registered", input: vendor, opts: []jsonify.Option{jsonify.WithView("summary")}, expected: `{"id":"v","total":3}`},
+		{name: "unregistered view", input: vendor, opts: []jsonify.Option{jsonify.WithView("full")}, expected: `{"id":"v","total":3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.input, tt.opts...)
+			if err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("String() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, fn := range []func(){
+		func() { jsonify.RegisterView[int]("summary") },
+		func() { jsonify.RegisterView[invoice]("summary", "Missing") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterView() did not panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestWithView_backend(t *testing.T) {
+	jsonify.SetBackend(jsonify.Stdlib)
+	defer jsonify.SetBackend(nil)
+	in := invoice{ID: "i-1", Lines: []string{"a"}, Notes: "internal"}
+	got, err := jsonify.String(in, jsonify.WithView("summary"))
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+	if want := `{"id":"i-1"}`; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
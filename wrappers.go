@@ -0,0 +1,120 @@
+package jsonify
+
+import (
+	"encoding/base64"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// wrappersPkgPath is the package of the google.protobuf wrapper messages,
+// which are recognized by their Go types so that they are flattened with
+// the jsonify_noproto build tag too.
+const wrappersPkgPath = "google.golang.org/protobuf/types/known/wrapperspb"
+
+// WithFlatWrappers returns an [Option] that encodes the wrapper messages of
+// google.protobuf, e.g. StringValue and Int32Value, as their scalar value,
+// or null for a nil pointer, as protojson does, where they would otherwise
+// be encoded as Go structs: with [WithProtoAsGo], or with the
+// jsonify_noproto build tag.
+//
+//	type Profile struct {
+//		Nickname *wrapperspb.StringValue `json:"nickname"`
+//	}
+//	jsonify.String(Profile{wrapperspb.String("Ann")}, jsonify.WithProtoAsGo(), jsonify.WithFlatWrappers())
+//	// {"nickname":"Ann"}
+//
+// As with protojson, Int64Value and UInt64Value are encoded as strings,
+// BytesValue in base64, and NaN and infinite floats as "NaN", "Infinity" and
+// "-Infinity".
+func WithFlatWrappers() Option {
+	return func(o *options) {
+		o.mode.flatWrappers = true
+	}
+}
+
+func wrapperEncoderOf(typ reflect2.Type) jsoniter.ValEncoder {
+	t := typ.Type1()
+	if t.Kind() != reflect.Struct || t.PkgPath() != wrappersPkgPath || !strings.HasSuffix(t.Name(), "Value") {
+		return nil
+	}
+	field := typ.(reflect2.StructType).FieldByName("Value")
+	if field == nil {
+		return nil
+	}
+	return &wrapperEncoder{field: field}
+}
+
+type wrapperEncoder struct {
+	field reflect2.StructField
+}
+
+func (e *wrapperEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (e *wrapperEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	v := e.field.UnsafeGet(ptr)
+	switch e.field.Type().Kind() {
+	case reflect.Bool:
+		stream.WriteBool(*(*bool)(v))
+	case reflect.String:
+		stream.WriteString(*(*string)(v))
+	case reflect.Int32:
+		stream.WriteInt32(*(*int32)(v))
+	case reflect.Uint32:
+		stream.WriteUint32(*(*uint32)(v))
+	case reflect.Int64:
+		stream.WriteString(strconv.FormatInt(*(*int64)(v), 10))
+	case reflect.Uint64:
+		stream.WriteString(strconv.FormatUint(*(*uint64)(v), 10))
+	case reflect.Float32:
+		writeProtoFloat(stream, float64(*(*float32)(v)), 32)
+	case reflect.Float64:
+		writeProtoFloat(stream, *(*float64)(v), 64)
+	case reflect.Slice:
+		stream.WriteString(base64.StdEncoding.EncodeToString(*(*[]byte)(v)))
+	default:
+		stream.WriteNil()
+	}
+}
+
+// writeProtoFloat writes f as protojson encodes floats of bitSize bits.
+func writeProtoFloat(stream *jsoniter.Stream, f float64, bitSize int) {
+	switch {
+	case math.IsNaN(f):
+		stream.WriteString("NaN")
+	case math.IsInf(f, 1):
+		stream.WriteString("Infinity")
+	case math.IsInf(f, -1):
+		stream.WriteString("-Infinity")
+	default:
+		stream.SetBuffer(appendProtoFloat(stream.Buffer(), f, bitSize))
+	}
+}
+
+// appendProtoFloat appends the finite f as protojson encodes floats of
+// bitSize bits, which is as [encoding/json] does.
+func appendProtoFloat(b []byte, f float64, bitSize int) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, format, -1, bitSize)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
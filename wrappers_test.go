@@ -0,0 +1,63 @@
+package jsonify_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/goaux/jsonify"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func ExampleWithFlatWrappers() {
+	type Profile struct {
+		Nickname *wrapperspb.StringValue `json:"nickname"`
+		Age      *wrapperspb.Int32Value  `json:"age"`
+	}
+	s, err := jsonify.String(Profile{Nickname: wrapperspb.String("Ann")}, jsonify.WithProtoAsGo(), jsonify.WithFlatWrappers())
+	fmt.Println(s, err)
+	// Output:
+	// {"nickname":"Ann","age":null} <nil>
+}
+
+func TestWithFlatWrappers(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{name: "bool", v: wrapperspb.Bool(true), want: `true`},
+		{name: "string", v: wrapperspb.String("<a>"), want: `"<a>"`},
+		{name: "int32", v: wrapperspb.Int32(-1), want: `-1`},
+		{name: "uint32", v: wrapperspb.UInt32(math.MaxUint32), want: `4294967295`},
+		{name: "int64", v: wrapperspb.Int64(math.MinInt64), want: `"-9223372036854775808"`},
+		{name: "uint64", v: wrapperspb.UInt64(math.MaxUint64), want: `"18446744073709551615"`},
+		{name: "float", v: wrapperspb.Float(0.1), want: `0.1`},
+		{name: "double", v: wrapperspb.Double(1e21), want: `1e+21`},
+		{name: "small double", v: wrapperspb.Double(1e-7), want: `1e-7`},
+		{name: "NaN", v: wrapperspb.Double(math.NaN()), want: `"NaN"`},
+		{name: "infinity", v: wrapperspb.Float(float32(math.Inf(-1))), want: `"-Infinity"`},
+		{name: "bytes", v: wrapperspb.Bytes([]byte("hi")), want: `"aGk="`},
+		{name: "nil", v: (*wrapperspb.StringValue)(nil), want: `null`},
+		{name: "value", v: []wrapperspb.Int32Value{{Value: 1}}, want: `[1]`},
+		{
+			name: "nested",
+			v: map[string]any{"a": struct {
+				B *wrapperspb.BoolValue `json:"b,omitempty"`
+				C *wrapperspb.BoolValue `json:"c"`
+			}{C: wrapperspb.Bool(false)}},
+			want: `{"a":{"c":false}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.String(tt.v, jsonify.WithProtoAsGo(), jsonify.WithFlatWrappers())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package jsonify
+
+import "io"
+
+// WriterTo returns an [io.WriterTo] that encodes v with opts when its
+// WriteTo method is called, for APIs that take one, e.g. to upload a value
+// without encoding it up front:
+//
+//	_, err := jsonify.WriterTo(report, jsonify.WithIndent("", "  ")).WriteTo(file)
+//
+// Each call of WriteTo encodes v again, so it reflects changes made to v in
+// between. WriteTo returns the number of bytes written, and the error of
+// encoding or of the writer.
+func WriterTo(v any, opts ...Option) io.WriterTo {
+	return &writerTo{v: v, opts: opts}
+}
+
+type writerTo struct {
+	v    any
+	opts []Option
+}
+
+func (wt *writerTo) WriteTo(w io.Writer) (int64, error) {
+	b, err := Bytes(wt.v, wt.opts...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
@@ -0,0 +1,53 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleWriterTo() {
+	wt := jsonify.WriterTo(map[string]int{"b": 2, "a": 1})
+	n, err := wt.WriteTo(os.Stdout)
+	fmt.Println()
+	fmt.Println(n, err)
+	// Output:
+	// {"a":1,"b":2}
+	// 13 <nil>
+}
+
+func TestWriterTo(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	u := &user{Name: "Ann"}
+	wt := jsonify.WriterTo(u, jsonify.WithIndent("", " "))
+	u.Name = "Bob" // Encoded lazily.
+	var buf bytes.Buffer
+	n, err := wt.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n \"name\": \"Bob\"\n}"; buf.String() != want {
+		t.Errorf("WriteTo() wrote %q, want %q", buf.String(), want)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, want %d", n, buf.Len())
+	}
+
+	var w countingWriter
+	if _, err := jsonify.WriterTo([]float64{math.NaN()}).WriteTo(&w); err == nil {
+		t.Error("WriteTo(NaN) error = nil, want error")
+	}
+	if w.writes != 0 {
+		t.Errorf("WriteTo(NaN) wrote %d times, want 0", w.writes)
+	}
+	if _, err := jsonify.WriterTo(1).WriteTo(errorWriter{}); !errors.Is(err, errWrite) {
+		t.Errorf("WriteTo() error = %v, want %v", err, errWrite)
+	}
+}
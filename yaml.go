@@ -0,0 +1,144 @@
+package jsonify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// YAMLBytes returns v encoded as YAML, converted from its encoding with
+// [Bytes] and opts, so that config dumps show what the JSON APIs do: the
+// same field names, e.g. json tags and protojson names, the same sorted
+// map keys, and the same values, e.g. of [json.RawMessage] and of
+// marshalers:
+//
+//	jsonify.YAMLBytes(map[string]any{"name": "Ann", "tags": []string{"a", "b"}})
+//	// name: Ann
+//	// tags:
+//	//   - a
+//	//   - b
+//
+// Objects and arrays are written in block style, except empty ones, which
+// are written as {} and []. Numbers, booleans and null are written as in
+// JSON, and strings as plain scalars if they cannot be read as anything
+// else, or else as JSON strings, which YAML reads as double-quoted scalars.
+// The members of objects keep the order of the JSON encoding.
+func YAMLBytes(v any, opts ...Option) ([]byte, error) {
+	b, err := Bytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(b) {
+		return nil, errors.New("jsonify: YAMLBytes of invalid JSON")
+	}
+	return appendYAML(nil, bytes.TrimSpace(b), 0)
+}
+
+// appendYAML appends the JSON value raw to b as a YAML block node, whose
+// first line starts at the end of b and other lines at indent. raw has no
+// leading or trailing space.
+func appendYAML(b []byte, raw []byte, indent int) ([]byte, error) {
+	switch raw[0] {
+	case '{':
+		keys, values, err := objectMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return append(b, "{}\n"...), nil
+		}
+		for i, key := range keys {
+			if i > 0 {
+				b = appendIndent(b, indent)
+			}
+			b = append(appendYAMLString(b, key), ':')
+			if isYAMLScalar(values[i]) {
+				b = append(b, ' ')
+			} else {
+				b = appendIndent(append(b, '\n'), indent+2)
+			}
+			if b, err = appendYAML(b, values[i], indent+2); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '[':
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return append(b, "[]\n"...), nil
+		}
+		for i, elem := range elems {
+			if i > 0 {
+				b = appendIndent(b, indent)
+			}
+			var err error
+			if b, err = appendYAML(append(b, "- "...), elem, indent+2); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case '"':
+		s, err := unquote(raw)
+		if err != nil {
+			return nil, err
+		}
+		return append(appendYAMLString(b, s), '\n'), nil
+	}
+	return append(append(b, raw...), '\n'), nil
+}
+
+// isYAMLScalar reports whether the JSON value raw is written on the line of
+// its key, as a scalar or an empty object or array.
+func isYAMLScalar(raw []byte) bool {
+	switch raw[0] {
+	case '{', '[':
+		return raw[skipSpace(raw, 1)] == raw[0]+2 // '}' or ']'
+	}
+	return true
+}
+
+func appendIndent(b []byte, indent int) []byte {
+	for range indent {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// appendYAMLString appends s to b as a plain scalar if YAML reads it as that
+// string, or else as a JSON string.
+func appendYAMLString(b []byte, s string) []byte {
+	if isPlainYAML(s) {
+		return append(b, s...)
+	}
+	return appendString(b, s)
+}
+
+// isPlainYAML reports whether s can be written as a plain scalar: it starts
+// with a letter or an underscore, has only letters, digits, spaces and
+// "_./-", does not end with a space, and is not a boolean or null of any
+// YAML version.
+func isPlainYAML(s string) bool {
+	if s == "" || s[len(s)-1] == ' ' {
+		return false
+	}
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_':
+		case i == 0:
+			return false
+		case '0' <= c && c <= '9', c == ' ', c == '.', c == '/', c == '-':
+		default:
+			return false
+		}
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "null", "yes", "no", "on", "off", "y", "n":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package jsonify_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/jsonify"
+)
+
+func ExampleYAMLBytes() {
+	type server struct {
+		Host  string            `json:"host"`
+		Ports []int             `json:"ports"`
+		Env   map[string]string `json:"env"`
+	}
+	b, err := jsonify.YAMLBytes(server{Host: "localhost", Ports: []int{80, 443}, Env: map[string]string{"MODE": "on", "DEBUG": "1"}})
+	fmt.Print(string(b))
+	fmt.Println(err)
+	// Output:
+	// host: localhost
+	// ports:
+	//   - 80
+	//   - 443
+	// env:
+	//   DEBUG: "1"
+	//   MODE: "on"
+	// <nil>
+}
+
+func TestYAMLBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		opts    []jsonify.Option
+		want    string
+		wantErr bool
+	}{
+		{name: "null", v: nil, want: "null\n"},
+		{name: "number", v: 1.5, want: "1.5\n"},
+		{name: "plain string", v: "hello world", want: "hello world\n"},
+		{name: "empty object", v: map[string]int{}, want: "{}\n"},
+		{name: "empty array", v: []int{}, want: "[]\n"},
+		{
+			name: "quoted strings",
+			v: []string{
+				"", "yes", "No", "null", "~", "123", "1e3", "-x", " x", "x ", "a: b", "a #b",
+				"line\nbreak", "*ref", "é", "a/b.c-d_e",
+			},
+			want: "- \"\"\n- \"yes\"\n- \"No\"\n- \"null\"\n- \"~\"\n- \"123\"\n- \"1e3\"\n- \"-x\"\n- \" x\"\n" +
+				"- \"x \"\n- \"a: b\"\n- \"a #b\"\n- \"line\\nbreak\"\n- \"*ref\"\n- \"é\"\n- a/b.c-d_e\n",
+		},
+		{
+			name: "nesting",
+			v: map[string]any{
+				"list":   []any{map[string]any{"a": 1, "b": []int{}}, []int{1, 2}, map[string]any{}},
+				"object": map[string]any{"inner": map[string]any{"x": true}},
+				"key: 1": nil,
+			},
+			want: "\"key: 1\": null\n" +
+				"list:\n" +
+				"  - a: 1\n" +
+				"    b: []\n" +
+				"  - - 1\n" +
+				"    - 2\n" +
+				"  - {}\n" +
+				"object:\n" +
+				"  inner:\n" +
+				"    x: true\n",
+		},
+		{
+			name: "raw message",
+			v:    map[string]any{"raw": json.RawMessage(`{ "z" : [ 1 , "a" ] }`)},
+			want: "raw:\n  z:\n    - 1\n    - a\n",
+		},
+		{
+			name: "indent option",
+			v:    map[string]any{"a": []int{1}, "b": map[string]int{}},
+			opts: []jsonify.Option{jsonify.WithIndent("", "  ")},
+			want: "a:\n  - 1\nb: {}\n",
+		},
+		{name: "invalid raw message", v: json.RawMessage(`{`), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonify.YAMLBytes(tt.v, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("YAMLBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("YAMLBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}